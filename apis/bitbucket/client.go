@@ -4,10 +4,12 @@ import (
 	"fmt"
 	"log"
 	"net/url"
-	"os"
+	"simple-git-terminal/auth"
 	"simple-git-terminal/state"
 	"simple-git-terminal/types"
+	"simple-git-terminal/util"
 	"strings"
+	"time"
 
 	"github.com/go-resty/resty/v2"
 )
@@ -15,72 +17,99 @@ import (
 // Bitbucket API details
 const (
 	BitbucketBaseURL                = "https://api.bitbucket.org/2.0"
-	BitbucketEnvTokenName           = "BITBUCKET_AUTH_TOKEN"
-	BitbucketEnvAppPasswordName     = "BITBUCKET_APP_PASSWORD"
-	BitbucketEnvAppPasswordUsername = "BITBUCKET_APP_USERNAME"
+	BitbucketWebURL                 = "https://bitbucket.org"
+	BitbucketEnvTokenName           = auth.EnvToken
+	BitbucketEnvAppPasswordName     = auth.EnvAppPassword
+	BitbucketEnvAppPasswordUsername = auth.EnvAppPasswordUsername
 )
 
 var client *resty.Client
 
-func getAuthToken(tokenString string) string {
-	token := os.Getenv(tokenString)
-	if token == "" {
-		log.Printf("[CLIENT] Environment variable %s is not set will try using basic authentication with app password", tokenString)
-	}
-	return token
-}
-
-// Helper function to create a Resty client with authentication
+// Helper function to create a Resty client with authentication, from the environment or `bbpr auth login`
 func createClient() *resty.Client {
 	if client != nil {
 		return client
 	}
 
 	client = resty.New()
-
-	authToken := getAuthToken(BitbucketEnvTokenName)
-	if authToken != "" {
-		client.SetAuthToken(authToken)
-	} else {
-		username := os.Getenv(BitbucketEnvAppPasswordUsername)
-		appPassword := os.Getenv(BitbucketEnvAppPasswordName)
-
-		if username != "" && appPassword != "" {
-			client.SetBasicAuth(username, appPassword)
-		} else {
-			log.Fatalf("Error: Missing authentication credentials. Please check your environment variables.")
-		}
+	if err := auth.Configure(client, auth.CloudHost); err != nil {
+		// Requests fail with 401 and surface the problem where they are made
+		log.Printf("[CLIENT] %v", err)
 	}
-
 	return client
 }
 
-func FetchPR(id int) *types.PR {
+func FetchPR(repo state.RepoContext, id int) (*types.PR, error) {
 	client := createClient()
-	url := fmt.Sprintf("%s/repositories/%s/%s/pullrequests/%d", BitbucketBaseURL, state.Workspace, state.Repo, id)
+	url := fmt.Sprintf("%s/repositories/%s/%s/pullrequests/%d", BitbucketBaseURL, repo.Workspace, repo.Repo, id)
 
 	resp, err := client.R().
 		SetResult(&types.PR{}).
 		Get(url)
 	if err != nil {
-		log.Fatalf("Error fetching PRs: %v", err)
+		return nil, fmt.Errorf("error fetching PR #%d: %w", id, err)
 	}
 	if resp.StatusCode() != 200 {
-		log.Fatalf("Unexpected status code: %d. Response body: %s", resp.StatusCode(), string(resp.Body()))
+		return nil, fmt.Errorf("unexpected status code %d fetching PR #%d: %s", resp.StatusCode(), id, string(resp.Body()))
 	}
 
 	pr := resp.Result().(*types.PR)
-	return pr
+	return pr, nil
+}
+
+// UpdatePR changes the title, description, destination branch and draft flag of a PR
+func UpdatePR(repo state.RepoContext, id int, update types.PRUpdate) (*types.PR, error) {
+	body := map[string]interface{}{
+		"title":       update.Title,
+		"description": update.Description,
+		"destination": map[string]interface{}{"branch": map[string]string{"name": update.Destination}},
+		"draft":       update.Draft,
+	}
+
+	resp, err := createClient().R().
+		SetBody(body).
+		SetResult(&types.PR{}).
+		Put(fmt.Sprintf("%s/repositories/%s/%s/pullrequests/%d", BitbucketBaseURL, repo.Workspace, repo.Repo, id))
+	if err != nil {
+		return nil, fmt.Errorf("error updating PR #%d: %w", id, err)
+	}
+	if resp.StatusCode() != 200 {
+		return nil, fmt.Errorf("unexpected status code %d updating PR #%d: %s", resp.StatusCode(), id, string(resp.Body()))
+	}
+	return resp.Result().(*types.PR), nil
+}
+
+// FetchBranchNames lists the branches of repo, most recently updated first
+func FetchBranchNames(repo state.RepoContext) ([]string, error) {
+	resp, err := createClient().R().
+		SetQueryParams(map[string]string{"pagelen": "100", "sort": "-target.date", "fields": "values.name"}).
+		SetResult(&types.BitbucketBranchResponse{}).
+		Get(fmt.Sprintf("%s/repositories/%s/%s/refs/branches", BitbucketBaseURL, repo.Workspace, repo.Repo))
+	if err != nil {
+		return nil, fmt.Errorf("error fetching branches: %w", err)
+	}
+	if resp.StatusCode() != 200 {
+		return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode(), string(resp.Body()))
+	}
+
+	var names []string
+	for _, branch := range resp.Result().(*types.BitbucketBranchResponse).Values {
+		names = append(names, branch.Name)
+	}
+	return names, nil
 }
 
 // Make query using BuildQuery method....
-func FetchPRsByQuery(query string) ([]types.PR, types.Pagination) {
+func FetchPRsByQuery(repo state.RepoContext, query string) ([]types.PR, types.Pagination, error) {
 	client := createClient()
 	encodedQuery := url.QueryEscape(query) // Properly encode the query string
-	fields := url.QueryEscape("+values.participants,-values.description,-values.summary")
+	fields := url.QueryEscape(prListFields)
 
 	url := fmt.Sprintf("%s/repositories/%s/%s/pullrequests?fields=%s&q=%s&page=%d",
-		BitbucketBaseURL, state.Workspace, state.Repo, fields, encodedQuery, state.Pagination.Page)
+		BitbucketBaseURL, repo.Workspace, repo.Repo, fields, encodedQuery, state.Pagination.Page)
+	if state.PRSort != "" {
+		url += "&sort=" + state.PRSort
+	}
 	url = strings.ReplaceAll(url, "+", "%20") // TODO: Fix encoding issue
 
 	log.Printf("[CLIENT] Fetching PRs with query...%v", url)
@@ -88,27 +117,25 @@ func FetchPRsByQuery(query string) ([]types.PR, types.Pagination) {
 		SetResult(&types.BitbucketPRResponse{}).
 		Get(url)
 	if err != nil {
-		log.Fatalf("Error fetching PRs: %v", err)
-		return nil, types.Pagination{}
+		return nil, types.Pagination{}, fmt.Errorf("error fetching PRs: %w", err)
 	}
 	if resp.StatusCode() != 200 {
-		log.Fatalf("Unexpected status code: %d. Response body: %s", resp.StatusCode(), string(resp.Body()))
-		return nil, types.Pagination{}
+		return nil, types.Pagination{}, fmt.Errorf("unexpected status code %d fetching PRs: %s", resp.StatusCode(), string(resp.Body()))
 	}
 
 	response := resp.Result().(*types.BitbucketPRResponse)
-	return response.Values, response.Pagination
+	return response.Values, response.Pagination, nil
 }
 
-func FetchBitbucketDiffContent(id int, filePath string) (string, error) {
+func FetchBitbucketDiffContent(repo state.RepoContext, id int, filePath string) (string, error) {
 	client := createClient()
 
 	resp, err := client.R().
 		SetHeader("Accept", "application/json").
 		Get(fmt.Sprintf("%s/repositories/%s/%s/pullrequests/%d/diff?path=%s",
 			BitbucketBaseURL,
-			state.Workspace,
-			state.Repo,
+			repo.Workspace,
+			repo.Repo,
 			id,
 			filePath,
 		))
@@ -123,81 +150,292 @@ func FetchBitbucketDiffContent(id int, filePath string) (string, error) {
 	return string(resp.Body()), nil
 }
 
+// FetchFileAtCommit fetches the raw content of a file at the given commit through the src API
+func FetchFileAtCommit(repo state.RepoContext, commitHash string, filePath string) (string, error) {
+	client := createClient()
+
+	resp, err := client.R().
+		Get(fmt.Sprintf("%s/repositories/%s/%s/src/%s/%s",
+			BitbucketBaseURL,
+			repo.Workspace,
+			repo.Repo,
+			commitHash,
+			util.EscapePath(filePath),
+		))
+	if err != nil {
+		return "", fmt.Errorf("error fetching file content: %w", err)
+	}
+
+	if resp.StatusCode() != 200 {
+		return "", fmt.Errorf("unexpected status code %d: %s", resp.StatusCode(), string(resp.Body()))
+	}
+
+	return string(resp.Body()), nil
+}
+
 // TODO: Same here maybe this endpoint should be made optional for user and just do local diff for faster diff?
-func FetchBitbucketDiffstat(id int) []types.DiffstatEntry {
+func FetchBitbucketDiffstat(repo state.RepoContext, id int) ([]types.DiffstatEntry, error) {
 	client := createClient()
 
 	// Fetching the diffstat for the given pull request ID
 	resp, err := client.R().
 		SetResult(&types.DiffstatResponse{}).
-		Get(fmt.Sprintf("%s/repositories/%s/%s/pullrequests/%d/diffstat", BitbucketBaseURL, state.Workspace, state.Repo, id))
+		Get(fmt.Sprintf("%s/repositories/%s/%s/pullrequests/%d/diffstat", BitbucketBaseURL, repo.Workspace, repo.Repo, id))
 	if err != nil {
-		log.Fatalf("Error fetching diffstat: %v", err)
+		return nil, fmt.Errorf("error fetching diffstat of PR #%d: %w", id, err)
 	}
 
 	if resp.StatusCode() != 200 {
-		log.Fatalf("Error: Unexpected status code %d", resp.StatusCode())
+		return nil, fmt.Errorf("unexpected status code %d fetching diffstat of PR #%d", resp.StatusCode(), id)
 	}
 
 	response := resp.Result().(*types.DiffstatResponse)
-	return response.Values
+	return response.Values, nil
+}
+
+// FetchDiffstatBetween fetches the plain two-dot diffstat from oldCommit to newCommit
+func FetchDiffstatBetween(repo state.RepoContext, newCommit string, oldCommit string) ([]types.DiffstatEntry, error) {
+	client := createClient()
+
+	resp, err := client.R().
+		SetResult(&types.DiffstatResponse{}).
+		Get(fmt.Sprintf("%s/repositories/%s/%s/diffstat/%s..%s?topic=false&pagelen=500",
+			BitbucketBaseURL, repo.Workspace, repo.Repo, newCommit, oldCommit))
+	if err != nil {
+		return nil, fmt.Errorf("error fetching diffstat between commits: %w", err)
+	}
+
+	if resp.StatusCode() != 200 {
+		return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode(), string(resp.Body()))
+	}
+
+	response := resp.Result().(*types.DiffstatResponse)
+	return response.Values, nil
+}
+
+// FetchPRCommits fetches the commits of a PR, newest first
+func FetchPRCommits(repo state.RepoContext, id int) ([]types.Commit, error) {
+	client := createClient()
+
+	var commits []types.Commit
+	url := fmt.Sprintf("%s/repositories/%s/%s/pullrequests/%d/commits?pagelen=100", BitbucketBaseURL, repo.Workspace, repo.Repo, id)
+
+	for url != "" {
+		resp, err := client.R().
+			SetResult(&types.BitbucketCommitsResponse{}).
+			Get(url)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching commits: %w", err)
+		}
+		if resp.StatusCode() != 200 {
+			return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode(), string(resp.Body()))
+		}
+		response := resp.Result().(*types.BitbucketCommitsResponse)
+		commits = append(commits, response.Values...)
+		url = response.Next
+	}
+	return commits, nil
+}
+
+// FetchCommitDiff fetches the diff of a single commit, or the plain two-dot diff from oldCommit to newCommit
+// when oldCommit is given
+func FetchCommitDiff(repo state.RepoContext, newCommit string, oldCommit string) (string, error) {
+	client := createClient()
+
+	spec := newCommit
+	if oldCommit != "" {
+		spec = fmt.Sprintf("%s..%s", newCommit, oldCommit)
+	}
+
+	resp, err := client.R().
+		Get(fmt.Sprintf("%s/repositories/%s/%s/diff/%s?topic=false", BitbucketBaseURL, repo.Workspace, repo.Repo, spec))
+	if err != nil {
+		return "", fmt.Errorf("error fetching commit diff: %w", err)
+	}
+
+	if resp.StatusCode() != 200 {
+		return "", fmt.Errorf("unexpected status code %d: %s", resp.StatusCode(), string(resp.Body()))
+	}
+
+	return string(resp.Body()), nil
 }
 
 // TODO: Maybe this endpoint should be able optional for end user if they want to use network? It is pretty slow
-func FetchBitbucketDiff(id int) string {
+func FetchBitbucketDiff(repo state.RepoContext, id int) (string, error) {
 	client := createClient()
 
 	// Fetching the diff for the given pull request ID
 	resp, err := client.R().
-		Get(fmt.Sprintf("%s/repositories/%s/%s/pullrequests/%d/diff", BitbucketBaseURL, state.Workspace, state.Repo, id))
+		Get(fmt.Sprintf("%s/repositories/%s/%s/pullrequests/%d/diff", BitbucketBaseURL, repo.Workspace, repo.Repo, id))
 	if err != nil {
-		log.Fatalf("Error fetching diffstat: %v", err)
+		return "", fmt.Errorf("error fetching diff of PR #%d: %w", id, err)
 	}
 
 	// Check if the response is successful (e.g., status code 200)
 	if resp.StatusCode() != 200 {
-		log.Fatalf("Error: Unexpected status code %d", resp.StatusCode())
+		return "", fmt.Errorf("unexpected status code %d fetching diff of PR #%d", resp.StatusCode(), id)
 	}
 
 	// Return the raw diff content (response body is the diff)
-	return string(resp.Body())
+	return string(resp.Body()), nil
+}
+
+// Fetches the activities of a PR from Bitbucket, newest first
+func FetchBitbucketActivities(repo state.RepoContext, id int) ([]types.Activity, error) {
+	client := createClient()
+
+	var activities []types.Activity
+	url := fmt.Sprintf("%s/repositories/%s/%s/pullrequests/%d/activity?pagelen=50", BitbucketBaseURL, repo.Workspace, repo.Repo, id)
+
+	// Follow pagination, approvals of older revisions are often beyond the first page
+	for url != "" {
+		resp, err := client.R().
+			SetResult(&types.BitbucketActivityResponse{}).
+			Get(url)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching activities of PR #%d: %w", id, err)
+		}
+		if resp.StatusCode() != 200 {
+			return nil, fmt.Errorf("unexpected status code %d fetching activities of PR #%d", resp.StatusCode(), id)
+		}
+		response := resp.Result().(*types.BitbucketActivityResponse)
+		activities = append(activities, response.Values...)
+		url = response.Next
+	}
+	return activities, nil
 }
 
-// Fetches recent activities from Bitbucket
-func FetchBitbucketActivities(id int) []types.Activity {
+func FetchBitbucketComments(repo state.RepoContext, id int) ([]types.Comment, error) {
 	client := createClient()
 
+	var comments []types.Comment
+	url := fmt.Sprintf("%s/repositories/%s/%s/pullrequests/%d/comments?pagelen=100", BitbucketBaseURL, repo.Workspace, repo.Repo, id)
+
+	// Follow pagination, a busy PR easily has more comments than fit on one page
+	for url != "" {
+		resp, err := client.R().
+			SetResult(&types.BitbucketCommentsResponse{}).
+			Get(url)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching comments of PR #%d: %w", id, err)
+		}
+		if resp.StatusCode() != 200 {
+			return nil, fmt.Errorf("unexpected status code %d fetching comments of PR #%d", resp.StatusCode(), id)
+		}
+		response := resp.Result().(*types.BitbucketCommentsResponse)
+		comments = append(comments, response.Values...)
+		url = response.Next
+	}
+	return comments, nil
+}
+
+// CreatePRComment adds a comment to a PR. Inline comments are anchored with inline, general comments pass nil.
+// Pending comments stay drafts only visible to their author until published.
+func CreatePRComment(repo state.RepoContext, id int, raw string, inline *types.Inline, pending bool) (*types.Comment, error) {
+	client := createClient()
+
+	body := map[string]interface{}{
+		"content": map[string]string{"raw": raw},
+		"pending": pending,
+	}
+	if inline != nil {
+		anchor := map[string]interface{}{"path": inline.Path}
+		if inline.From > 0 {
+			anchor["from"] = inline.From
+		}
+		if inline.To > 0 {
+			anchor["to"] = inline.To
+		}
+		body["inline"] = anchor
+	}
+
 	resp, err := client.R().
-		SetResult(&types.BitbucketActivityResponse{}).
-		Get(fmt.Sprintf("%s/repositories/%s/%s/pullrequests/%d/activity", BitbucketBaseURL, state.Workspace, state.Repo, id))
+		SetBody(body).
+		SetResult(&types.Comment{}).
+		Post(fmt.Sprintf("%s/repositories/%s/%s/pullrequests/%d/comments", BitbucketBaseURL, repo.Workspace, repo.Repo, id))
 	if err != nil {
-		log.Fatalf("Error fetching activities: %v", err)
+		return nil, fmt.Errorf("error creating comment: %w", err)
+	}
+
+	if resp.StatusCode() != 201 {
+		return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode(), string(resp.Body()))
 	}
-	activityResponse := resp.Result().(*types.BitbucketActivityResponse)
-	return activityResponse.Values
+
+	return resp.Result().(*types.Comment), nil
 }
 
-func FetchBitbucketComments(id int) []types.Comment {
+// UpdatePRComment changes the text of a comment and whether it is still a pending draft
+func UpdatePRComment(repo state.RepoContext, id int, commentID int, raw string, pending bool) (*types.Comment, error) {
 	client := createClient()
 
 	resp, err := client.R().
-		SetResult(&types.BitbucketCommentsResponse{}).
-		Get(fmt.Sprintf("%s/repositories/%s/%s/pullrequests/%d/comments", BitbucketBaseURL, state.Workspace, state.Repo, id))
+		SetBody(map[string]interface{}{
+			"content": map[string]string{"raw": raw},
+			"pending": pending,
+		}).
+		SetResult(&types.Comment{}).
+		Put(fmt.Sprintf("%s/repositories/%s/%s/pullrequests/%d/comments/%d", BitbucketBaseURL, repo.Workspace, repo.Repo, id, commentID))
 	if err != nil {
-		log.Fatalf("Error fetching comments: %v", err)
+		return nil, fmt.Errorf("error updating comment: %w", err)
 	}
-	response := resp.Result().(*types.BitbucketCommentsResponse)
-	return response.Values
+
+	if resp.StatusCode() != 200 {
+		return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode(), string(resp.Body()))
+	}
+
+	return resp.Result().(*types.Comment), nil
 }
 
-func FetchCurrentUser() *types.User {
+func DeletePRComment(repo state.RepoContext, id int, commentID int) error {
+	client := createClient()
+
+	resp, err := client.R().
+		Delete(fmt.Sprintf("%s/repositories/%s/%s/pullrequests/%d/comments/%d", BitbucketBaseURL, repo.Workspace, repo.Repo, id, commentID))
+	if err != nil {
+		return fmt.Errorf("error deleting comment: %w", err)
+	}
+
+	if resp.StatusCode() != 204 {
+		return fmt.Errorf("unexpected status code %d: %s", resp.StatusCode(), string(resp.Body()))
+	}
+
+	return nil
+}
+
+// ApprovePR approves the PR as the current user
+func ApprovePR(repo state.RepoContext, id int) error {
+	return postPRAction(repo, id, "approve")
+}
+
+// RequestChangesPR requests changes on the PR as the current user
+func RequestChangesPR(repo state.RepoContext, id int) error {
+	return postPRAction(repo, id, "request-changes")
+}
+
+func postPRAction(repo state.RepoContext, id int, action string) error {
+	client := createClient()
+
+	resp, err := client.R().
+		Post(fmt.Sprintf("%s/repositories/%s/%s/pullrequests/%d/%s", BitbucketBaseURL, repo.Workspace, repo.Repo, id, action))
+	if err != nil {
+		return fmt.Errorf("error calling %s: %w", action, err)
+	}
+
+	if resp.StatusCode() != 200 {
+		return fmt.Errorf("unexpected status code %d for %s: %s", resp.StatusCode(), action, string(resp.Body()))
+	}
+
+	return nil
+}
+
+func FetchCurrentUser() (*types.User, error) {
 	client := createClient()
 
 	resp, err := client.R().
 		SetResult(&types.User{}).
 		Get(fmt.Sprintf("%s/user", BitbucketBaseURL))
 	if err != nil {
-		log.Fatalf("Error fetching user: %v", err)
+		return nil, fmt.Errorf("error fetching user: %w", err)
 	}
 	userResponse := resp.Result().(*types.User)
 	if userResponse == nil {
@@ -206,30 +444,91 @@ func FetchCurrentUser() *types.User {
 		log.Printf("Current active user => %v", userResponse)
 	}
 
-	return userResponse
+	return userResponse, nil
+}
+
+// PRFilter is everything the PR list can be filtered on. BuildQuery fills it from the global filter state,
+// saved views build their own.
+type PRFilter struct {
+	States      []string // OPEN, MERGED, DECLINED, SUPERSEDED; empty means any
+	Author      bool     // Only PRs I authored
+	Reviewer    bool     // Only PRs I am a reviewer on
+	Participant bool     // Only PRs I participated in (commented, approved or reviewed)
+	OpenTasks   bool     // Only PRs I authored with open tasks, which are mine to do
+	Involved    bool     // Only PRs I authored or am a reviewer on
+	Destination string   // Destination branch name
+	Source      string   // Source branch name
+	Search      string   // Search bar query syntax, see TranslatePRQuery
+}
+
+// hasRole reports whether the filter is limited to PRs I have a role in
+func (f PRFilter) hasRole() bool {
+	return f.Author || f.Reviewer || f.Participant || f.OpenTasks || f.Involved
 }
 
 func BuildQuery(searchTerm string) string {
+	return BuildFilterQuery(CurrentFilter(searchTerm), state.CurrentUser)
+}
+
+// CurrentFilter returns the filter of the PR list from the filter panel, branch filter and search bar
+func CurrentFilter(searchTerm string) PRFilter {
+	return PRFilter{
+		States:      BuildFilterStates(),
+		Author:      state.PRStatusFilter.IAmAuthor,
+		Reviewer:    state.PRStatusFilter.IAmReviewer,
+		Participant: state.PRStatusFilter.IAmParticipant,
+		OpenTasks:   state.PRStatusFilter.MyOpenTasks,
+		Destination: state.PRDestinationBranch,
+		Search:      searchTerm,
+	}
+}
+
+// BuildFilterQuery translates a PRFilter into BBQL
+func BuildFilterQuery(filter PRFilter, me *types.User) string {
 	var filters []string
 
-	stateFilter := buildStateFilter()
-	if stateFilter != "" {
-		filters = append(filters, stateFilter)
+	var stateFilters []string
+	for _, prState := range filter.States {
+		stateFilters = append(stateFilters, fmt.Sprintf("state=\"%s\"", prState))
+	}
+	if len(stateFilters) > 0 {
+		// Combine the state filters into a single string with OR
+		filters = append(filters, "("+strings.Join(stateFilters, " OR ")+")")
 	}
 
-	// Add author filter if IAmReviewing is true
-	if state.CurrentUser.UUID != "" && state.PRStatusFilter.IAmAuthor {
-		authorFilter := fmt.Sprintf("author.uuid=\"%s\"", state.CurrentUser.UUID)
-		filters = append(filters, authorFilter)
+	if me != nil && me.UUID != "" {
+		if filter.Author {
+			filters = append(filters, fmt.Sprintf("author.uuid=\"%s\"", me.UUID))
+		}
+		if filter.Reviewer {
+			filters = append(filters, fmt.Sprintf("reviewers.uuid=\"%s\"", me.UUID))
+		}
+		if filter.Participant {
+			filters = append(filters, fmt.Sprintf("participants.user.uuid=\"%s\"", me.UUID))
+		}
+		if filter.OpenTasks {
+			if !filter.Author {
+				filters = append(filters, fmt.Sprintf("author.uuid=\"%s\"", me.UUID))
+			}
+			filters = append(filters, "task_count>0")
+		}
+		if filter.Involved {
+			filters = append(filters, fmt.Sprintf("(author.uuid=\"%s\" OR reviewers.uuid=\"%s\")", me.UUID, me.UUID))
+		}
 	}
 
-	if state.CurrentUser.UUID != "" && state.PRStatusFilter.IAmReviewer {
-		reviewersFilter := fmt.Sprintf("reviewers.uuid=\"%s\"", state.CurrentUser.UUID)
-		filters = append(filters, reviewersFilter)
+	if filter.Destination != "" {
+		filters = append(filters, "destination.branch.name="+quoteBBQL(filter.Destination))
 	}
-	// Add search term filter
-	if searchTerm != "" {
-		searchFilter := fmt.Sprintf("(description~\"%s\" OR title~\"%s\")", searchTerm, searchTerm)
+	if filter.Source != "" {
+		filters = append(filters, "source.branch.name="+quoteBBQL(filter.Source))
+	}
+
+	// Add search term filter, written in the search bar query syntax
+	searchFilter, err := TranslatePRQuery(filter.Search, me, time.Now())
+	if err != nil {
+		log.Printf("Ignoring invalid search %q: %v", filter.Search, err)
+	} else if searchFilter != "" {
 		filters = append(filters, searchFilter)
 	}
 
@@ -240,33 +539,123 @@ func BuildQuery(searchTerm string) string {
 	return finalQuery
 }
 
-func buildStateFilter() string {
+// BuildFilterStates returns the PR states checked in the filter panel
+func BuildFilterStates() []string {
 	// Initialize state filters array
 	var stateFilters []string
 
 	// Add individual state filters (Open, Merged, Declined)
 	if state.PRStatusFilter.Merged {
-		stateFilters = append(stateFilters, "state=\"MERGED\"")
+		stateFilters = append(stateFilters, "MERGED")
 	}
 	if state.PRStatusFilter.Declined {
-		stateFilters = append(stateFilters, "state=\"DECLINED\"")
+		stateFilters = append(stateFilters, "DECLINED")
 	}
 	if state.PRStatusFilter.Open {
-		stateFilters = append(stateFilters, "state=\"OPEN\"")
+		stateFilters = append(stateFilters, "OPEN")
 	}
 
-	// Combine the state filters into a single string with OR
-	if len(stateFilters) > 0 {
-		return strings.Join(stateFilters, " OR ")
+	return stateFilters
+}
+
+// SearchPRs fetches up to limit PRs matching a BBQL query, independent of the PR list pagination.
+// Bitbucket returns at most 50 PRs per page, so larger limits follow the next links.
+func SearchPRs(repo state.RepoContext, query string, limit int) ([]types.PR, error) {
+	client := createClient()
+
+	var prs []types.PR
+	request := client.R().
+		SetQueryParams(map[string]string{
+			"q":       query,
+			"pagelen": fmt.Sprintf("%d", min(limit, 50)),
+		})
+	url := fmt.Sprintf("%s/repositories/%s/%s/pullrequests", BitbucketBaseURL, repo.Workspace, repo.Repo)
+	for url != "" && len(prs) < limit {
+		resp, err := request.
+			SetResult(&types.BitbucketPRResponse{}).
+			Get(url)
+		if err != nil {
+			return nil, fmt.Errorf("error searching PRs: %w", err)
+		}
+
+		if resp.StatusCode() != 200 {
+			return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode(), string(resp.Body()))
+		}
+
+		response := resp.Result().(*types.BitbucketPRResponse)
+		prs = append(prs, response.Values...)
+		// The next link already carries the query
+		url = response.Next
+		request = client.R()
+	}
+	return prs[:min(limit, len(prs))], nil
+}
+
+// CountPRs returns how many PRs match a BBQL query without fetching them
+func CountPRs(repo state.RepoContext, query string) (int, error) {
+	client := createClient()
+
+	resp, err := client.R().
+		SetQueryParams(map[string]string{
+			"q":       query,
+			"pagelen": "1",
+			"fields":  "size",
+		}).
+		SetResult(&types.BitbucketPRResponse{}).
+		Get(fmt.Sprintf("%s/repositories/%s/%s/pullrequests", BitbucketBaseURL, repo.Workspace, repo.Repo))
+	if err != nil {
+		return 0, fmt.Errorf("error counting PRs: %w", err)
+	}
+
+	if resp.StatusCode() != 200 {
+		return 0, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode(), string(resp.Body()))
+	}
+
+	return resp.Result().(*types.BitbucketPRResponse).Size, nil
+}
+
+// FetchPRStatuses returns the build statuses reported on the latest commit of a PR
+func FetchPRStatuses(repo state.RepoContext, id int) ([]types.CommitStatus, error) {
+	client := createClient()
+
+	resp, err := client.R().
+		SetQueryParam("pagelen", "50").
+		SetResult(&types.BitbucketCommitStatusResponse{}).
+		Get(fmt.Sprintf("%s/repositories/%s/%s/pullrequests/%d/statuses", BitbucketBaseURL, repo.Workspace, repo.Repo, id))
+	if err != nil {
+		return nil, fmt.Errorf("error fetching statuses of PR #%d: %w", id, err)
+	}
+
+	if resp.StatusCode() != 200 {
+		return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode(), string(resp.Body()))
+	}
+
+	return resp.Result().(*types.BitbucketCommitStatusResponse).Values, nil
+}
+
+// FetchRecentActivities returns the latest page of PR activities, newest first
+func FetchRecentActivities(repo state.RepoContext, id int) ([]types.Activity, error) {
+	client := createClient()
+
+	resp, err := client.R().
+		SetResult(&types.BitbucketActivityResponse{}).
+		Get(fmt.Sprintf("%s/repositories/%s/%s/pullrequests/%d/activity", BitbucketBaseURL, repo.Workspace, repo.Repo, id))
+	if err != nil {
+		return nil, fmt.Errorf("error fetching activities: %w", err)
+	}
+
+	if resp.StatusCode() != 200 {
+		return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode(), string(resp.Body()))
 	}
 
-	return ""
+	return resp.Result().(*types.BitbucketActivityResponse).Values, nil
 }
 
 // Pipelines
-func FetchPipelinesByQuery(query string) ([]types.PipelineResponse, types.Pagination) {
-	if state.PipelineUIState.IsNetworkMockMode {
-		return TestFetchPipelinesByQuery(query)
+func FetchPipelinesByQuery(query string) ([]types.PipelineResponse, types.Pagination, error) {
+	if state.IsNetworkMockMode() {
+		pipelines, pagination := TestFetchPipelinesByQuery(query)
+		return pipelines, pagination, nil
 	}
 
 	client := createClient()
@@ -293,24 +682,69 @@ func FetchPipelinesByQuery(query string) ([]types.PipelineResponse, types.Pagina
 		SetResult(&types.BitbucketPipelineResponse{}).
 		Get(url)
 	if err != nil {
-		log.Fatalf("Error fetching pipelines: %v", err)
-		return nil, types.Pagination{}
+		return nil, types.Pagination{}, fmt.Errorf("error fetching pipelines: %w", err)
 	}
 
 	if resp.StatusCode() != 200 {
-		log.Fatalf("Unexpected status code: %d. Response body: %s", resp.StatusCode(), string(resp.Body()))
-		return nil, types.Pagination{}
+		return nil, types.Pagination{}, fmt.Errorf("unexpected status code %d fetching pipelines: %s", resp.StatusCode(), string(resp.Body()))
 	}
 
 	response := resp.Result().(*types.BitbucketPipelineResponse)
 	log.Printf("[INFO] Total pipelines: %d", len(response.Values))
 
-	return response.Values, response.Pagination
+	return response.Values, response.Pagination, nil
 }
 
-func FetchPipeline(pipelineUUID string) *types.PipelineResponse {
-	if state.PipelineUIState.IsNetworkMockMode {
-		return TestFetchPipeline(pipelineUUID)
+// FetchRecentPipelines returns up to limit of the newest pipelines of repo. Bitbucket returns at most
+// 100 pipelines per page, so larger limits fetch several pages.
+func FetchRecentPipelines(repo state.RepoContext, limit int) ([]types.PipelineResponse, error) {
+	if state.IsNetworkMockMode() {
+		pipelines, _ := TestFetchPipelinesByQuery("")
+		return pipelines, nil
+	}
+
+	client := createClient()
+
+	var pipelines []types.PipelineResponse
+	pagelen := min(limit, 100)
+	for page := 1; len(pipelines) < limit; page++ {
+		resp, err := client.R().
+			SetQueryParams(map[string]string{
+				"sort":    "-created_on",
+				"pagelen": fmt.Sprintf("%d", pagelen),
+				"page":    fmt.Sprintf("%d", page),
+			}).
+			SetResult(&types.BitbucketPipelineResponse{}).
+			Get(fmt.Sprintf("%s/repositories/%s/%s/pipelines", BitbucketBaseURL, repo.Workspace, repo.Repo))
+		if err != nil {
+			return nil, fmt.Errorf("error fetching pipelines: %w", err)
+		}
+
+		if resp.StatusCode() != 200 {
+			return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode(), string(resp.Body()))
+		}
+
+		response := resp.Result().(*types.BitbucketPipelineResponse)
+		pipelines = append(pipelines, response.Values...)
+		if len(response.Values) < pagelen || page*pagelen >= response.Size {
+			break
+		}
+	}
+	return pipelines[:min(limit, len(pipelines))], nil
+}
+
+// PipelineWebURL is the address of a pipeline in the web UI, or of one of its steps when stepUUID is set
+func PipelineWebURL(buildNumber int, stepUUID string) string {
+	link := fmt.Sprintf("%s/%s/%s/pipelines/results/%d", BitbucketWebURL, state.Workspace, state.Repo, buildNumber)
+	if stepUUID != "" {
+		link += "/steps/" + url.PathEscape(stepUUID)
+	}
+	return link
+}
+
+func FetchPipeline(pipelineUUID string) (*types.PipelineResponse, error) {
+	if state.IsNetworkMockMode() {
+		return TestFetchPipeline(pipelineUUID), nil
 	}
 	client := createClient()
 
@@ -323,22 +757,24 @@ func FetchPipeline(pipelineUUID string) *types.PipelineResponse {
 		SetResult(&types.PipelineResponse{}).
 		Get(baseURL)
 	if err != nil {
-		log.Fatalf("Error fetching single pipeline %v", err)
-		return nil
+		return nil, fmt.Errorf("error fetching pipeline %s: %w", pipelineUUID, err)
+	}
+	if resp.StatusCode() != 200 {
+		return nil, fmt.Errorf("unexpected status code %d fetching pipeline %s", resp.StatusCode(), pipelineUUID)
 	}
 
 	response := resp.Result().(*types.PipelineResponse)
 
-	return response
+	return response, nil
 }
 
 // FetchPipelineSteps fetches pipeline steps from Bitbucket API.
 // It fetches up to 3 pages maximum, combining all steps from those pages.
 // Stops early if last page is reached before 3 pages.
-// Returns a combined slice of all steps.
-func FetchPipelineSteps(pipelineUUID string) []types.StepDetail {
-	if state.PipelineUIState.IsNetworkMockMode {
-		return SimulatedFetchPipelineSteps(pipelineUUID)
+// Returns a combined slice of all steps, or an error when a page fails to load.
+func FetchPipelineSteps(pipelineUUID string) ([]types.StepDetail, error) {
+	if state.IsNetworkMockMode() {
+		return SimulatedFetchPipelineSteps(pipelineUUID), nil
 	}
 
 	client := createClient()
@@ -358,13 +794,11 @@ func FetchPipelineSteps(pipelineUUID string) []types.StepDetail {
 			SetResult(&types.BitbucketStepsResponse{}).
 			Get(url)
 		if err != nil {
-			log.Printf("[ERROR] Failed to fetch pipeline steps: %v", err)
-			break
+			return nil, fmt.Errorf("error fetching steps of pipeline %s: %w", pipelineUUID, err)
 		}
 
 		if resp.StatusCode() != 200 {
-			log.Printf("[ERROR] Unexpected status code: %d\nBody: %s", resp.StatusCode(), string(resp.Body()))
-			break
+			return nil, fmt.Errorf("unexpected status code %d fetching steps of pipeline %s", resp.StatusCode(), pipelineUUID)
 		}
 
 		result := resp.Result().(*types.BitbucketStepsResponse)
@@ -380,7 +814,7 @@ func FetchPipelineSteps(pipelineUUID string) []types.StepDetail {
 		}
 	}
 
-	return allSteps
+	return allSteps, nil
 }
 
 func FetchPipelineStep(pipelineUUID string, stepUUID string) types.StepDetail {
@@ -0,0 +1,44 @@
+package bitbucket
+
+import (
+	"simple-git-terminal/types"
+	"testing"
+)
+
+func TestBuildFilterQuery(t *testing.T) {
+	me := &types.User{UUID: "{me}"}
+
+	tests := []struct {
+		name   string
+		filter PRFilter
+		me     *types.User
+		want   string
+	}{
+		{name: "no filter", me: me, want: ""},
+		{name: "states and author", filter: PRFilter{States: []string{"OPEN", "MERGED"}, Author: true}, me: me,
+			want: `(state="OPEN" OR state="MERGED") AND author.uuid="{me}"`},
+		{name: "reviewer and participant", filter: PRFilter{Reviewer: true, Participant: true}, me: me,
+			want: `reviewers.uuid="{me}" AND participants.user.uuid="{me}"`},
+		{name: "open tasks are on my PRs", filter: PRFilter{OpenTasks: true}, me: me,
+			want: `author.uuid="{me}" AND task_count>0`},
+		{name: "open tasks with author", filter: PRFilter{Author: true, OpenTasks: true}, me: me,
+			want: `author.uuid="{me}" AND task_count>0`},
+		{name: "involved", filter: PRFilter{States: []string{"OPEN"}, Involved: true}, me: me,
+			want: `(state="OPEN") AND (author.uuid="{me}" OR reviewers.uuid="{me}")`},
+		{name: "roles need the current user", filter: PRFilter{Author: true, States: []string{"OPEN"}},
+			want: `(state="OPEN")`},
+		{name: "branches are escaped", filter: PRFilter{Destination: `main"`, Source: "feature/x"}, me: me,
+			want: `destination.branch.name="main\"" AND source.branch.name="feature/x"`},
+		{name: "search", filter: PRFilter{States: []string{"OPEN"}, Search: "title:fix"}, me: me,
+			want: `(state="OPEN") AND title~"fix"`},
+		{name: "invalid search is ignored", filter: PRFilter{States: []string{"OPEN"}, Search: "foo:bar"}, me: me,
+			want: `(state="OPEN")`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := BuildFilterQuery(tt.filter, tt.me); got != tt.want {
+				t.Errorf("BuildFilterQuery = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package bitbucket
+
+import (
+	"fmt"
+	"log"
+	"simple-git-terminal/state"
+	"simple-git-terminal/types"
+	"sync"
+)
+
+// Number of repositories queried at the same time by the dashboard
+const dashboardConcurrency = 4
+
+// Fields of the PR list responses, participants are needed for the approval column
+const prListFields = "+values.participants,-values.description,-values.summary"
+
+// DashboardSource describes which PRs the dashboard shows
+type DashboardSource struct {
+	Repos              []state.RepoContext // Repositories queried with the PR list filters
+	Workspace          string              // Also query the recently updated repositories of this workspace, and my PRs in it
+	WorkspaceRepoLimit int
+}
+
+// FetchRepoPRs fetches the first page of PRs of a repository matching a BBQL query
+func FetchRepoPRs(repo state.RepoContext, query string, sort string) ([]types.PR, error) {
+	params := map[string]string{
+		"q":       query,
+		"fields":  prListFields,
+		"pagelen": "50",
+	}
+	if sort != "" {
+		params["sort"] = sort
+	}
+
+	resp, err := createClient().R().
+		SetQueryParams(params).
+		SetResult(&types.BitbucketPRResponse{}).
+		Get(fmt.Sprintf("%s/repositories/%s/%s/pullrequests", BitbucketBaseURL, repo.Workspace, repo.Repo))
+	if err != nil {
+		return nil, fmt.Errorf("error fetching PRs of %s: %w", repo.FullName(), err)
+	}
+	if resp.StatusCode() != 200 {
+		return nil, fmt.Errorf("unexpected status code %d fetching PRs of %s: %s", resp.StatusCode(), repo.FullName(), string(resp.Body()))
+	}
+	return resp.Result().(*types.BitbucketPRResponse).Values, nil
+}
+
+// FetchWorkspaceUserPRs fetches the PRs a user authored anywhere in a workspace, through the user level endpoint
+func FetchWorkspaceUserPRs(workspace string, user *types.User, query string, sort string) ([]types.PR, error) {
+	params := map[string]string{
+		"q":       query,
+		"fields":  prListFields,
+		"pagelen": "50",
+	}
+	if sort != "" {
+		params["sort"] = sort
+	}
+
+	resp, err := createClient().R().
+		SetQueryParams(params).
+		SetResult(&types.BitbucketPRResponse{}).
+		Get(fmt.Sprintf("%s/workspaces/%s/pullrequests/%s", BitbucketBaseURL, workspace, user.UUID))
+	if err != nil {
+		return nil, fmt.Errorf("error fetching PRs of %s in %s: %w", user.DisplayName, workspace, err)
+	}
+	if resp.StatusCode() != 200 {
+		return nil, fmt.Errorf("unexpected status code %d fetching PRs in %s: %s", resp.StatusCode(), workspace, string(resp.Body()))
+	}
+	return resp.Result().(*types.BitbucketPRResponse).Values, nil
+}
+
+// FetchWorkspaceRepos lists the most recently updated repositories of a workspace I am a member of
+func FetchWorkspaceRepos(workspace string, limit int) ([]state.RepoContext, error) {
+	resp, err := createClient().R().
+		SetQueryParams(map[string]string{
+			"role":    "member",
+			"sort":    "-updated_on",
+			"pagelen": fmt.Sprintf("%d", min(max(limit, 1), 100)),
+			"fields":  "values.full_name",
+		}).
+		SetResult(&types.BitbucketRepositoryResponse{}).
+		Get(fmt.Sprintf("%s/repositories/%s", BitbucketBaseURL, workspace))
+	if err != nil {
+		return nil, fmt.Errorf("error listing repositories of %s: %w", workspace, err)
+	}
+	if resp.StatusCode() != 200 {
+		return nil, fmt.Errorf("unexpected status code %d listing repositories of %s: %s", resp.StatusCode(), workspace, string(resp.Body()))
+	}
+
+	var repos []state.RepoContext
+	for _, repository := range resp.Result().(*types.BitbucketRepositoryResponse).Values {
+		if repo, ok := state.ParseRepoContext(repository.FullName); ok {
+			repos = append(repos, repo)
+		}
+	}
+	return repos, nil
+}
+
+// FetchDashboardPRs fetches the PRs matching filter from every dashboard repository. A repository that fails
+// is skipped, an error is only returned when nothing could be fetched.
+//
+// The repositories of the workspace were not picked by hand, so without a role filter they only list PRs I
+// authored or review. The user level endpoint only knows authored PRs, so PRs I review in repositories
+// beyond the workspace limit are missing.
+func FetchDashboardPRs(source DashboardSource, me *types.User, filter PRFilter, sort string) ([]types.PR, error) {
+	query := BuildFilterQuery(filter, me)
+	workspaceQuery := query
+	if !filter.hasRole() && me != nil && me.UUID != "" {
+		involved := filter
+		involved.Involved = true
+		workspaceQuery = BuildFilterQuery(involved, me)
+	}
+
+	queries := make(map[string]string)
+	var repos []state.RepoContext
+	add := func(found []state.RepoContext, query string) {
+		for _, repo := range found {
+			if _, ok := queries[repo.FullName()]; !ok {
+				queries[repo.FullName()] = query
+				repos = append(repos, repo)
+			}
+		}
+	}
+	add(source.Repos, query)
+	if source.Workspace != "" {
+		workspaceRepos, err := FetchWorkspaceRepos(source.Workspace, source.WorkspaceRepoLimit)
+		if err != nil {
+			log.Printf("[DASHBOARD] %v", err)
+		}
+		add(workspaceRepos, workspaceQuery)
+	}
+
+	var (
+		mutex    sync.Mutex
+		wg       sync.WaitGroup
+		prs      []types.PR
+		seen     = make(map[string]bool)
+		failures []error
+		slots    = make(chan struct{}, dashboardConcurrency)
+	)
+	collect := func(found []types.PR, err error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		if err != nil {
+			log.Printf("[DASHBOARD] %v", err)
+			failures = append(failures, err)
+			return
+		}
+		for _, pr := range found {
+			if !seen[pr.Key()] {
+				seen[pr.Key()] = true
+				prs = append(prs, pr)
+			}
+		}
+	}
+
+	for _, repo := range repos {
+		wg.Add(1)
+		go func(repo state.RepoContext) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			collect(FetchRepoPRs(repo, queries[repo.FullName()], sort))
+		}(repo)
+	}
+
+	// My PRs in repositories beyond the workspace limit
+	if source.Workspace != "" && me != nil && me.UUID != "" {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			collect(FetchWorkspaceUserPRs(source.Workspace, me, query, sort))
+		}()
+	}
+	wg.Wait()
+
+	if len(prs) == 0 && len(failures) > 0 {
+		return nil, fmt.Errorf("could not fetch PRs from any repository: %w", failures[0])
+	}
+	return prs, nil
+}
@@ -0,0 +1,233 @@
+package bitbucket
+
+import (
+	"fmt"
+	"regexp"
+	"simple-git-terminal/types"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// QuerySyntaxError points at the part of a search query that could not be understood
+type QuerySyntaxError struct {
+	Pos     int // Byte offset in the input
+	Message string
+}
+
+func (e *QuerySyntaxError) Error() string {
+	return fmt.Sprintf("col %d: %s", e.Pos+1, e.Message)
+}
+
+// queryToken is a single whitespace separated part of a search query, with quotes removed
+type queryToken struct {
+	pos    int
+	key    string // Empty for free text
+	value  string
+	quoted bool
+}
+
+var relativeDateRegex = regexp.MustCompile(`^(\d+)([hdw])$`)
+
+// TranslatePRQuery turns the search bar syntax into a BBQL expression, e.g.
+// `author:me state:open branch:feature/* updated:<7d "login bug"`. Every value is escaped, so quotes
+// in the input cannot break out of the generated query. Returns "" for an empty query.
+func TranslatePRQuery(input string, me *types.User, now time.Time) (string, error) {
+	tokens, err := tokenizeQuery(input)
+	if err != nil {
+		return "", err
+	}
+
+	var clauses []string
+	for _, token := range tokens {
+		clause, err := translateToken(token, me, now)
+		if err != nil {
+			return "", err
+		}
+		clauses = append(clauses, clause)
+	}
+	return strings.Join(clauses, " AND "), nil
+}
+
+func tokenizeQuery(input string) ([]queryToken, error) {
+	var tokens []queryToken
+	i := 0
+	for i < len(input) {
+		if unicode.IsSpace(rune(input[i])) {
+			i++
+			continue
+		}
+
+		token := queryToken{pos: i}
+		var sb strings.Builder
+		for i < len(input) && !unicode.IsSpace(rune(input[i])) {
+			switch c := input[i]; {
+			case c == '"':
+				end, text, err := readQuoted(input, i)
+				if err != nil {
+					return nil, err
+				}
+				sb.WriteString(text)
+				token.quoted = true
+				i = end
+			case c == ':' && token.key == "" && !token.quoted && sb.Len() > 0:
+				token.key = strings.ToLower(sb.String())
+				sb.Reset()
+				i++
+			default:
+				sb.WriteByte(c)
+				i++
+			}
+		}
+		token.value = sb.String()
+
+		if token.key != "" && token.value == "" {
+			return nil, &QuerySyntaxError{Pos: token.pos, Message: fmt.Sprintf("missing value for %s:", token.key)}
+		}
+		tokens = append(tokens, token)
+	}
+	return tokens, nil
+}
+
+// readQuoted reads a double quoted string starting at input[start], where \" and \\ are escapes
+func readQuoted(input string, start int) (int, string, error) {
+	var sb strings.Builder
+	for i := start + 1; i < len(input); i++ {
+		switch input[i] {
+		case '\\':
+			if i+1 < len(input) {
+				i++
+				sb.WriteByte(input[i])
+			}
+		case '"':
+			return i + 1, sb.String(), nil
+		default:
+			sb.WriteByte(input[i])
+		}
+	}
+	return 0, "", &QuerySyntaxError{Pos: start, Message: "unterminated quote"}
+}
+
+func translateToken(token queryToken, me *types.User, now time.Time) (string, error) {
+	fail := func(format string, args ...interface{}) (string, error) {
+		return "", &QuerySyntaxError{Pos: token.pos, Message: fmt.Sprintf(format, args...)}
+	}
+
+	switch token.key {
+	case "":
+		text := quoteBBQL(token.value)
+		return fmt.Sprintf("(title~%s OR description~%s)", text, text), nil
+
+	case "title":
+		return "title~" + quoteBBQL(token.value), nil
+
+	case "author", "reviewer":
+		field := "author"
+		if token.key == "reviewer" {
+			field = "reviewers"
+		}
+		if strings.EqualFold(token.value, "me") && !token.quoted {
+			if me == nil || me.UUID == "" {
+				return fail("%s:me is unavailable, the current user could not be loaded", token.key)
+			}
+			return fmt.Sprintf("%s.uuid=%s", field, quoteBBQL(me.UUID)), nil
+		}
+		name := quoteBBQL(token.value)
+		return fmt.Sprintf("(%s.nickname=%s OR %s.display_name~%s)", field, name, field, name), nil
+
+	case "state":
+		var states []string
+		for _, value := range strings.Split(token.value, ",") {
+			switch upper := strings.ToUpper(strings.TrimSpace(value)); upper {
+			case "OPEN", "MERGED", "DECLINED", "SUPERSEDED":
+				states = append(states, "state="+quoteBBQL(upper))
+			default:
+				return fail("unknown state %q, use open, merged, declined or superseded", value)
+			}
+		}
+		return "(" + strings.Join(states, " OR ") + ")", nil
+
+	case "branch", "source", "target", "dest":
+		field := "source.branch.name"
+		if token.key == "target" || token.key == "dest" {
+			field = "destination.branch.name"
+		}
+		prefix, isGlob := strings.CutSuffix(token.value, "*")
+		if strings.Contains(prefix, "*") {
+			return fail("only a trailing * is supported in %s:", token.key)
+		}
+		if isGlob {
+			// BBQL has no prefix match, "contains" is the closest, so feature/* also matches xfeature/1
+			return field + "~" + quoteBBQL(prefix), nil
+		}
+		return field + "=" + quoteBBQL(prefix), nil
+
+	case "updated", "created":
+		field := token.key + "_on"
+		clause, err := translateDateFilter(field, token.value, now)
+		if err != nil {
+			return fail("%v", err)
+		}
+		return clause, nil
+	}
+
+	if strings.HasPrefix(token.key, "-") {
+		return fail("negated filters are not supported, BBQL has no NOT")
+	}
+	return fail("unknown filter %q, put literal text in quotes", token.key)
+}
+
+// translateDateFilter supports relative ages (<7d: within the last 7 days, >7d: older than 7 days)
+// and absolute dates (<2024-01-31, >=2024-01-01). A bare value means "<".
+func translateDateFilter(field string, value string, now time.Time) (string, error) {
+	operator := "<"
+	for _, candidate := range []string{"<=", ">=", "<", ">"} {
+		if rest, ok := strings.CutPrefix(value, candidate); ok {
+			operator, value = candidate, rest
+			break
+		}
+	}
+
+	if matches := relativeDateRegex.FindStringSubmatch(value); matches != nil {
+		amount, _ := strconv.Atoi(matches[1])
+		unit := map[string]time.Duration{"h": time.Hour, "d": 24 * time.Hour, "w": 7 * 24 * time.Hour}[matches[2]]
+		since := now.Add(-time.Duration(amount) * unit)
+
+		// A smaller age means a later timestamp, so the comparison flips
+		flipped := map[string]string{"<": ">", "<=": ">=", ">": "<", ">=": "<="}[operator]
+		return fmt.Sprintf("%s %s %s", field, flipped, since.UTC().Format(time.RFC3339)), nil
+	}
+
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return "", fmt.Errorf("invalid date %q, use e.g. 7d, 12h, 2w or 2024-01-31", value)
+	}
+	return fmt.Sprintf("%s %s %s", field, operator, date.UTC().Format(time.RFC3339)), nil
+}
+
+// quoteBBQL wraps a value in double quotes, escaping backslashes and quotes
+func quoteBBQL(value string) string {
+	escaped := strings.ReplaceAll(value, `\`, `\\`)
+	escaped = strings.ReplaceAll(escaped, `"`, `\"`)
+	return `"` + escaped + `"`
+}
+
+// PRQueryText extracts the free text and title: values of a search query, for APIs that only support a
+// plain text search. The keys of all other filters are returned so callers can report them as unsupported.
+func PRQueryText(input string) (string, []string, error) {
+	tokens, err := tokenizeQuery(input)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var words, unsupported []string
+	for _, token := range tokens {
+		if token.key == "" || token.key == "title" {
+			words = append(words, token.value)
+		} else {
+			unsupported = append(unsupported, token.key)
+		}
+	}
+	return strings.Join(words, " "), unsupported, nil
+}
@@ -0,0 +1,85 @@
+package bitbucket
+
+import (
+	"errors"
+	"reflect"
+	"simple-git-terminal/types"
+	"testing"
+	"time"
+)
+
+func TestTranslatePRQuery(t *testing.T) {
+	me := &types.User{UUID: "{me}"}
+	now := time.Date(2024, 2, 8, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		input   string
+		me      *types.User
+		want    string
+		wantPos int // Column of the syntax error, 0 when none is expected
+	}{
+		{name: "empty", input: "  ", want: ""},
+		{name: "free text words", input: "login bug",
+			want: `(title~"login" OR description~"login") AND (title~"bug" OR description~"bug")`},
+		{name: "quoted free text", input: `"login bug"`, want: `(title~"login bug" OR description~"login bug")`},
+		{name: "quoted key is free text", input: `"foo:bar"`, want: `(title~"foo:bar" OR description~"foo:bar")`},
+		{name: "escaped quote", input: `title:"say \"hi\""`, want: `title~"say \"hi\""`},
+		{name: "quote cannot break out", input: `title:a"\" OR state=\""`, want: `title~"a\" OR state=\""`},
+		{name: "backslash escaped", input: `title:a\b`, want: `title~"a\\b"`},
+		{name: "author me", input: "author:me", me: me, want: `author.uuid="{me}"`},
+		{name: "quoted me is a name", input: `author:"me"`, me: me,
+			want: `(author.nickname="me" OR author.display_name~"me")`},
+		{name: "me unknown", input: "author:me", wantPos: 1},
+		{name: "reviewer", input: "reviewer:alice",
+			want: `(reviewers.nickname="alice" OR reviewers.display_name~"alice")`},
+		{name: "keys ignore case", input: "State:OPEN", want: `(state="OPEN")`},
+		{name: "several states", input: "state:open,merged", want: `(state="OPEN" OR state="MERGED")`},
+		{name: "unknown state", input: "state:closed", wantPos: 1},
+		{name: "exact branch", input: "target:main", want: `destination.branch.name="main"`},
+		{name: "branch glob is contains", input: "branch:feature/*", want: `source.branch.name~"feature/"`},
+		{name: "inner glob", input: "branch:a*b", wantPos: 1},
+		{name: "relative date", input: "updated:<7d", want: "updated_on > 2024-02-01T00:00:00Z"},
+		{name: "older than", input: "updated:>2w", want: "updated_on < 2024-01-25T00:00:00Z"},
+		{name: "absolute date", input: "created:>=2024-01-01", want: "created_on >= 2024-01-01T00:00:00Z"},
+		{name: "invalid date", input: "updated:yesterday", wantPos: 1},
+		{name: "negation", input: "state:open -author:me", me: me, wantPos: 12},
+		{name: "unknown key", input: "state:open foo:bar", wantPos: 12},
+		{name: "missing value", input: "author:", wantPos: 1},
+		{name: "unterminated quote", input: `title:"open`, wantPos: 7},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := TranslatePRQuery(tt.input, tt.me, now)
+			if tt.wantPos != 0 {
+				var syntaxErr *QuerySyntaxError
+				if !errors.As(err, &syntaxErr) {
+					t.Fatalf("TranslatePRQuery(%q) err = %v, want a syntax error", tt.input, err)
+				}
+				if syntaxErr.Pos+1 != tt.wantPos {
+					t.Errorf("error at col %d, want %d", syntaxErr.Pos+1, tt.wantPos)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("TranslatePRQuery(%q) err = %v", tt.input, err)
+			}
+			if got != tt.want {
+				t.Errorf("TranslatePRQuery(%q) = %s, want %s", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPRQueryText(t *testing.T) {
+	text, unsupported, err := PRQueryText(`login title:"bug fix" author:me state:open`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text != "login bug fix" {
+		t.Errorf("text = %q, want %q", text, "login bug fix")
+	}
+	if want := []string{"author", "state"}; !reflect.DeepEqual(unsupported, want) {
+		t.Errorf("unsupported = %v, want %v", unsupported, want)
+	}
+}
@@ -0,0 +1,106 @@
+package bitbucket
+
+import (
+	"fmt"
+	"simple-git-terminal/state"
+	"simple-git-terminal/types"
+)
+
+// Pages of workspace members fetched at most, large workspaces are cut off
+const maxMemberPages = 5
+
+// FetchWorkspaceMembers lists the users of the workspace of repo
+func FetchWorkspaceMembers(repo state.RepoContext) ([]types.User, error) {
+	var users []types.User
+	url := fmt.Sprintf("%s/workspaces/%s/members?pagelen=100", BitbucketBaseURL, repo.Workspace)
+	for page := 0; url != "" && page < maxMemberPages; page++ {
+		members, next, err := fetchMembers(url)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching members of %s: %w", repo.Workspace, err)
+		}
+		for _, member := range members {
+			users = append(users, member.User)
+		}
+		url = next
+	}
+	return users, nil
+}
+
+// FetchDefaultReviewers lists the default reviewers of repo, including those inherited
+// from its project
+func FetchDefaultReviewers(repo state.RepoContext) ([]types.Member, error) {
+	members, _, err := fetchMembers(fmt.Sprintf("%s/repositories/%s/%s/effective-default-reviewers?pagelen=100", BitbucketBaseURL, repo.Workspace, repo.Repo))
+	if err != nil {
+		return nil, fmt.Errorf("error fetching default reviewers: %w", err)
+	}
+	return members, nil
+}
+
+func fetchMembers(url string) ([]types.Member, string, error) {
+	resp, err := createClient().R().
+		SetResult(&types.BitbucketMemberResponse{}).
+		Get(url)
+	if err != nil {
+		return nil, "", err
+	}
+	if resp.StatusCode() != 200 {
+		return nil, "", fmt.Errorf("unexpected status code %d: %s", resp.StatusCode(), string(resp.Body()))
+	}
+	response := resp.Result().(*types.BitbucketMemberResponse)
+	return response.Values, response.Next, nil
+}
+
+// FetchBranchRestrictions lists the branch restrictions of repo
+func FetchBranchRestrictions(repo state.RepoContext) ([]types.BranchRestriction, error) {
+	resp, err := createClient().R().
+		SetQueryParam("pagelen", "100").
+		SetResult(&types.BitbucketBranchRestrictionResponse{}).
+		Get(fmt.Sprintf("%s/repositories/%s/%s/branch-restrictions", BitbucketBaseURL, repo.Workspace, repo.Repo))
+	if err != nil {
+		return nil, fmt.Errorf("error fetching branch restrictions: %w", err)
+	}
+	if resp.StatusCode() != 200 {
+		return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode(), string(resp.Body()))
+	}
+	return resp.Result().(*types.BitbucketBranchRestrictionResponse).Values, nil
+}
+
+// UpdatePRReviewers replaces the reviewers of a PR
+func UpdatePRReviewers(repo state.RepoContext, id int, title string, reviewers []types.User) (*types.PR, error) {
+	type reviewer struct {
+		UUID string `json:"uuid"`
+	}
+	body := struct {
+		Title     string     `json:"title"`
+		Reviewers []reviewer `json:"reviewers"`
+	}{Title: title, Reviewers: []reviewer{}}
+	for _, user := range reviewers {
+		body.Reviewers = append(body.Reviewers, reviewer{UUID: user.UUID})
+	}
+
+	resp, err := createClient().R().
+		SetBody(body).
+		SetResult(&types.PR{}).
+		Put(fmt.Sprintf("%s/repositories/%s/%s/pullrequests/%d", BitbucketBaseURL, repo.Workspace, repo.Repo, id))
+	if err != nil {
+		return nil, fmt.Errorf("error updating reviewers of PR #%d: %w", id, err)
+	}
+	if resp.StatusCode() != 200 {
+		return nil, fmt.Errorf("unexpected status code %d updating reviewers of PR #%d: %s", resp.StatusCode(), id, string(resp.Body()))
+	}
+	return resp.Result().(*types.PR), nil
+}
+
+// FetchBranchingModel returns the effective branching model of repo
+func FetchBranchingModel(repo state.RepoContext) (*types.BranchingModel, error) {
+	resp, err := createClient().R().
+		SetResult(&types.BranchingModel{}).
+		Get(fmt.Sprintf("%s/repositories/%s/%s/effective-branching-model", BitbucketBaseURL, repo.Workspace, repo.Repo))
+	if err != nil {
+		return nil, fmt.Errorf("error fetching branching model: %w", err)
+	}
+	if resp.StatusCode() != 200 {
+		return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode(), string(resp.Body()))
+	}
+	return resp.Result().(*types.BranchingModel), nil
+}
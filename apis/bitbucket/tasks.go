@@ -0,0 +1,73 @@
+package bitbucket
+
+import (
+	"fmt"
+	"simple-git-terminal/state"
+	"simple-git-terminal/types"
+)
+
+func tasksURL(repo state.RepoContext, id int) string {
+	return fmt.Sprintf("%s/repositories/%s/%s/pullrequests/%d/tasks", BitbucketBaseURL, repo.Workspace, repo.Repo, id)
+}
+
+// FetchPRTasks lists the open and resolved tasks of a PR
+func FetchPRTasks(repo state.RepoContext, id int) ([]types.Task, error) {
+	var tasks []types.Task
+	url := tasksURL(repo, id) + "?pagelen=100"
+	for url != "" {
+		resp, err := createClient().R().
+			SetResult(&types.BitbucketTaskResponse{}).
+			Get(url)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching tasks of PR #%d: %w", id, err)
+		}
+		if resp.StatusCode() != 200 {
+			return nil, fmt.Errorf("unexpected status code %d fetching tasks of PR #%d: %s", resp.StatusCode(), id, string(resp.Body()))
+		}
+		response := resp.Result().(*types.BitbucketTaskResponse)
+		tasks = append(tasks, response.Values...)
+		url = response.Next
+	}
+	return tasks, nil
+}
+
+// CreatePRTask adds a task to a PR, attached to the comment unless commentID is 0
+func CreatePRTask(repo state.RepoContext, id int, commentID int, raw string) (*types.Task, error) {
+	body := map[string]interface{}{
+		"content": map[string]string{"raw": raw},
+	}
+	if commentID > 0 {
+		body["comment"] = map[string]int{"id": commentID}
+	}
+
+	resp, err := createClient().R().
+		SetBody(body).
+		SetResult(&types.Task{}).
+		Post(tasksURL(repo, id))
+	if err != nil {
+		return nil, fmt.Errorf("error creating task: %w", err)
+	}
+	if resp.StatusCode() != 201 {
+		return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode(), string(resp.Body()))
+	}
+	return resp.Result().(*types.Task), nil
+}
+
+// SetPRTaskResolved resolves or reopens a task
+func SetPRTaskResolved(repo state.RepoContext, id int, taskID int, resolved bool) error {
+	taskState := types.TaskUnresolved
+	if resolved {
+		taskState = types.TaskResolved
+	}
+
+	resp, err := createClient().R().
+		SetBody(map[string]string{"state": taskState}).
+		Put(fmt.Sprintf("%s/%d", tasksURL(repo, id), taskID))
+	if err != nil {
+		return fmt.Errorf("error updating task %d: %w", taskID, err)
+	}
+	if resp.StatusCode() != 200 {
+		return fmt.Errorf("unexpected status code %d: %s", resp.StatusCode(), string(resp.Body()))
+	}
+	return nil
+}
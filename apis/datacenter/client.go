@@ -0,0 +1,683 @@
+package datacenter
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"simple-git-terminal/apis/bitbucket"
+	"simple-git-terminal/auth"
+	"simple-git-terminal/state"
+	"simple-git-terminal/types"
+	"simple-git-terminal/util"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+const (
+	// Page size of the PR list, Data Center's default of 25 matches Cloud's pagelen
+	prPageLimit = 25
+	// How long a fetched PR diff is reused for the diffstat and the per file diffs
+	diffCacheTTL = 30 * time.Second
+)
+
+// BaseURL is the Data Center server including any context path, e.g. https://bitbucket.example.com
+var BaseURL string
+
+var client *resty.Client
+
+type cachedDiff struct {
+	text      string
+	fetchedAt time.Time
+}
+
+var (
+	diffCache      = make(map[string]cachedDiff)
+	diffCacheMutex sync.Mutex
+)
+
+// Configure points the client at a Data Center server
+func Configure(baseURL string) {
+	BaseURL = strings.TrimSuffix(baseURL, "/")
+	client = nil
+}
+
+// createClient authenticates with the environment or `bbpr auth login`. Personal access tokens are sent as
+// bearer token.
+func createClient() *resty.Client {
+	if client != nil {
+		return client
+	}
+
+	client = resty.New()
+	if err := auth.Configure(client, BaseURL); err != nil {
+		log.Printf("[DATACENTER] %v", err)
+	}
+	return client
+}
+
+// prURL builds the REST URL of the PRs of repo, followed by the optional suffix
+func prURL(repo state.RepoContext, suffix string, args ...interface{}) string {
+	return fmt.Sprintf("%s/rest/api/1.0/projects/%s/repos/%s/pull-requests", BaseURL, url.PathEscape(repo.Workspace), url.PathEscape(repo.Repo)) +
+		fmt.Sprintf(suffix, args...)
+}
+
+// webPRURL is the address of a PR in the web UI
+func webPRURL(repo state.RepoContext, id int) string {
+	return fmt.Sprintf("%s/projects/%s/repos/%s/pull-requests/%d", BaseURL, url.PathEscape(repo.Workspace), url.PathEscape(repo.Repo), id)
+}
+
+func checkStatus(resp *resty.Response, expected ...int) error {
+	for _, code := range expected {
+		if resp.StatusCode() == code {
+			return nil
+		}
+	}
+	return fmt.Errorf("unexpected status code %d: %s", resp.StatusCode(), string(resp.Body()))
+}
+
+// FetchCurrentUser resolves the authenticated user. Data Center has no "myself" endpoint, but reports the
+// user name in the X-AUSERNAME header of every authenticated response.
+func FetchCurrentUser() (*types.User, error) {
+	resp, err := createClient().R().Get(BaseURL + "/rest/api/1.0/application-properties")
+	if err != nil {
+		return nil, fmt.Errorf("error fetching user: %w", err)
+	}
+	username := resp.Header().Get("X-AUSERNAME")
+	if username == "" {
+		log.Printf("No active user, the server did not report who the credentials belong to")
+		return nil, nil
+	}
+
+	resp, err = createClient().R().
+		SetResult(&user{}).
+		Get(fmt.Sprintf("%s/rest/api/1.0/users/%s", BaseURL, url.PathEscape(username)))
+	if err != nil || resp.StatusCode() != 200 {
+		log.Printf("[DATACENTER] Could not load user %s, using the name only: %v", username, err)
+		return &types.User{DisplayName: username, UUID: username, Nickname: username, Type: "user"}, nil
+	}
+
+	currentUser := resp.Result().(*user).toUser()
+	log.Printf("Current active user => %v", currentUser)
+	return &currentUser, nil
+}
+
+// FetchPRs fetches a page of PRs matching the filter. Data Center filters on a single state, by role and
+// by destination branch; of the search syntax only free text is supported.
+func FetchPRs(repo state.RepoContext, filter bitbucket.PRFilter, me *types.User, sort string, pageNumber int) ([]types.PR, types.Pagination, error) {
+	params := url.Values{}
+	params.Set("limit", fmt.Sprintf("%d", prPageLimit))
+	params.Set("start", fmt.Sprintf("%d", max(pageNumber-1, 0)*prPageLimit))
+
+	if len(filter.States) == 1 {
+		params.Set("state", filter.States[0])
+	} else {
+		// Several states are filtered locally below
+		params.Set("state", "ALL")
+	}
+
+	if me != nil && me.Nickname != "" {
+		roles := []struct {
+			enabled bool
+			role    string
+		}{{filter.Author || filter.OpenTasks, "AUTHOR"}, {filter.Reviewer, "REVIEWER"}, {filter.Participant, "PARTICIPANT"}}
+		n := 0
+		for _, role := range roles {
+			if role.enabled {
+				n++
+				params.Set(fmt.Sprintf("role.%d", n), role.role)
+				params.Set(fmt.Sprintf("username.%d", n), me.Nickname)
+			}
+		}
+	}
+
+	// Only one branch can be filtered on, the destination wins
+	switch {
+	case filter.Destination != "":
+		params.Set("direction", "INCOMING")
+		params.Set("at", "refs/heads/"+filter.Destination)
+	case filter.Source != "":
+		params.Set("direction", "OUTGOING")
+		params.Set("at", "refs/heads/"+filter.Source)
+	}
+
+	text, unsupported, err := bitbucket.PRQueryText(filter.Search)
+	if err != nil {
+		log.Printf("Ignoring invalid search %q: %v", filter.Search, err)
+	}
+	if len(unsupported) > 0 {
+		log.Printf("[DATACENTER] Search filters %v are not supported by Data Center, only searching text", unsupported)
+	}
+	if text != "" {
+		params.Set("filterText", text)
+	}
+
+	switch {
+	case strings.HasPrefix(sort, "-"):
+		params.Set("order", "NEWEST")
+	case sort != "":
+		params.Set("order", "OLDEST")
+	}
+
+	log.Printf("[DATACENTER] Fetching PRs with %s", params.Encode())
+	resp, err := createClient().R().
+		SetQueryParamsFromValues(params).
+		SetResult(&page[pullRequest]{}).
+		Get(prURL(repo, ""))
+	if err != nil {
+		return nil, types.Pagination{}, fmt.Errorf("error fetching PRs: %w", err)
+	}
+	if err := checkStatus(resp, 200); err != nil {
+		return nil, types.Pagination{}, err
+	}
+
+	response := resp.Result().(*page[pullRequest])
+	var prs []types.PR
+	for _, pr := range response.Values {
+		if len(filter.States) > 1 && !containsState(filter.States, pr.State) {
+			continue
+		}
+		// Open tasks are filtered locally as well
+		if filter.OpenTasks && pr.Properties.OpenTaskCount == 0 {
+			continue
+		}
+		prs = append(prs, pr.toPR())
+	}
+
+	// Data Center does not count the matches, so offer one more page until the last one is reached
+	size := response.Start + len(response.Values)
+	if !response.IsLastPage {
+		size += prPageLimit
+	}
+	return prs, types.Pagination{Page: max(pageNumber, 1), PageLen: prPageLimit, Size: size}, nil
+}
+
+func containsState(states []string, prState string) bool {
+	for _, candidate := range states {
+		if strings.EqualFold(candidate, prState) {
+			return true
+		}
+	}
+	return false
+}
+
+func FetchPR(repo state.RepoContext, id int) (*types.PR, error) {
+	resp, err := createClient().R().
+		SetResult(&pullRequest{}).
+		Get(prURL(repo, "/%d", id))
+	if err != nil {
+		return nil, fmt.Errorf("error fetching PR #%d: %w", id, err)
+	}
+	if resp.StatusCode() != 200 {
+		return nil, fmt.Errorf("unexpected status code %d fetching PR #%d: %s", resp.StatusCode(), id, string(resp.Body()))
+	}
+
+	pr := resp.Result().(*pullRequest).toPR()
+	return &pr, nil
+}
+
+// UpdatePR changes the title, description, destination branch and draft flag of a PR. Data Center drops
+// reviewers left out of the update, so the current ones are sent along.
+func UpdatePR(repo state.RepoContext, id int, update types.PRUpdate) (*types.PR, error) {
+	resp, err := createClient().R().
+		SetResult(&pullRequest{}).
+		Get(prURL(repo, "/%d", id))
+	if err != nil {
+		return nil, fmt.Errorf("error fetching PR #%d: %w", id, err)
+	}
+	if err := checkStatus(resp, 200); err != nil {
+		return nil, err
+	}
+	current := resp.Result().(*pullRequest)
+
+	reviewers := []map[string]interface{}{}
+	for _, reviewer := range current.Reviewers {
+		reviewers = append(reviewers, map[string]interface{}{"user": map[string]string{"name": reviewer.User.Name}})
+	}
+	body := map[string]interface{}{
+		"version":     current.Version,
+		"title":       update.Title,
+		"description": update.Description,
+		"toRef": map[string]interface{}{
+			"id":         "refs/heads/" + update.Destination,
+			"repository": current.ToRef.Repository,
+		},
+		"draft":     update.Draft,
+		"reviewers": reviewers,
+	}
+
+	resp, err = createClient().R().
+		SetBody(body).
+		SetResult(&pullRequest{}).
+		Put(prURL(repo, "/%d", id))
+	if err != nil {
+		return nil, fmt.Errorf("error updating PR #%d: %w", id, err)
+	}
+	if err := checkStatus(resp, 200); err != nil {
+		return nil, err
+	}
+	pr := resp.Result().(*pullRequest).toPR()
+	return &pr, nil
+}
+
+// FetchBranchNames lists the branches of repo, most recently updated first
+func FetchBranchNames(repo state.RepoContext) ([]string, error) {
+	resp, err := createClient().R().
+		SetQueryParams(map[string]string{"limit": "100", "orderBy": "MODIFICATION"}).
+		SetResult(&page[ref]{}).
+		Get(fmt.Sprintf("%s/rest/api/1.0/projects/%s/repos/%s/branches", BaseURL, url.PathEscape(repo.Workspace), url.PathEscape(repo.Repo)))
+	if err != nil {
+		return nil, fmt.Errorf("error fetching branches: %w", err)
+	}
+	if err := checkStatus(resp, 200); err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, branch := range resp.Result().(*page[ref]).Values {
+		names = append(names, branch.DisplayID)
+	}
+	return names, nil
+}
+
+// fetchRawActivities follows the pagination of the activity stream, newest first
+func fetchRawActivities(repo state.RepoContext, id int) ([]activity, error) {
+	var activities []activity
+	start := 0
+	for {
+		resp, err := createClient().R().
+			SetQueryParams(map[string]string{"start": fmt.Sprintf("%d", start), "limit": "100"}).
+			SetResult(&page[activity]{}).
+			Get(prURL(repo, "/%d/activities", id))
+		if err != nil {
+			return nil, fmt.Errorf("error fetching activities of PR #%d: %w", id, err)
+		}
+		if err := checkStatus(resp, 200); err != nil {
+			return nil, err
+		}
+
+		response := resp.Result().(*page[activity])
+		activities = append(activities, response.Values...)
+		if response.IsLastPage || len(response.Values) == 0 {
+			return activities, nil
+		}
+		start = response.NextPageStart
+	}
+}
+
+func FetchActivities(repo state.RepoContext, id int) ([]types.Activity, error) {
+	raw, err := fetchRawActivities(repo, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var activities []types.Activity
+	for _, entry := range raw {
+		// Replies and edits are already part of the comment tree of the ADDED activity
+		if entry.Action == "COMMENTED" && entry.CommentAction != "ADDED" {
+			continue
+		}
+		activities = append(activities, entry.toActivity())
+	}
+	return activities, nil
+}
+
+// FetchComments collects the comment threads of the activity stream, plus my pending drafts. Drafts are not
+// part of the activities and are listed by the comments endpoint. Blocker comments are left to FetchTasks.
+func FetchComments(repo state.RepoContext, id int) ([]types.Comment, error) {
+	raw, err := fetchRawActivities(repo, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var comments []types.Comment
+	seen := make(map[int]bool)
+	for _, task := range activityTasks(raw) {
+		seen[task.ID] = true
+	}
+	add := func(found []types.Comment) {
+		for _, comment := range found {
+			if !seen[comment.ID] {
+				seen[comment.ID] = true
+				comments = append(comments, comment)
+			}
+		}
+	}
+
+	// Oldest first, like Cloud's comments endpoint
+	for i := len(raw) - 1; i >= 0; i-- {
+		entry := raw[i]
+		if entry.Action != "COMMENTED" || entry.CommentAction != "ADDED" || entry.Comment == nil {
+			continue
+		}
+		inline := entry.CommentAnchor.toInline()
+		if entry.Comment.Anchor != nil {
+			inline = entry.Comment.Anchor.toInline()
+		}
+		add(entry.Comment.toComments(inline, 0))
+	}
+
+	pending, err := fetchPendingComments(repo, id)
+	if err != nil {
+		log.Printf("[DATACENTER] Could not list drafts of PR #%d: %v", id, err)
+	}
+	add(pending)
+
+	for i := range comments {
+		comments[i].Links.HTML.Href = fmt.Sprintf("%s/overview?commentId=%d", webPRURL(repo, id), comments[i].ID)
+	}
+	return comments, nil
+}
+
+// FetchTasks lists the blocker comments of a PR, which are Data Center's tasks
+func FetchTasks(repo state.RepoContext, id int) ([]types.Task, error) {
+	raw, err := fetchRawActivities(repo, id)
+	if err != nil {
+		return nil, err
+	}
+	return activityTasks(raw), nil
+}
+
+// activityTasks collects the tasks of the comment threads in the activity stream, oldest first
+func activityTasks(raw []activity) []types.Task {
+	var tasks []types.Task
+	for i := len(raw) - 1; i >= 0; i-- {
+		entry := raw[i]
+		if entry.Action == "COMMENTED" && entry.CommentAction == "ADDED" && entry.Comment != nil {
+			tasks = append(tasks, entry.Comment.toTasks(0)...)
+		}
+	}
+	return tasks
+}
+
+// CreateTask adds a blocker comment, as a reply to the comment unless commentID is 0
+func CreateTask(repo state.RepoContext, id int, commentID int, raw string) (*types.Task, error) {
+	body := map[string]interface{}{"text": raw, "severity": "BLOCKER"}
+	if commentID > 0 {
+		body["parent"] = map[string]int{"id": commentID}
+	}
+
+	resp, err := createClient().R().
+		SetBody(body).
+		SetResult(&comment{}).
+		Post(prURL(repo, "/%d/comments", id))
+	if err != nil {
+		return nil, fmt.Errorf("error creating task: %w", err)
+	}
+	if err := checkStatus(resp, 201); err != nil {
+		return nil, err
+	}
+
+	tasks := resp.Result().(*comment).toTasks(commentID)
+	if len(tasks) == 0 {
+		return nil, fmt.Errorf("the server did not create a blocker comment")
+	}
+	return &tasks[0], nil
+}
+
+// SetTaskResolved resolves or reopens a blocker comment
+func SetTaskResolved(repo state.RepoContext, id int, taskID int, resolved bool) error {
+	version, err := fetchCommentVersion(repo, id, taskID)
+	if err != nil {
+		return err
+	}
+	commentState := "OPEN"
+	if resolved {
+		commentState = "RESOLVED"
+	}
+
+	resp, err := createClient().R().
+		SetBody(map[string]interface{}{"version": version, "state": commentState}).
+		Put(prURL(repo, "/%d/comments/%d", id, taskID))
+	if err != nil {
+		return fmt.Errorf("error updating task %d: %w", taskID, err)
+	}
+	return checkStatus(resp, 200)
+}
+
+// fetchPendingComments lists my drafts on every file of the PR with one paged request, leaving out path
+func fetchPendingComments(repo state.RepoContext, id int) ([]types.Comment, error) {
+	var comments []types.Comment
+	start := 0
+	for {
+		resp, err := createClient().R().
+			SetQueryParams(map[string]string{"state": "PENDING", "start": fmt.Sprintf("%d", start), "limit": "100"}).
+			SetResult(&page[comment]{}).
+			Get(prURL(repo, "/%d/comments", id))
+		if err != nil {
+			return nil, err
+		}
+		if err := checkStatus(resp, 200); err != nil {
+			return nil, err
+		}
+
+		response := resp.Result().(*page[comment])
+		for _, draft := range response.Values {
+			comments = append(comments, draft.toComments(draft.Anchor.toInline(), 0)...)
+		}
+		if response.IsLastPage || len(response.Values) == 0 {
+			return comments, nil
+		}
+		start = response.NextPageStart
+	}
+}
+
+// FetchDiff fetches the unified diff of a PR, rewriting Data Center's src:// and dst:// prefixes into git's a/ and b/
+func FetchDiff(repo state.RepoContext, id int) (string, error) {
+	key := fmt.Sprintf("%s/%s#%d", repo.Workspace, repo.Repo, id)
+	diffCacheMutex.Lock()
+	cached, ok := diffCache[key]
+	diffCacheMutex.Unlock()
+	if ok && time.Since(cached.fetchedAt) < diffCacheTTL {
+		return cached.text, nil
+	}
+
+	resp, err := createClient().R().
+		SetHeader("Accept", "text/plain").
+		Get(prURL(repo, "/%d.diff", id))
+	if err != nil {
+		return "", fmt.Errorf("error fetching diff of PR #%d: %w", id, err)
+	}
+	if resp.StatusCode() != 200 {
+		return "", fmt.Errorf("unexpected status code %d fetching diff of PR #%d", resp.StatusCode(), id)
+	}
+
+	text := normalizeDiff(string(resp.Body()))
+	diffCacheMutex.Lock()
+	diffCache[key] = cachedDiff{text: text, fetchedAt: time.Now()}
+	diffCacheMutex.Unlock()
+	return text, nil
+}
+
+func normalizeDiff(diffText string) string {
+	lines := strings.Split(diffText, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, "diff --git ") || strings.HasPrefix(line, "--- ") || strings.HasPrefix(line, "+++ ") {
+			line = strings.Replace(line, " src://", " a/", 1)
+			lines[i] = strings.Replace(line, " dst://", " b/", 1)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// FetchDiffContent returns the diff of a single file, cut from the PR diff
+func FetchDiffContent(repo state.RepoContext, id int, path string) (string, error) {
+	diff, err := FetchDiff(repo, id)
+	if err != nil {
+		return "", err
+	}
+	for _, file := range util.SplitDiffFiles(diff) {
+		if file.Path == path {
+			return file.Text, nil
+		}
+	}
+	return "", fmt.Errorf("%s is not changed in PR #%d", path, id)
+}
+
+// FetchDiffstat derives the diffstat from the PR diff, saving a request per file compared to the changes endpoint
+func FetchDiffstat(repo state.RepoContext, id int) ([]types.DiffstatEntry, error) {
+	diff, err := FetchDiff(repo, id)
+	if err != nil {
+		return nil, err
+	}
+	return util.DiffstatFromDiff(diff), nil
+}
+
+// FetchFileAtCommit fetches the raw content of a file at the given commit
+func FetchFileAtCommit(repo state.RepoContext, commitHash string, filePath string) (string, error) {
+	resp, err := createClient().R().
+		SetQueryParam("at", commitHash).
+		Get(fmt.Sprintf("%s/rest/api/1.0/projects/%s/repos/%s/raw/%s", BaseURL, url.PathEscape(repo.Workspace), url.PathEscape(repo.Repo), util.EscapePath(filePath)))
+	if err != nil {
+		return "", fmt.Errorf("error fetching %s at %s: %w", filePath, commitHash, err)
+	}
+	if err := checkStatus(resp, 200); err != nil {
+		return "", err
+	}
+	return string(resp.Body()), nil
+}
+
+// toAnchor anchors an inline comment. Data Center needs to know whether the line was added, removed or
+// is context, which is looked up in the PR diff.
+func toAnchor(repo state.RepoContext, id int, inline *types.Inline) map[string]interface{} {
+	anchor := map[string]interface{}{"path": inline.Path, "diffType": "EFFECTIVE", "fileType": "TO", "lineType": "CONTEXT"}
+	line, newSide := inline.To, true
+	if inline.To == 0 {
+		line, newSide = inline.From, false
+		anchor["fileType"] = "FROM"
+	}
+	anchor["line"] = line
+
+	diff, err := FetchDiffContent(repo, id, inline.Path)
+	if err != nil {
+		log.Printf("[DATACENTER] Anchoring on %s:%d as context: %v", inline.Path, line, err)
+		return anchor
+	}
+	_, hunks := util.ParseDiffHunks(diff)
+	if util.ChangedLineNumbers(hunks, newSide)[line] {
+		if newSide {
+			anchor["lineType"] = "ADDED"
+		} else {
+			anchor["lineType"] = "REMOVED"
+		}
+	}
+	return anchor
+}
+
+// CreateComment adds a comment to a PR, see bitbucket.CreatePRComment
+func CreateComment(repo state.RepoContext, id int, raw string, inline *types.Inline, pending bool) (*types.Comment, error) {
+	body := map[string]interface{}{"text": raw}
+	if pending {
+		body["state"] = "PENDING"
+	}
+	if inline != nil {
+		body["anchor"] = toAnchor(repo, id, inline)
+	}
+
+	resp, err := createClient().R().
+		SetBody(body).
+		SetResult(&comment{}).
+		Post(prURL(repo, "/%d/comments", id))
+	if err != nil {
+		return nil, fmt.Errorf("error creating comment: %w", err)
+	}
+	if err := checkStatus(resp, 201); err != nil {
+		return nil, err
+	}
+
+	created := resp.Result().(*comment)
+	converted := created.toComments(created.Anchor.toInline(), 0)[0]
+	return &converted, nil
+}
+
+// fetchCommentVersion returns the version Data Center requires for optimistic locking of updates and deletes
+func fetchCommentVersion(repo state.RepoContext, id int, commentID int) (int, error) {
+	resp, err := createClient().R().
+		SetResult(&comment{}).
+		Get(prURL(repo, "/%d/comments/%d", id, commentID))
+	if err != nil {
+		return 0, fmt.Errorf("error fetching comment %d: %w", commentID, err)
+	}
+	if err := checkStatus(resp, 200); err != nil {
+		return 0, err
+	}
+	return resp.Result().(*comment).Version, nil
+}
+
+// UpdateComment changes the text of a comment. Drafts keep their state, they are published by PublishDrafts.
+func UpdateComment(repo state.RepoContext, id int, commentID int, raw string) (*types.Comment, error) {
+	version, err := fetchCommentVersion(repo, id, commentID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := createClient().R().
+		SetBody(map[string]interface{}{"version": version, "text": raw}).
+		SetResult(&comment{}).
+		Put(prURL(repo, "/%d/comments/%d", id, commentID))
+	if err != nil {
+		return nil, fmt.Errorf("error updating comment: %w", err)
+	}
+	if err := checkStatus(resp, 200); err != nil {
+		return nil, err
+	}
+
+	updated := resp.Result().(*comment)
+	converted := updated.toComments(updated.Anchor.toInline(), 0)[0]
+	return &converted, nil
+}
+
+func DeleteComment(repo state.RepoContext, id int, commentID int) error {
+	version, err := fetchCommentVersion(repo, id, commentID)
+	if err != nil {
+		return err
+	}
+
+	resp, err := createClient().R().
+		SetQueryParam("version", fmt.Sprintf("%d", version)).
+		Delete(prURL(repo, "/%d/comments/%d", id, commentID))
+	if err != nil {
+		return fmt.Errorf("error deleting comment: %w", err)
+	}
+	return checkStatus(resp, 204)
+}
+
+// PublishDrafts completes my review, which publishes all my pending comments at once
+func PublishDrafts(repo state.RepoContext, id int) error {
+	resp, err := createClient().R().
+		SetBody(map[string]interface{}{}).
+		Put(prURL(repo, "/%d/review", id))
+	if err != nil {
+		return fmt.Errorf("error publishing review: %w", err)
+	}
+	return checkStatus(resp, 200, 204)
+}
+
+// ApprovePR approves the PR as the current user
+func ApprovePR(repo state.RepoContext, id int) error {
+	return setParticipantStatus(repo, id, "APPROVED")
+}
+
+// RequestChangesPR marks the PR as needing work, Data Center's equivalent of requesting changes
+func RequestChangesPR(repo state.RepoContext, id int) error {
+	return setParticipantStatus(repo, id, "NEEDS_WORK")
+}
+
+func setParticipantStatus(repo state.RepoContext, id int, status string) error {
+	if state.CurrentUser == nil || state.CurrentUser.UUID == "" {
+		return fmt.Errorf("the current user is unknown, cannot set the review status")
+	}
+
+	resp, err := createClient().R().
+		SetBody(map[string]string{"status": status}).
+		Put(prURL(repo, "/%d/participants/%s", id, url.PathEscape(state.CurrentUser.UUID)))
+	if err != nil {
+		return fmt.Errorf("error setting review status %s: %w", status, err)
+	}
+	if resp.StatusCode() != 200 {
+		return fmt.Errorf("unexpected status code %d for %s: %s", resp.StatusCode(), status, string(resp.Body()))
+	}
+	return nil
+}
@@ -0,0 +1,303 @@
+package datacenter
+
+import (
+	"fmt"
+	"simple-git-terminal/types"
+	"time"
+)
+
+// JSON shapes of the Data Center REST API 1.0, converted into the Cloud based types the views use
+
+type page[T any] struct {
+	Values        []T  `json:"values"`
+	Size          int  `json:"size"`
+	Start         int  `json:"start"`
+	Limit         int  `json:"limit"`
+	IsLastPage    bool `json:"isLastPage"`
+	NextPageStart int  `json:"nextPageStart"`
+}
+
+type user struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	Slug        string `json:"slug"`
+	DisplayName string `json:"displayName"`
+}
+
+type participant struct {
+	User     user   `json:"user"`
+	Role     string `json:"role"` // AUTHOR, REVIEWER or PARTICIPANT
+	Approved bool   `json:"approved"`
+	Status   string `json:"status"` // APPROVED, NEEDS_WORK or UNAPPROVED
+}
+
+type repository struct {
+	Slug    string `json:"slug"`
+	Name    string `json:"name"`
+	Project struct {
+		Key string `json:"key"`
+	} `json:"project"`
+}
+
+type ref struct {
+	ID           string     `json:"id"`        // refs/heads/feature
+	DisplayID    string     `json:"displayId"` // feature
+	LatestCommit string     `json:"latestCommit"`
+	Repository   repository `json:"repository"`
+}
+
+type links struct {
+	Self []struct {
+		Href string `json:"href"`
+	} `json:"self"`
+}
+
+type pullRequest struct {
+	ID           int           `json:"id"`
+	Version      int           `json:"version"`
+	Title        string        `json:"title"`
+	Description  string        `json:"description"`
+	State        string        `json:"state"`
+	CreatedDate  int64         `json:"createdDate"`
+	UpdatedDate  int64         `json:"updatedDate"`
+	FromRef      ref           `json:"fromRef"`
+	ToRef        ref           `json:"toRef"`
+	Author       participant   `json:"author"`
+	Reviewers    []participant `json:"reviewers"`
+	Participants []participant `json:"participants"`
+	Links        links         `json:"links"`
+	Draft        bool          `json:"draft"`
+	Properties   struct {
+		CommentCount      int `json:"commentCount"`
+		OpenTaskCount     int `json:"openTaskCount"`
+		ResolvedTaskCount int `json:"resolvedTaskCount"`
+	} `json:"properties"`
+}
+
+type anchor struct {
+	Path     string `json:"path"`
+	Line     int    `json:"line"`
+	LineType string `json:"lineType"` // ADDED, REMOVED or CONTEXT
+	FileType string `json:"fileType"` // FROM (old file) or TO (new file)
+}
+
+type comment struct {
+	ID             int       `json:"id"`
+	Version        int       `json:"version"`
+	Text           string    `json:"text"`
+	Author         user      `json:"author"`
+	CreatedDate    int64     `json:"createdDate"`
+	UpdatedDate    int64     `json:"updatedDate"`
+	State          string    `json:"state"`    // OPEN, RESOLVED or PENDING
+	Severity       string    `json:"severity"` // NORMAL, or BLOCKER for tasks
+	ThreadResolved bool      `json:"threadResolved"`
+	ResolvedDate   int64     `json:"resolvedDate"`
+	Resolver       *user     `json:"resolver"`
+	Anchor         *anchor   `json:"anchor"`
+	Comments       []comment `json:"comments"` // Replies
+}
+
+type activity struct {
+	ID             int      `json:"id"`
+	CreatedDate    int64    `json:"createdDate"`
+	User           user     `json:"user"`
+	Action         string   `json:"action"`        // OPENED, UPDATED, RESCOPED, COMMENTED, APPROVED, REVIEWED, MERGED, DECLINED ...
+	CommentAction  string   `json:"commentAction"` // ADDED, EDITED, REPLIED or DELETED for COMMENTED
+	Comment        *comment `json:"comment"`
+	CommentAnchor  *anchor  `json:"commentAnchor"`
+	FromHash       string   `json:"fromHash"`
+	AddedReviewers []user   `json:"addedReviewers"`
+}
+
+// formatDate converts Data Center's epoch milliseconds into the RFC3339 dates of Cloud
+func formatDate(millis int64) string {
+	if millis == 0 {
+		return ""
+	}
+	return time.UnixMilli(millis).UTC().Format(time.RFC3339)
+}
+
+// toUser maps a Data Center user. The user slug takes the place of the Cloud UUID, so comparisons
+// with the current user keep working.
+func (u user) toUser() types.User {
+	return types.User{
+		DisplayName: u.DisplayName,
+		UUID:        u.Slug,
+		AccountID:   fmt.Sprintf("%d", u.ID),
+		Nickname:    u.Name,
+		Type:        "user",
+	}
+}
+
+func (p participant) toParticipant() types.Participant {
+	participantUser := p.User.toUser()
+	var reviewState types.ApprovedState
+	switch p.Status {
+	case "APPROVED":
+		reviewState = types.StateApproved
+	case "NEEDS_WORK":
+		reviewState = types.StateRequestedChanges
+	}
+	return types.Participant{
+		Type:     "participant",
+		User:     &participantUser,
+		Role:     p.Role,
+		Approved: p.Approved,
+		State:    reviewState,
+	}
+}
+
+func (r repository) fullName() string {
+	return r.Project.Key + "/" + r.Slug
+}
+
+func (pr pullRequest) toPR() types.PR {
+	converted := types.PR{
+		ID:           pr.ID,
+		Title:        pr.Title,
+		State:        pr.State,
+		Author:       types.Author{DisplayName: pr.Author.User.DisplayName, Username: pr.Author.User.Name, UUID: pr.Author.User.Slug},
+		CreatedOn:    formatDate(pr.CreatedDate),
+		UpdatedOn:    formatDate(pr.UpdatedDate),
+		Description:  pr.Description,
+		CommentCount: pr.Properties.CommentCount,
+		TaskCount:    pr.Properties.OpenTaskCount,
+		Draft:        pr.Draft,
+
+		ResolvedTaskCount: pr.Properties.ResolvedTaskCount,
+	}
+	if len(pr.Links.Self) > 0 {
+		converted.Links.HTML.Href = pr.Links.Self[0].Href
+	}
+
+	converted.Source.Branch.Name = pr.FromRef.DisplayID
+	converted.Source.Commit.Hash = pr.FromRef.LatestCommit
+	converted.Source.Repository = types.Repository{Name: pr.FromRef.Repository.Name, FullName: pr.FromRef.Repository.fullName()}
+	converted.Destination.Branch.Name = pr.ToRef.DisplayID
+	converted.Destination.Commit.Hash = pr.ToRef.LatestCommit
+	converted.Destination.Repository = types.Repository{Name: pr.ToRef.Repository.Name, FullName: pr.ToRef.Repository.fullName()}
+
+	for _, reviewer := range pr.Reviewers {
+		converted.Reviewers = append(converted.Reviewers, types.Reviewer{
+			DisplayName: reviewer.User.DisplayName,
+			UUID:        reviewer.User.Slug,
+			Nickname:    reviewer.User.Name,
+		})
+	}
+
+	// Cloud lists reviewers among the participants, Data Center keeps them apart
+	for _, reviewer := range pr.Reviewers {
+		converted.Participants = append(converted.Participants, reviewer.toParticipant())
+	}
+	for _, other := range pr.Participants {
+		converted.Participants = append(converted.Participants, other.toParticipant())
+	}
+	return converted
+}
+
+// toInline maps an anchor onto Cloud's inline position, where "to" is the line in the new file
+func (a *anchor) toInline() types.Inline {
+	if a == nil {
+		return types.Inline{}
+	}
+	inline := types.Inline{Path: a.Path}
+	if a.FileType == "FROM" {
+		inline.From = a.Line
+	} else {
+		inline.To = a.Line
+	}
+	return inline
+}
+
+// toComments flattens a comment and its replies into Cloud style comments linked by parent ID
+func (c comment) toComments(inline types.Inline, parentID int) []types.Comment {
+	converted := types.Comment{
+		ID:        c.ID,
+		CreatedOn: formatDate(c.CreatedDate),
+		UpdatedOn: formatDate(c.UpdatedDate),
+		Content:   types.Content{Type: "rendered", Raw: c.Text, Markup: "markdown"},
+		User:      c.Author.toUser(),
+		Pending:   c.State == "PENDING",
+		Type:      "pullrequest_comment",
+		Inline:    inline,
+	}
+	converted.Parent.ID = parentID
+	if c.State == "RESOLVED" || c.ThreadResolved {
+		converted.Resolution = map[string]string{"type": "comment_resolution"}
+	}
+
+	comments := []types.Comment{converted}
+	for _, reply := range c.Comments {
+		comments = append(comments, reply.toComments(inline, c.ID)...)
+	}
+	return comments
+}
+
+func (c comment) isTask() bool {
+	return c.Severity == "BLOCKER"
+}
+
+// toTasks collects the blocker comments among a comment and its replies. Data Center's tasks are blocker
+// comments, attached to the comment they reply to.
+func (c comment) toTasks(parentID int) []types.Task {
+	var tasks []types.Task
+	if c.isTask() {
+		task := types.Task{
+			ID:         c.ID,
+			State:      types.TaskUnresolved,
+			Content:    types.Content{Type: "rendered", Raw: c.Text, Markup: "markdown"},
+			Creator:    c.Author.toUser(),
+			CreatedOn:  formatDate(c.CreatedDate),
+			ResolvedOn: formatDate(c.ResolvedDate),
+			Pending:    c.State == "PENDING",
+		}
+		if c.State == "RESOLVED" {
+			task.State = types.TaskResolved
+		}
+		if c.Resolver != nil {
+			resolver := c.Resolver.toUser()
+			task.ResolvedBy = &resolver
+		}
+		if parentID > 0 {
+			task.Comment = &types.CommentParent{ID: parentID}
+		}
+		tasks = append(tasks, task)
+	}
+	for _, reply := range c.Comments {
+		tasks = append(tasks, reply.toTasks(c.ID)...)
+	}
+	return tasks
+}
+
+func (a activity) toActivity() types.Activity {
+	var converted types.Activity
+	date := formatDate(a.CreatedDate)
+	author := a.User.toUser()
+
+	switch a.Action {
+	case "COMMENTED":
+		if a.Comment != nil {
+			inline := a.CommentAnchor.toInline()
+			if a.Comment.Anchor != nil {
+				inline = a.Comment.Anchor.toInline()
+			}
+			converted.Comment = a.Comment.toComments(inline, 0)[0]
+		}
+	case "APPROVED":
+		converted.Approval = types.Approval{Date: date, User: author}
+	case "REVIEWED":
+		converted.ChangesRequested = types.ChangeRequested{Date: date, User: author}
+	default:
+		// OPENED, UPDATED, RESCOPED, MERGED, DECLINED, REOPENED
+		converted.Update = types.UpdateDetail{State: "OPEN", Author: author, Date: date}
+		if a.Action == "MERGED" || a.Action == "DECLINED" {
+			converted.Update.State = a.Action
+		}
+		converted.Update.Source.Commit.Hash = a.FromHash
+		for _, added := range a.AddedReviewers {
+			converted.Update.Changes.Reviewers.Added = append(converted.Update.Changes.Reviewers.Added,
+				types.Reviewer{DisplayName: added.DisplayName, UUID: added.Slug, Nickname: added.Name})
+		}
+	}
+	return converted
+}
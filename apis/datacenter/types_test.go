@@ -0,0 +1,40 @@
+package datacenter
+
+import (
+	"encoding/json"
+	"simple-git-terminal/types"
+	"testing"
+)
+
+func TestActivityTasks(t *testing.T) {
+	// Newest first, like the activity stream
+	var raw []activity
+	err := json.Unmarshal([]byte(`[
+		{"action": "COMMENTED", "commentAction": "ADDED", "comment": {"id": 3, "severity": "BLOCKER", "state": "OPEN", "text": "newest"}},
+		{"action": "COMMENTED", "commentAction": "EDITED", "comment": {"id": 1, "severity": "BLOCKER", "state": "OPEN", "text": "edited"}},
+		{"action": "APPROVED"},
+		{"action": "COMMENTED", "commentAction": "ADDED", "comment": {"id": 1, "severity": "NORMAL", "text": "question", "comments": [
+			{"id": 2, "severity": "BLOCKER", "state": "RESOLVED", "text": "fix it", "resolver": {"slug": "bob"}}
+		]}}
+	]`), &raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tasks := activityTasks(raw)
+	if len(tasks) != 2 {
+		t.Fatalf("got %d tasks, want 2: %+v", len(tasks), tasks)
+	}
+
+	reply := tasks[0]
+	if reply.ID != 2 || !reply.IsResolved() || reply.Comment == nil || reply.Comment.ID != 1 || reply.ResolvedBy.UUID != "bob" {
+		t.Errorf("task on the reply = %+v", reply)
+	}
+	own := tasks[1]
+	if own.ID != 3 || own.IsResolved() || own.Comment != nil || own.Content.Raw != "newest" {
+		t.Errorf("task on the PR = %+v", own)
+	}
+	if count := types.CountTasks(tasks); count != (types.TaskCount{Open: 1, Resolved: 1}) {
+		t.Errorf("CountTasks = %+v, want 1 open and 1 resolved", count)
+	}
+}
@@ -0,0 +1,161 @@
+package localgit
+
+import (
+	"fmt"
+	"log"
+	"simple-git-terminal/state"
+	"simple-git-terminal/types"
+	"simple-git-terminal/util"
+	"strings"
+)
+
+const ForkRemotePrefix = "bbpr-"
+
+// IsWorkingTreeDirty reports uncommitted changes to tracked files, which would block or be carried over by a checkout
+func IsWorkingTreeDirty() (bool, error) {
+	out, err := util.RunGit("status", "--porcelain", "--untracked-files=no")
+	if err != nil {
+		return false, err
+	}
+	return strings.TrimSpace(out) != "", nil
+}
+
+func CurrentBranch() (string, error) {
+	out, err := util.RunGit("rev-parse", "--abbrev-ref", "HEAD")
+	if err != nil {
+		return "", err
+	}
+	branch := strings.TrimSpace(out)
+	if branch == "HEAD" {
+		return "", fmt.Errorf("HEAD is detached, not on a branch")
+	}
+	return branch, nil
+}
+
+// IsFork reports whether the PR comes from a different repository than the one it targets
+func IsFork(pr *types.PR) bool {
+	source := pr.Source.Repository.FullName
+	destination := pr.Destination.Repository.FullName
+	return source != "" && destination != "" && source != destination
+}
+
+// LocalBranchName is the branch CheckoutPR checks the PR out as
+func LocalBranchName(pr *types.PR) string {
+	if IsFork(pr) {
+		// Fork branches are often named like ours (e.g. master), keep them apart
+		return strings.SplitN(pr.Source.Repository.FullName, "/", 2)[0] + "/" + pr.Source.Branch.Name
+	}
+	return pr.Source.Branch.Name
+}
+
+// IsCheckedOut reports whether the local clone is on the PR's branch
+func IsCheckedOut(pr *types.PR) (bool, error) {
+	branch, err := CurrentBranch()
+	if err != nil {
+		return false, err
+	}
+	return branch == LocalBranchName(pr), nil
+}
+
+// CheckoutResult describes a checkout that moved HEAD to the PR's branch
+type CheckoutResult struct {
+	Branch string
+	// Stash is the message of the stash entry holding the local changes, empty when nothing was stashed
+	Stash string
+	// Warning is set when the branch was checked out but not brought up to date
+	Warning string
+}
+
+// CheckoutPR fetches the PR's source branch and checks it out as a local tracking branch.
+// Progress messages are reported through progress. An error means HEAD did not move.
+func CheckoutPR(pr *types.PR, stash bool, progress func(string)) (CheckoutResult, error) {
+	branch := pr.Source.Branch.Name
+	remote := state.HomeRemote
+	result := CheckoutResult{Branch: LocalBranchName(pr)}
+
+	if IsFork(pr) {
+		fullName := pr.Source.Repository.FullName
+		progress(fmt.Sprintf("Setting up remote for fork %s", fullName))
+
+		forkRemote, err := ensureForkRemote(fullName)
+		if err != nil {
+			return CheckoutResult{}, err
+		}
+		remote = forkRemote
+	}
+
+	progress(fmt.Sprintf("Fetching %s from %s", branch, remote))
+	if _, err := util.RunGit("fetch", "--no-tags", remote, branch); err != nil {
+		return CheckoutResult{}, fmt.Errorf("failed to fetch %s: %w", branch, err)
+	}
+	remoteRef := "refs/remotes/" + remote + "/" + branch
+
+	if stash {
+		progress("Stashing local changes")
+		message := fmt.Sprintf("bbpr: before checking out PR #%d", pr.ID)
+		if _, err := util.RunGit("stash", "push", "-m", message); err != nil {
+			return CheckoutResult{}, fmt.Errorf("failed to stash: %w", err)
+		}
+		result.Stash = message
+	}
+	// A failed checkout leaves the branch as it was, so the stashed changes go back onto it
+	failed := func(err error) error {
+		if !stash {
+			return err
+		}
+		if _, popErr := util.RunGit("stash", "pop"); popErr != nil {
+			return fmt.Errorf("%w; your changes are still stashed, restore them with git stash pop: %v", err, popErr)
+		}
+		return fmt.Errorf("%w; your stashed changes were restored", err)
+	}
+
+	if _, err := util.RunGit("rev-parse", "--verify", "--quiet", "refs/heads/"+result.Branch); err == nil {
+		progress(fmt.Sprintf("Switching to existing branch %s", result.Branch))
+		if _, err := util.RunGit("checkout", result.Branch); err != nil {
+			return CheckoutResult{}, failed(fmt.Errorf("failed to check out %s: %w", result.Branch, err))
+		}
+		if _, err := util.RunGit("merge", "--ff-only", remoteRef); err != nil {
+			log.Printf("[LOCALGIT] Could not fast-forward %s: %v", result.Branch, err)
+			result.Warning = fmt.Sprintf("%s has local commits or diverged, it was not fast-forwarded to %s", result.Branch, remoteRef)
+		}
+		return result, nil
+	}
+
+	progress(fmt.Sprintf("Creating branch %s", result.Branch))
+	if _, err := util.RunGit("checkout", "-b", result.Branch, "--track", remoteRef); err != nil {
+		return CheckoutResult{}, failed(fmt.Errorf("failed to create %s: %w", result.Branch, err))
+	}
+	return result, nil
+}
+
+// PopStash applies the latest stash entry to the working tree and drops it
+func PopStash() error {
+	if _, err := util.RunGit("stash", "pop"); err != nil {
+		return fmt.Errorf("failed to pop stash: %w", err)
+	}
+	return nil
+}
+
+// ensureForkRemote adds a remote for the fork, reusing the URL style (ssh/https) of origin
+func ensureForkRemote(fullName string) (string, error) {
+	owner := strings.SplitN(fullName, "/", 2)[0]
+	remote := ForkRemotePrefix + owner
+
+	if _, err := util.RunGit("remote", "get-url", remote); err == nil {
+		return remote, nil
+	}
+
+	url := "https://bitbucket.org/" + fullName + ".git"
+	if originURL, err := util.RunGit("remote", "get-url", state.HomeRemote); err == nil {
+		ownRepo := state.HomeRepo.FullName()
+		if originURL = strings.TrimSpace(originURL); strings.Contains(originURL, ownRepo) {
+			url = strings.Replace(originURL, ownRepo, fullName, 1)
+		}
+	}
+
+	log.Printf("[LOCALGIT] Adding remote %s -> %s", remote, url)
+	if _, err := util.RunGit("remote", "add", remote, url); err != nil {
+		return "", fmt.Errorf("failed to add remote for fork %s: %w", fullName, err)
+	}
+	return remote, nil
+}
@@ -0,0 +1,247 @@
+package localgit
+
+import (
+	"fmt"
+	"log"
+	"simple-git-terminal/state"
+	"simple-git-terminal/types"
+	"simple-git-terminal/util"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// prRevisions is what a PR diff is computed from: the merge base of destination and source, and the source head
+type prRevisions struct {
+	base    string
+	head    string
+	renames map[string]string // new path -> old path, filled by FetchDiffstat
+}
+
+var (
+	revisionCache = make(map[string]*prRevisions)
+	cacheMutex    sync.Mutex
+)
+
+// resolveRevisions fetches the PR branches into the local clone once per source and destination commit and
+// computes the merge base. The fetch runs without the cache lock, so one slow remote does not block other PRs.
+func resolveRevisions(pr *types.PR) (*prRevisions, error) {
+	head := pr.Source.Commit.Hash
+	destination := pr.Destination.Commit.Hash
+	key := fmt.Sprintf("%d@%s..%s", pr.ID, destination, head)
+
+	cacheMutex.Lock()
+	revisions, ok := revisionCache[key]
+	cacheMutex.Unlock()
+	if ok {
+		return revisions, nil
+	}
+
+	if !util.HasCommit(head) || !util.HasCommit(destination) {
+		fetchBranches(pr.Destination.Branch.Name, pr.Source.Branch.Name)
+	}
+	if !util.HasCommit(head) {
+		return nil, fmt.Errorf("source commit %s is not available locally", head)
+	}
+	// The destination commit Bitbucket reports is what the PR diff is based on, the fetched branch tip stands in
+	// when it is missing
+	if !util.HasCommit(destination) {
+		destination = "refs/remotes/" + state.HomeRemote + "/" + pr.Destination.Branch.Name
+	}
+	if !util.HasCommit(destination) {
+		return nil, fmt.Errorf("destination %s is not available locally", pr.Destination.Branch.Name)
+	}
+
+	base, err := util.RunGit("merge-base", destination, head)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute merge base: %w", err)
+	}
+
+	cacheMutex.Lock()
+	defer cacheMutex.Unlock()
+	// Another caller may have resolved the same PR meanwhile, keep its renames
+	if revisions, ok := revisionCache[key]; ok {
+		return revisions, nil
+	}
+	revisions = &prRevisions{
+		base:    strings.TrimSpace(base),
+		head:    head,
+		renames: make(map[string]string),
+	}
+	revisionCache[key] = revisions
+	log.Printf("[LOCALGIT] PR #%d diff base %s head %s", pr.ID, revisions.base, revisions.head)
+
+	return revisions, nil
+}
+
+// fetchBranches fetches each branch on its own so a missing one (e.g. a fork branch) does not fail the rest
+func fetchBranches(branches ...string) {
+	for _, branch := range branches {
+		if branch == "" {
+			continue
+		}
+		if _, err := util.RunGit("fetch", "--quiet", "--no-tags", state.HomeRemote, branch); err != nil {
+			log.Printf("[LOCALGIT] Could not fetch %s: %v", branch, err)
+		}
+	}
+}
+
+// FetchDiffstat builds the same diffstat entries the Bitbucket diffstat endpoint returns from the local clone
+func FetchDiffstat(pr *types.PR) ([]types.DiffstatEntry, error) {
+	revisions, err := resolveRevisions(pr)
+	if err != nil {
+		return nil, err
+	}
+
+	numstat, err := util.RunGit("diff", "-M", "--numstat", "-z", revisions.base, revisions.head)
+	if err != nil {
+		return nil, err
+	}
+	nameStatus, err := util.RunGit("diff", "-M", "--name-status", "-z", revisions.base, revisions.head)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := parseNameStatus(nameStatus)
+	entries := parseNumstat(numstat, statuses)
+
+	cacheMutex.Lock()
+	for _, entry := range entries {
+		if entry.Status == "renamed" {
+			revisions.renames[entry.New.Path] = entry.Old.Path
+		}
+	}
+	cacheMutex.Unlock()
+
+	return entries, nil
+}
+
+// FetchDiffContent returns the unified diff of a single file in the PR
+func FetchDiffContent(pr *types.PR, path string) (string, error) {
+	revisions, err := resolveRevisions(pr)
+	if err != nil {
+		return "", err
+	}
+
+	paths := []string{path}
+	cacheMutex.Lock()
+	if oldPath, ok := revisions.renames[path]; ok {
+		paths = append(paths, oldPath)
+	}
+	cacheMutex.Unlock()
+
+	args := append([]string{"diff", "-M", revisions.base, revisions.head, "--"}, paths...)
+	return util.RunGit(args...)
+}
+
+// ChangedFilesBetween lists the paths that differ between two commits, both of which must exist locally
+func ChangedFilesBetween(oldCommit string, newCommit string) ([]string, error) {
+	if !util.HasCommit(oldCommit) || !util.HasCommit(newCommit) {
+		return nil, fmt.Errorf("commits %s and %s are not both available locally", oldCommit, newCommit)
+	}
+
+	out, err := util.RunGit("diff", "--name-only", "-z", oldCommit, newCommit)
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, path := range strings.Split(out, "\x00") {
+		if path != "" {
+			paths = append(paths, path)
+		}
+	}
+	return paths, nil
+}
+
+// CommitDiff returns the diff of a single commit against its first parent, or from oldCommit to newCommit
+// when oldCommit is given. The commits must exist locally.
+func CommitDiff(newCommit string, oldCommit string) (string, error) {
+	if !util.HasCommit(newCommit) || (oldCommit != "" && !util.HasCommit(oldCommit)) {
+		return "", fmt.Errorf("commits are not available locally")
+	}
+
+	if oldCommit == "" {
+		return util.RunGit("show", "--format=", "-m", "--first-parent", "-M", newCommit)
+	}
+	return util.RunGit("diff", "-M", oldCommit, newCommit)
+}
+
+// parseNameStatus maps the new path of each file to its Bitbucket status from `git diff --name-status -z`
+func parseNameStatus(output string) map[string]string {
+	statuses := make(map[string]string)
+	fields := strings.Split(strings.TrimSuffix(output, "\x00"), "\x00")
+
+	for i := 0; i < len(fields); i++ {
+		code := fields[i]
+		if code == "" {
+			continue
+		}
+		switch code[0] {
+		case 'R', 'C':
+			if i+2 < len(fields) {
+				statuses[fields[i+2]] = "renamed"
+			}
+			i += 2
+		case 'A':
+			if i+1 < len(fields) {
+				statuses[fields[i+1]] = "added"
+			}
+			i++
+		case 'D':
+			if i+1 < len(fields) {
+				statuses[fields[i+1]] = "removed"
+			}
+			i++
+		default:
+			if i+1 < len(fields) {
+				statuses[fields[i+1]] = "modified"
+			}
+			i++
+		}
+	}
+	return statuses
+}
+
+// parseNumstat reads `git diff --numstat -z`, where renames are "added\tremoved\t\0old\0new" instead of "added\tremoved\tpath"
+func parseNumstat(output string, statuses map[string]string) []types.DiffstatEntry {
+	var entries []types.DiffstatEntry
+	fields := strings.Split(strings.TrimSuffix(output, "\x00"), "\x00")
+
+	for i := 0; i < len(fields); i++ {
+		parts := strings.SplitN(fields[i], "\t", 3)
+		if len(parts) < 3 {
+			continue
+		}
+
+		oldPath, newPath := parts[2], parts[2]
+		if parts[2] == "" && i+2 < len(fields) {
+			oldPath, newPath = fields[i+1], fields[i+2]
+			i += 2
+		}
+
+		// Binary files report "-" for both counts
+		added, _ := strconv.Atoi(parts[0])
+		removed, _ := strconv.Atoi(parts[1])
+
+		status, ok := statuses[newPath]
+		if !ok {
+			status = "modified"
+		}
+
+		entry := types.DiffstatEntry{
+			Type:         "diffstat",
+			LinesAdded:   added,
+			LinesRemoved: removed,
+			Status:       status,
+		}
+		if status != "added" {
+			entry.Old = &types.DiffFile{Path: oldPath}
+		}
+		if status != "removed" {
+			entry.New = &types.DiffFile{Path: newPath}
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
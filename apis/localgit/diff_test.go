@@ -0,0 +1,71 @@
+package localgit
+
+import (
+	"reflect"
+	"simple-git-terminal/types"
+	"testing"
+)
+
+func TestParseNameStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   map[string]string
+	}{
+		{name: "empty", output: "", want: map[string]string{}},
+		{name: "modified", output: "M\x00main.go\x00", want: map[string]string{"main.go": "modified"}},
+		{name: "added and removed", output: "A\x00new.go\x00D\x00old.go\x00",
+			want: map[string]string{"new.go": "added", "old.go": "removed"}},
+		{name: "rename is keyed by the new path", output: "R087\x00util/a.go\x00util/b.go\x00M\x00main.go\x00",
+			want: map[string]string{"util/b.go": "renamed", "main.go": "modified"}},
+		{name: "copy", output: "C100\x00a.go\x00b.go\x00", want: map[string]string{"b.go": "renamed"}},
+		{name: "type change", output: "T\x00link\x00", want: map[string]string{"link": "modified"}},
+		{name: "path with tab and spaces", output: "M\x00dir/a file\tname.go\x00",
+			want: map[string]string{"dir/a file\tname.go": "modified"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseNameStatus(tt.output); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseNameStatus(%q) = %v, want %v", tt.output, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseNumstat(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string
+		statuses map[string]string
+		want     []types.DiffstatEntry
+	}{
+		{name: "empty", output: "", want: nil},
+		{name: "modified", output: "3\t1\tmain.go\x00", statuses: map[string]string{"main.go": "modified"},
+			want: []types.DiffstatEntry{{Type: "diffstat", LinesAdded: 3, LinesRemoved: 1, Status: "modified",
+				Old: &types.DiffFile{Path: "main.go"}, New: &types.DiffFile{Path: "main.go"}}}},
+		{name: "added has no old file", output: "10\t0\tnew.go\x00", statuses: map[string]string{"new.go": "added"},
+			want: []types.DiffstatEntry{{Type: "diffstat", LinesAdded: 10, Status: "added",
+				New: &types.DiffFile{Path: "new.go"}}}},
+		{name: "removed has no new file", output: "0\t4\told.go\x00", statuses: map[string]string{"old.go": "removed"},
+			want: []types.DiffstatEntry{{Type: "diffstat", LinesRemoved: 4, Status: "removed",
+				Old: &types.DiffFile{Path: "old.go"}}}},
+		{name: "rename spans three fields", output: "1\t2\t\x00util/a.go\x00util/b.go\x005\t0\tmain.go\x00",
+			statuses: map[string]string{"util/b.go": "renamed", "main.go": "modified"},
+			want: []types.DiffstatEntry{
+				{Type: "diffstat", LinesAdded: 1, LinesRemoved: 2, Status: "renamed",
+					Old: &types.DiffFile{Path: "util/a.go"}, New: &types.DiffFile{Path: "util/b.go"}},
+				{Type: "diffstat", LinesAdded: 5, Status: "modified",
+					Old: &types.DiffFile{Path: "main.go"}, New: &types.DiffFile{Path: "main.go"}},
+			}},
+		{name: "binary file counts as zero lines", output: "-\t-\tlogo.png\x00",
+			want: []types.DiffstatEntry{{Type: "diffstat", Status: "modified",
+				Old: &types.DiffFile{Path: "logo.png"}, New: &types.DiffFile{Path: "logo.png"}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseNumstat(tt.output, tt.statuses); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseNumstat(%q) = %+v, want %+v", tt.output, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package provider
+
+import (
+	"fmt"
+	"simple-git-terminal/apis/bitbucket"
+	"simple-git-terminal/state"
+	"simple-git-terminal/types"
+)
+
+// Cloud talks to bitbucket.org, about Repo
+type Cloud struct {
+	Repo state.RepoContext
+}
+
+func (Cloud) withRepo(repo state.RepoContext) Provider {
+	return Cloud{Repo: repo}
+}
+
+func (Cloud) Name() string {
+	return "Bitbucket Cloud"
+}
+
+func (Cloud) FetchCurrentUser() (*types.User, error) {
+	return bitbucket.FetchCurrentUser()
+}
+
+func (c Cloud) FetchPRs(filter bitbucket.PRFilter, me *types.User) ([]types.PR, types.Pagination, error) {
+	return bitbucket.FetchPRsByQuery(c.Repo, bitbucket.BuildFilterQuery(filter, me))
+}
+
+func (c Cloud) FetchPR(id int) (*types.PR, error) {
+	return bitbucket.FetchPR(c.Repo, id)
+}
+
+func (c Cloud) FetchActivities(id int) ([]types.Activity, error) {
+	return bitbucket.FetchBitbucketActivities(c.Repo, id)
+}
+
+func (c Cloud) FetchComments(id int) ([]types.Comment, error) {
+	return bitbucket.FetchBitbucketComments(c.Repo, id)
+}
+
+func (c Cloud) FetchDiff(id int) (string, error) {
+	return bitbucket.FetchBitbucketDiff(c.Repo, id)
+}
+
+func (c Cloud) FetchDiffContent(id int, path string) (string, error) {
+	return bitbucket.FetchBitbucketDiffContent(c.Repo, id, path)
+}
+
+func (c Cloud) FetchDiffstat(id int) ([]types.DiffstatEntry, error) {
+	return bitbucket.FetchBitbucketDiffstat(c.Repo, id)
+}
+
+func (c Cloud) FetchFileAtCommit(commitHash string, path string) (string, error) {
+	return bitbucket.FetchFileAtCommit(c.Repo, commitHash, path)
+}
+
+func (c Cloud) CreateComment(id int, raw string, inline *types.Inline, pending bool) (*types.Comment, error) {
+	return bitbucket.CreatePRComment(c.Repo, id, raw, inline, pending)
+}
+
+func (c Cloud) UpdateComment(id int, commentID int, raw string, pending bool) (*types.Comment, error) {
+	return bitbucket.UpdatePRComment(c.Repo, id, commentID, raw, pending)
+}
+
+func (c Cloud) DeleteComment(id int, commentID int) error {
+	return bitbucket.DeletePRComment(c.Repo, id, commentID)
+}
+
+// PublishDrafts publishes every draft on its own, Cloud has no endpoint to submit a review
+func (c Cloud) PublishDrafts(id int, drafts []types.Comment) error {
+	for _, comment := range drafts {
+		if _, err := bitbucket.UpdatePRComment(c.Repo, id, comment.ID, comment.Content.Raw, false); err != nil {
+			return fmt.Errorf("failed to publish comment %d: %w", comment.ID, err)
+		}
+	}
+	return nil
+}
+
+func (c Cloud) ApprovePR(id int) error {
+	return bitbucket.ApprovePR(c.Repo, id)
+}
+
+func (c Cloud) RequestChangesPR(id int) error {
+	return bitbucket.RequestChangesPR(c.Repo, id)
+}
+
+func (c Cloud) UpdatePR(id int, update types.PRUpdate) (*types.PR, error) {
+	return bitbucket.UpdatePR(c.Repo, id, update)
+}
+
+func (c Cloud) FetchBranchNames() ([]string, error) {
+	return bitbucket.FetchBranchNames(c.Repo)
+}
+
+func (c Cloud) FetchTasks(id int) ([]types.Task, error) {
+	return bitbucket.FetchPRTasks(c.Repo, id)
+}
+
+func (c Cloud) CreateTask(id int, commentID int, raw string) (*types.Task, error) {
+	return bitbucket.CreatePRTask(c.Repo, id, commentID, raw)
+}
+
+func (c Cloud) SetTaskResolved(id int, taskID int, resolved bool) error {
+	return bitbucket.SetPRTaskResolved(c.Repo, id, taskID, resolved)
+}
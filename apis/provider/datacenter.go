@@ -0,0 +1,108 @@
+package provider
+
+import (
+	"simple-git-terminal/apis/bitbucket"
+	"simple-git-terminal/apis/datacenter"
+	"simple-git-terminal/state"
+	"simple-git-terminal/types"
+)
+
+// DataCenter talks to a Bitbucket Server or Data Center instance, about Repo
+type DataCenter struct {
+	Repo state.RepoContext
+}
+
+func (DataCenter) withRepo(repo state.RepoContext) Provider {
+	return DataCenter{Repo: repo}
+}
+
+func (DataCenter) Name() string {
+	return "Bitbucket Data Center at " + datacenter.BaseURL
+}
+
+func (DataCenter) FetchCurrentUser() (*types.User, error) {
+	return datacenter.FetchCurrentUser()
+}
+
+func (d DataCenter) FetchPRs(filter bitbucket.PRFilter, me *types.User) ([]types.PR, types.Pagination, error) {
+	page := 1
+	if state.Pagination != nil {
+		page = state.Pagination.Page
+	}
+	return datacenter.FetchPRs(d.Repo, filter, me, state.PRSort, page)
+}
+
+func (d DataCenter) FetchPR(id int) (*types.PR, error) {
+	return datacenter.FetchPR(d.Repo, id)
+}
+
+func (d DataCenter) FetchActivities(id int) ([]types.Activity, error) {
+	return datacenter.FetchActivities(d.Repo, id)
+}
+
+func (d DataCenter) FetchComments(id int) ([]types.Comment, error) {
+	return datacenter.FetchComments(d.Repo, id)
+}
+
+func (d DataCenter) FetchDiff(id int) (string, error) {
+	return datacenter.FetchDiff(d.Repo, id)
+}
+
+func (d DataCenter) FetchDiffContent(id int, path string) (string, error) {
+	return datacenter.FetchDiffContent(d.Repo, id, path)
+}
+
+func (d DataCenter) FetchDiffstat(id int) ([]types.DiffstatEntry, error) {
+	return datacenter.FetchDiffstat(d.Repo, id)
+}
+
+func (d DataCenter) FetchFileAtCommit(commitHash string, path string) (string, error) {
+	return datacenter.FetchFileAtCommit(d.Repo, commitHash, path)
+}
+
+func (d DataCenter) CreateComment(id int, raw string, inline *types.Inline, pending bool) (*types.Comment, error) {
+	return datacenter.CreateComment(d.Repo, id, raw, inline, pending)
+}
+
+// UpdateComment ignores pending, drafts are only published together by PublishDrafts
+func (d DataCenter) UpdateComment(id int, commentID int, raw string, pending bool) (*types.Comment, error) {
+	return datacenter.UpdateComment(d.Repo, id, commentID, raw)
+}
+
+func (d DataCenter) DeleteComment(id int, commentID int) error {
+	return datacenter.DeleteComment(d.Repo, id, commentID)
+}
+
+// PublishDrafts completes the review, which publishes all drafts at once
+func (d DataCenter) PublishDrafts(id int, drafts []types.Comment) error {
+	return datacenter.PublishDrafts(d.Repo, id)
+}
+
+func (d DataCenter) ApprovePR(id int) error {
+	return datacenter.ApprovePR(d.Repo, id)
+}
+
+func (d DataCenter) RequestChangesPR(id int) error {
+	return datacenter.RequestChangesPR(d.Repo, id)
+}
+
+func (d DataCenter) UpdatePR(id int, update types.PRUpdate) (*types.PR, error) {
+	return datacenter.UpdatePR(d.Repo, id, update)
+}
+
+func (d DataCenter) FetchBranchNames() ([]string, error) {
+	return datacenter.FetchBranchNames(d.Repo)
+}
+
+// FetchTasks lists the blocker comments, Data Center's tasks
+func (d DataCenter) FetchTasks(id int) ([]types.Task, error) {
+	return datacenter.FetchTasks(d.Repo, id)
+}
+
+func (d DataCenter) CreateTask(id int, commentID int, raw string) (*types.Task, error) {
+	return datacenter.CreateTask(d.Repo, id, commentID, raw)
+}
+
+func (d DataCenter) SetTaskResolved(id int, taskID int, resolved bool) error {
+	return datacenter.SetTaskResolved(d.Repo, id, taskID, resolved)
+}
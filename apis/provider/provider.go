@@ -0,0 +1,105 @@
+package provider
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"simple-git-terminal/apis/bitbucket"
+	"simple-git-terminal/apis/datacenter"
+	"simple-git-terminal/config"
+	"simple-git-terminal/state"
+	"simple-git-terminal/types"
+	"simple-git-terminal/util"
+)
+
+// Provider is the part of the Bitbucket API that differs between Cloud and Data Center. Each value works
+// on one repository, whose workspace holds the project key on Data Center.
+type Provider interface {
+	Name() string
+	// withRepo returns the same provider working on repo
+	withRepo(repo state.RepoContext) Provider
+	FetchCurrentUser() (*types.User, error)
+	// FetchPRs fetches the page state.Pagination.Page of the PRs matching filter, sorted by state.PRSort
+	FetchPRs(filter bitbucket.PRFilter, me *types.User) ([]types.PR, types.Pagination, error)
+	FetchPR(id int) (*types.PR, error)
+	FetchActivities(id int) ([]types.Activity, error)
+	FetchComments(id int) ([]types.Comment, error)
+	FetchDiff(id int) (string, error)
+	FetchDiffContent(id int, path string) (string, error)
+	FetchDiffstat(id int) ([]types.DiffstatEntry, error)
+	FetchFileAtCommit(commitHash string, path string) (string, error)
+	CreateComment(id int, raw string, inline *types.Inline, pending bool) (*types.Comment, error)
+	UpdateComment(id int, commentID int, raw string, pending bool) (*types.Comment, error)
+	DeleteComment(id int, commentID int) error
+	// PublishDrafts makes my pending comments visible to everyone
+	PublishDrafts(id int, drafts []types.Comment) error
+	ApprovePR(id int) error
+	RequestChangesPR(id int) error
+	UpdatePR(id int, update types.PRUpdate) (*types.PR, error)
+	// FetchBranchNames lists the branches of the repository, most recently updated first
+	FetchBranchNames() ([]string, error)
+	FetchTasks(id int) ([]types.Task, error)
+	// CreateTask adds a task to the PR, attached to the comment unless commentID is 0
+	CreateTask(id int, commentID int, raw string) (*types.Task, error)
+	SetTaskResolved(id int, taskID int, resolved bool) error
+}
+
+var current Provider = Cloud{}
+
+// Current returns the provider picked by Setup, Cloud until then, working on the repository bbpr was started for
+func Current() Provider {
+	return current.withRepo(state.CurrentRepo())
+}
+
+// ForPR returns the provider working on the repository of pr, which is not the one of Current on the dashboard
+func ForPR(pr *types.PR) Provider {
+	return current.withRepo(state.RepoOfPR(pr))
+}
+
+// IsCloud reports whether features only Bitbucket Cloud offers (pipelines, BBQL, build statuses,
+// workspaces) are available
+func IsCloud() bool {
+	_, ok := current.(Cloud)
+	return ok
+}
+
+// Setup picks the provider from the config, or else from the host of the origin remote
+func Setup(cfg config.ProviderConfig) error {
+	if cfg.BaseURL != "" {
+		parsed, err := url.Parse(cfg.BaseURL)
+		if err != nil || parsed.Host == "" {
+			return fmt.Errorf("invalid provider.base_url %q", cfg.BaseURL)
+		}
+		util.DataCenterHost = parsed.Hostname()
+	}
+
+	remote, remoteErr := util.GetRemote()
+	providerType := cfg.Type
+	if providerType == "" {
+		providerType = config.ProviderCloud
+		// Remotes given by -workspace and -repo have no host
+		if remoteErr == nil && remote.Host != "" && !remote.IsCloud() {
+			providerType = config.ProviderDataCenter
+		}
+	}
+
+	switch providerType {
+	case config.ProviderCloud:
+		current = Cloud{}
+	case config.ProviderDataCenter:
+		baseURL := cfg.BaseURL
+		if baseURL == "" && remoteErr == nil {
+			baseURL = remote.BaseURL
+		}
+		if baseURL == "" {
+			return fmt.Errorf("the Data Center server is unknown, set provider.base_url in %s", config.Path())
+		}
+		datacenter.Configure(baseURL)
+		current = DataCenter{}
+	default:
+		return fmt.Errorf("unknown provider.type %q, use %q or %q", providerType, config.ProviderCloud, config.ProviderDataCenter)
+	}
+
+	log.Printf("[PROVIDER] Using %s", current.Name())
+	return nil
+}
@@ -0,0 +1,298 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// Environment variables, which take precedence over stored accounts
+const (
+	EnvToken               = "BITBUCKET_AUTH_TOKEN"
+	EnvAppPassword         = "BITBUCKET_APP_PASSWORD"
+	EnvAppPasswordUsername = "BITBUCKET_APP_USERNAME"
+	EnvAccount             = "BBPR_ACCOUNT"
+)
+
+const (
+	TypeOAuth = "oauth" // OAuth consumer, refreshed automatically
+	TypeToken = "token" // API token, access token or personal access token; with a username sent as basic auth
+
+	DefaultAccountName = "default"
+	CloudHost          = "bitbucket.org"
+)
+
+// Account is a set of stored credentials
+type Account struct {
+	Name         string    `json:"name"`
+	Type         string    `json:"type"`
+	Host         string    `json:"host"` // bitbucket.org, or the Data Center server URL
+	Username     string    `json:"username,omitempty"`
+	Token        string    `json:"token"`
+	RefreshToken string    `json:"refresh_token,omitempty"`
+	ExpiresAt    time.Time `json:"expires_at,omitempty"`
+	ClientID     string    `json:"client_id,omitempty"` // OAuth consumer, needed to refresh the token
+	ClientSecret string    `json:"client_secret,omitempty"`
+}
+
+// Credentials is everything `bbpr auth login` stored
+type Credentials struct {
+	Default  string              `json:"default"`
+	Accounts map[string]*Account `json:"accounts"`
+}
+
+// SelectedAccount overrides the default account, set from the -account flag
+var SelectedAccount string
+
+var (
+	credentialsMutex sync.Mutex
+	cached           *Credentials
+	cachedStore      string
+)
+
+// Load reads the credentials from the first store that has them
+func Load() (*Credentials, string, error) {
+	credentialsMutex.Lock()
+	defer credentialsMutex.Unlock()
+	return load()
+}
+
+func load() (*Credentials, string, error) {
+	if cached != nil {
+		return cached, cachedStore, nil
+	}
+
+	for _, candidate := range stores() {
+		data, err := candidate.load()
+		if errors.Is(err, errNotStored) {
+			continue
+		}
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to load credentials from %s: %w", candidate.name(), err)
+		}
+		creds := &Credentials{}
+		if err := json.Unmarshal(data, creds); err != nil {
+			return nil, "", fmt.Errorf("failed to parse credentials from %s: %w", candidate.name(), err)
+		}
+		if creds.Accounts == nil {
+			creds.Accounts = make(map[string]*Account)
+		}
+		cached, cachedStore = creds, candidate.name()
+		return cached, cachedStore, nil
+	}
+	return &Credentials{Accounts: make(map[string]*Account)}, "", nil
+}
+
+// Save writes the credentials to the first store that accepts them, and clears the others so a
+// stale copy is never picked up
+func Save(creds *Credentials) (string, error) {
+	credentialsMutex.Lock()
+	defer credentialsMutex.Unlock()
+	return save(creds)
+}
+
+func save(creds *Credentials) (string, error) {
+	data, err := json.MarshalIndent(creds, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	var failures []error
+	for _, candidate := range stores() {
+		if !candidate.available() {
+			continue
+		}
+		if err := candidate.save(data); err != nil {
+			log.Printf("[AUTH] Could not save to %s: %v", candidate.name(), err)
+			failures = append(failures, err)
+			continue
+		}
+		for _, other := range stores() {
+			if other.name() != candidate.name() {
+				if err := other.remove(); err != nil {
+					log.Printf("[AUTH] Could not clear %s: %v", other.name(), err)
+				}
+			}
+		}
+		cached, cachedStore = creds, candidate.name()
+		return candidate.name(), nil
+	}
+	if len(failures) > 0 {
+		return "", fmt.Errorf("failed to save credentials: %w", failures[0])
+	}
+	return "", fmt.Errorf("no credential store available, check credential_store in the config")
+}
+
+// ActiveAccountName is the account used for API calls: -account, then BBPR_ACCOUNT, then the default
+func ActiveAccountName(creds *Credentials) string {
+	if SelectedAccount != "" {
+		return SelectedAccount
+	}
+	if name := os.Getenv(EnvAccount); name != "" {
+		return name
+	}
+	return creds.Default
+}
+
+// accountFor picks the account used for API calls to host: the active one when it belongs to host, or else
+// the first stored account of host. An account chosen with -account or BBPR_ACCOUNT is never replaced.
+func (c *Credentials) accountFor(host string) (*Account, error) {
+	name := ActiveAccountName(c)
+	if account, ok := c.Accounts[name]; ok {
+		if sameHost(account.Host, host) {
+			return account, nil
+		}
+		if name != c.Default {
+			return nil, fmt.Errorf("account %q is for %s, not %s", name, account.Host, host)
+		}
+	} else if name != "" && name != c.Default {
+		return nil, fmt.Errorf("no account named %q, run `bbpr auth login --account %s`", name, name)
+	}
+
+	for _, other := range c.AccountNames() {
+		if sameHost(c.Accounts[other].Host, host) {
+			return c.Accounts[other], nil
+		}
+	}
+	return nil, nil
+}
+
+// sameHost compares bitbucket.org or Data Center server URLs by their host, ignoring scheme and case
+func sameHost(a string, b string) bool {
+	hostOf := func(address string) string {
+		if parsed, err := url.Parse(address); err == nil && parsed.Host != "" {
+			address = parsed.Host
+		}
+		return strings.ToLower(strings.TrimSuffix(address, "/"))
+	}
+	return hostOf(a) == hostOf(b)
+}
+
+// ActiveAccount returns the stored account used for API calls, nil when there is none
+func ActiveAccount() (*Account, error) {
+	creds, _, err := Load()
+	if err != nil {
+		return nil, err
+	}
+	name := ActiveAccountName(creds)
+	account, ok := creds.Accounts[name]
+	if !ok {
+		if name != "" && name != creds.Default {
+			return nil, fmt.Errorf("no account named %q, run `bbpr auth login --account %s`", name, name)
+		}
+		return nil, nil
+	}
+	return account, nil
+}
+
+// AccountNames returns the stored account names, sorted
+func (c *Credentials) AccountNames() []string {
+	var names []string
+	for name := range c.Accounts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// HasEnvCredentials reports whether credentials come from environment variables
+func HasEnvCredentials() bool {
+	return os.Getenv(EnvToken) != "" || (os.Getenv(EnvAppPasswordUsername) != "" && os.Getenv(EnvAppPassword) != "")
+}
+
+// HasCredentials reports whether API calls can be authenticated at all
+func HasCredentials() bool {
+	if HasEnvCredentials() {
+		return true
+	}
+	account, err := ActiveAccount()
+	if err != nil {
+		log.Printf("[AUTH] %v", err)
+	}
+	return account != nil
+}
+
+// Configure authenticates every request of client to host, with the environment variables or the stored
+// account of that host (see accountFor). OAuth tokens are refreshed shortly before they expire.
+func Configure(client *resty.Client, host string) error {
+	if token := os.Getenv(EnvToken); token != "" {
+		client.SetAuthToken(token)
+		return nil
+	}
+	username, appPassword := os.Getenv(EnvAppPasswordUsername), os.Getenv(EnvAppPassword)
+	if username != "" && appPassword != "" {
+		client.SetBasicAuth(username, appPassword)
+		return nil
+	}
+
+	creds, _, err := Load()
+	if err != nil {
+		return err
+	}
+	account, err := creds.accountFor(host)
+	if err != nil {
+		return err
+	}
+	if account == nil {
+		return fmt.Errorf("not logged in to %s, run `bbpr auth login --host %s` or set %s", host, host, EnvToken)
+	}
+
+	switch {
+	case account.Type == TypeOAuth:
+		name := account.Name
+		client.OnBeforeRequest(func(_ *resty.Client, request *resty.Request) error {
+			token, err := AccessToken(name)
+			if err != nil {
+				return err
+			}
+			request.SetAuthToken(token)
+			return nil
+		})
+	case account.Username != "":
+		client.SetBasicAuth(account.Username, account.Token)
+	default:
+		client.SetAuthToken(account.Token)
+	}
+	log.Printf("[AUTH] Using account %s (%s)", account.Name, account.Type)
+	return nil
+}
+
+// AccessToken returns a valid access token of an OAuth account, refreshing and storing it when it
+// is about to expire
+func AccessToken(name string) (string, error) {
+	credentialsMutex.Lock()
+	defer credentialsMutex.Unlock()
+
+	creds, _, err := load()
+	if err != nil {
+		return "", err
+	}
+	account, ok := creds.Accounts[name]
+	if !ok {
+		return "", fmt.Errorf("account %q was logged out", name)
+	}
+	if account.Type != TypeOAuth || time.Until(account.ExpiresAt) > time.Minute {
+		return account.Token, nil
+	}
+
+	log.Printf("[AUTH] Refreshing the access token of %s", name)
+	token, err := refreshToken(account.ClientID, account.ClientSecret, account.RefreshToken)
+	if err != nil {
+		return "", fmt.Errorf("failed to refresh the access token of %s, run `bbpr auth login --account %s`: %w", name, name, err)
+	}
+	token.applyTo(account)
+	if _, err := save(creds); err != nil {
+		// The new token still works for this session
+		log.Printf("[AUTH] %v", err)
+	}
+	return account.Token, nil
+}
@@ -0,0 +1,74 @@
+package auth
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAccountFor(t *testing.T) {
+	creds := &Credentials{
+		Default: "work",
+		Accounts: map[string]*Account{
+			"cloud":  {Name: "cloud", Host: CloudHost},
+			"work":   {Name: "work", Host: "https://git.example.com"},
+			"backup": {Name: "backup", Host: "https://GIT.example.com/"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		selected string
+		host     string
+		want     string
+		wantErr  bool
+	}{
+		{name: "default matches", host: "https://git.example.com", want: "work"},
+		{name: "default of another host", host: CloudHost, want: "cloud"},
+		{name: "selected matches", selected: "backup", host: "https://git.example.com", want: "backup"},
+		{name: "selected of another host", selected: "cloud", host: "https://git.example.com", wantErr: true},
+		{name: "selected unknown", selected: "missing", host: CloudHost, wantErr: true},
+		{name: "no account of host", host: "https://other.example.com"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(EnvAccount, "")
+			SelectedAccount = tt.selected
+			defer func() { SelectedAccount = "" }()
+
+			account, err := creds.accountFor(tt.host)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			got := ""
+			if account != nil {
+				got = account.Name
+			}
+			if got != tt.want {
+				t.Errorf("account = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWritePrivateFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credentials.json")
+	// A leftover temp file of an interrupted write must not be reused
+	if err := os.WriteFile(path+".tmp", []byte("old"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := writePrivateFile(path, []byte("secret")); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("mode = %v, want 0600", info.Mode().Perm())
+	}
+	if data, _ := os.ReadFile(path); string(data) != "secret" {
+		t.Errorf("content = %q", data)
+	}
+}
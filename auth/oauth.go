@@ -0,0 +1,199 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// Bitbucket Cloud OAuth 2.0 endpoints
+const (
+	OAuthAuthorizeURL = "https://bitbucket.org/site/oauth2/authorize"
+	OAuthTokenURL     = "https://bitbucket.org/site/oauth2/access_token"
+
+	EnvOAuthClientID     = "BITBUCKET_OAUTH_CLIENT_ID"
+	EnvOAuthClientSecret = "BITBUCKET_OAUTH_CLIENT_SECRET"
+
+	// The OAuth consumer's callback URL must be http://127.0.0.1:<port>/callback
+	DefaultCallbackPort = 8976
+	callbackPath        = "/callback"
+	loginTimeout        = 5 * time.Minute
+)
+
+type tokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"` // Seconds
+	Error        string `json:"error"`
+	Description  string `json:"error_description"`
+}
+
+func (t tokenResponse) applyTo(account *Account) {
+	account.Token = t.AccessToken
+	if t.RefreshToken != "" {
+		account.RefreshToken = t.RefreshToken
+	}
+	account.ExpiresAt = time.Now().Add(time.Duration(t.ExpiresIn) * time.Second)
+}
+
+// LoginOAuth runs the authorization code flow: the browser authorizes the consumer and Bitbucket redirects
+// to a loopback server, which receives the code that is exchanged for tokens
+func LoginOAuth(name string, clientID string, clientSecret string, port int, openBrowser func(string) error, out io.Writer) (*Account, error) {
+	listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen for the OAuth callback on port %d: %w", port, err)
+	}
+
+	stateBytes := make([]byte, 16)
+	if _, err := rand.Read(stateBytes); err != nil {
+		return nil, err
+	}
+	state := hex.EncodeToString(stateBytes)
+
+	type callbackResult struct {
+		code string
+		err  error
+	}
+	results := make(chan callbackResult, 1)
+	mux := http.NewServeMux()
+	mux.HandleFunc(callbackPath, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		result := callbackResult{code: query.Get("code")}
+		switch {
+		case query.Get("state") != state:
+			result.err = fmt.Errorf("the OAuth callback has an unexpected state, try again")
+		case query.Get("error") != "":
+			result.err = fmt.Errorf("authorization failed: %s %s", query.Get("error"), query.Get("error_description"))
+		case result.code == "":
+			result.err = fmt.Errorf("the OAuth callback has no code")
+		}
+		if result.err != nil {
+			http.Error(w, result.err.Error(), http.StatusBadRequest)
+		} else {
+			fmt.Fprintln(w, "bbpr is logged in, you can close this tab.")
+		}
+		select {
+		case results <- result:
+		default:
+		}
+	})
+	server := &http.Server{Handler: mux}
+	go server.Serve(listener)
+	defer server.Shutdown(context.Background())
+
+	authorizeURL := fmt.Sprintf("%s?client_id=%s&response_type=code&state=%s", OAuthAuthorizeURL, url.QueryEscape(clientID), state)
+	fmt.Fprintf(out, "Authorize bbpr in your browser:\n  %s\n", authorizeURL)
+	if err := openBrowser(authorizeURL); err != nil {
+		log.Printf("[AUTH] Could not open the browser: %v", err)
+	}
+
+	var result callbackResult
+	select {
+	case result = <-results:
+	case <-time.After(loginTimeout):
+		return nil, fmt.Errorf("timed out waiting for the authorization")
+	}
+	if result.err != nil {
+		return nil, result.err
+	}
+
+	token, err := requestToken(clientID, clientSecret, map[string]string{"grant_type": "authorization_code", "code": result.code})
+	if err != nil {
+		return nil, err
+	}
+	account := &Account{Name: name, Type: TypeOAuth, Host: CloudHost, ClientID: clientID, ClientSecret: clientSecret}
+	token.applyTo(account)
+	return account, nil
+}
+
+func refreshToken(clientID string, clientSecret string, refresh string) (tokenResponse, error) {
+	if refresh == "" {
+		return tokenResponse{}, fmt.Errorf("no refresh token stored")
+	}
+	return requestToken(clientID, clientSecret, map[string]string{"grant_type": "refresh_token", "refresh_token": refresh})
+}
+
+func requestToken(clientID string, clientSecret string, form map[string]string) (tokenResponse, error) {
+	resp, err := resty.New().R().
+		SetBasicAuth(clientID, clientSecret).
+		SetFormData(form).
+		SetResult(&tokenResponse{}).
+		SetError(&tokenResponse{}).
+		Post(OAuthTokenURL)
+	if err != nil {
+		return tokenResponse{}, fmt.Errorf("error requesting an access token: %w", err)
+	}
+	if resp.StatusCode() != 200 {
+		if failure, ok := resp.Error().(*tokenResponse); ok && failure.Error != "" {
+			return tokenResponse{}, fmt.Errorf("%s: %s", failure.Error, failure.Description)
+		}
+		return tokenResponse{}, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode(), string(resp.Body()))
+	}
+	return *resp.Result().(*tokenResponse), nil
+}
+
+// VerifyStored is Verify for an account that was saved before, refreshing its OAuth token first when
+// it is about to expire
+func VerifyStored(account *Account) (string, error) {
+	if account.Type == TypeOAuth {
+		token, err := AccessToken(account.Name)
+		if err != nil {
+			return "", err
+		}
+		fresh := *account
+		fresh.Token = token
+		account = &fresh
+	}
+	return Verify(account)
+}
+
+// Verify checks the credentials of an account against its server and returns the user they belong to.
+// The token is used as is, so a new login can be checked before it is saved.
+func Verify(account *Account) (string, error) {
+	client := resty.New()
+	switch {
+	case account.Type == TypeOAuth:
+		client.SetAuthToken(account.Token)
+	case account.Username != "":
+		client.SetBasicAuth(account.Username, account.Token)
+	default:
+		client.SetAuthToken(account.Token)
+	}
+
+	if account.Host == CloudHost {
+		var user struct {
+			DisplayName string `json:"display_name"`
+		}
+		resp, err := client.R().SetResult(&user).Get("https://api.bitbucket.org/2.0/user")
+		if err != nil {
+			return "", err
+		}
+		if resp.StatusCode() != 200 {
+			return "", fmt.Errorf("unexpected status code %d", resp.StatusCode())
+		}
+		return user.DisplayName, nil
+	}
+
+	// Data Center reports the user in a header of any authenticated response
+	resp, err := client.R().Get(strings.TrimSuffix(account.Host, "/") + "/rest/api/1.0/application-properties")
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode() != 200 {
+		return "", fmt.Errorf("unexpected status code %d", resp.StatusCode())
+	}
+	if username := resp.Header().Get("X-AUSERNAME"); username != "" {
+		return username, nil
+	}
+	return "", fmt.Errorf("the server accepted the request anonymously, the token is not valid")
+}
@@ -0,0 +1,265 @@
+package auth
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"simple-git-terminal/config"
+	"strings"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const (
+	EnvCredentialsPassphrase = "BBPR_CREDENTIALS_PASSPHRASE"
+
+	keyringService = "bbpr"
+	keyringAccount = "credentials"
+
+	// PBKDF2 iterations deriving the key of the encrypted credentials file
+	pbkdf2Iterations = 210000
+)
+
+// errNotStored is returned by a store that holds no credentials
+var errNotStored = errors.New("no credentials stored")
+
+// store is a place the credentials blob can be kept
+type store interface {
+	name() string
+	available() bool
+	load() ([]byte, error)
+	save(data []byte) error
+	remove() error
+}
+
+// stores returns the candidate stores in order of preference for the configured credential_store
+func stores() []store {
+	all := []store{keyringStore{}, encryptedFileStore{}, plainFileStore{}}
+	switch config.Current.CredentialStore {
+	case config.CredentialStoreKeyring:
+		return all[:1]
+	case config.CredentialStoreEncrypted:
+		return all[1:2]
+	case config.CredentialStoreFile:
+		return all[2:]
+	}
+	return all
+}
+
+// keyringStore uses the macOS Keychain through `security`, or the Secret Service through `secret-tool` on Linux
+type keyringStore struct{}
+
+func (keyringStore) name() string {
+	return "OS keyring"
+}
+
+func (keyringStore) available() bool {
+	tool := map[string]string{"darwin": "security", "linux": "secret-tool"}[runtime.GOOS]
+	if tool == "" {
+		return false
+	}
+	_, err := exec.LookPath(tool)
+	return err == nil
+}
+
+func (s keyringStore) load() ([]byte, error) {
+	if !s.available() {
+		return nil, errNotStored
+	}
+	var cmd *exec.Cmd
+	if runtime.GOOS == "darwin" {
+		cmd = exec.Command("security", "find-generic-password", "-s", keyringService, "-a", keyringAccount, "-w")
+	} else {
+		cmd = exec.Command("secret-tool", "lookup", "service", keyringService, "account", keyringAccount)
+	}
+	out, err := cmd.Output()
+	if err != nil || len(bytes.TrimSpace(out)) == 0 {
+		// Both tools fail when nothing is stored
+		return nil, errNotStored
+	}
+	return bytes.TrimSpace(out), nil
+}
+
+func (keyringStore) save(data []byte) error {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "darwin" {
+		// Arguments show up in ps, so the command is read from stdin by the interactive mode. The hex
+		// encoding of -X needs no quoting.
+		cmd = exec.Command("security", "-i")
+		cmd.Stdin = strings.NewReader(fmt.Sprintf("add-generic-password -U -s %s -a %s -X %s\n",
+			keyringService, keyringAccount, hex.EncodeToString(data)))
+	} else {
+		cmd = exec.Command("secret-tool", "store", "--label=bbpr credentials", "service", keyringService, "account", keyringAccount)
+		cmd.Stdin = bytes.NewReader(data)
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("keyring: %v %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func (s keyringStore) remove() error {
+	if !s.available() {
+		return nil
+	}
+	if runtime.GOOS == "darwin" {
+		// Fails when there is nothing to delete, which is fine
+		_ = exec.Command("security", "delete-generic-password", "-s", keyringService, "-a", keyringAccount).Run()
+		return nil
+	}
+	return exec.Command("secret-tool", "clear", "service", keyringService, "account", keyringAccount).Run()
+}
+
+// encryptedFileStore encrypts the credentials with AES-GCM under a key derived from BBPR_CREDENTIALS_PASSPHRASE
+type encryptedFileStore struct{}
+
+type encryptedFile struct {
+	Salt  []byte `json:"salt"`
+	Nonce []byte `json:"nonce"`
+	Data  []byte `json:"data"`
+}
+
+func (encryptedFileStore) path() string {
+	return filepath.Join(config.Dir(), "credentials.enc")
+}
+
+func (encryptedFileStore) name() string {
+	return "encrypted file"
+}
+
+func (encryptedFileStore) available() bool {
+	return os.Getenv(EnvCredentialsPassphrase) != ""
+}
+
+func (s encryptedFileStore) load() ([]byte, error) {
+	raw, err := os.ReadFile(s.path())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, errNotStored
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !s.available() {
+		return nil, fmt.Errorf("%s is encrypted, set %s", s.path(), EnvCredentialsPassphrase)
+	}
+
+	var file encryptedFile
+	if err := json.Unmarshal(raw, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", s.path(), err)
+	}
+	gcm, err := newGCM(os.Getenv(EnvCredentialsPassphrase), file.Salt)
+	if err != nil {
+		return nil, err
+	}
+	data, err := gcm.Open(nil, file.Nonce, file.Data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt %s, wrong %s?", s.path(), EnvCredentialsPassphrase)
+	}
+	return data, nil
+}
+
+func (s encryptedFileStore) save(data []byte) error {
+	file := encryptedFile{Salt: make([]byte, 16)}
+	if _, err := rand.Read(file.Salt); err != nil {
+		return err
+	}
+	gcm, err := newGCM(os.Getenv(EnvCredentialsPassphrase), file.Salt)
+	if err != nil {
+		return err
+	}
+	file.Nonce = make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(file.Nonce); err != nil {
+		return err
+	}
+	file.Data = gcm.Seal(nil, file.Nonce, data, nil)
+
+	encoded, err := json.Marshal(file)
+	if err != nil {
+		return err
+	}
+	return writePrivateFile(s.path(), encoded)
+}
+
+func (s encryptedFileStore) remove() error {
+	return removeIfExists(s.path())
+}
+
+func newGCM(passphrase string, salt []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(pbkdf2.Key([]byte(passphrase), salt, pbkdf2Iterations, 32, sha256.New))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// plainFileStore is the fallback, a file only the user can read
+type plainFileStore struct{}
+
+func (plainFileStore) path() string {
+	return filepath.Join(config.Dir(), "credentials.json")
+}
+
+func (plainFileStore) name() string {
+	return "plain file"
+}
+
+func (plainFileStore) available() bool {
+	return true
+}
+
+func (s plainFileStore) load() ([]byte, error) {
+	data, err := os.ReadFile(s.path())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, errNotStored
+	}
+	return data, err
+}
+
+func (s plainFileStore) save(data []byte) error {
+	return writePrivateFile(s.path(), data)
+}
+
+func (s plainFileStore) remove() error {
+	return removeIfExists(s.path())
+}
+
+// writePrivateFile writes through a new temp file readable only by the user. CreateTemp opens it with
+// O_EXCL at 0600, so a leftover file and its permissions are never reused.
+func writePrivateFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+func removeIfExists(path string) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
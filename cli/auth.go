@@ -0,0 +1,214 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"simple-git-terminal/auth"
+	"simple-git-terminal/util"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"golang.org/x/term"
+)
+
+func runAuth(args []string) (int, error) {
+	if len(args) == 0 {
+		return ExitUsage, fmt.Errorf("%w: missing auth subcommand", errUsage)
+	}
+
+	switch args[0] {
+	case "login":
+		return authLogin(args[1:])
+	case "status":
+		return authStatus(args[1:])
+	case "logout":
+		return authLogout(args[1:])
+	}
+	return ExitUsage, fmt.Errorf("%w: unknown auth subcommand %q", errUsage, args[0])
+}
+
+func authLogin(args []string) (int, error) {
+	fs, _ := newFlagSet("auth login")
+	name := fs.String("account", auth.DefaultAccountName, "Name of the account")
+	withToken := fs.Bool("with-token", false, "Enter a token instead of the OAuth flow")
+	username := fs.String("username", "", "Username or email the token belongs to, for basic authentication")
+	host := fs.String("host", auth.CloudHost, "bitbucket.org, or the URL of a Data Center server")
+	clientID := fs.String("client-id", os.Getenv(auth.EnvOAuthClientID), "Key of the OAuth consumer")
+	clientSecret := fs.String("client-secret", os.Getenv(auth.EnvOAuthClientSecret), "Secret of the OAuth consumer")
+	port := fs.Int("port", auth.DefaultCallbackPort, "Port of the OAuth callback on 127.0.0.1")
+	if _, err := parseArgs(fs, args); err != nil {
+		return ExitUsage, err
+	}
+
+	var account *auth.Account
+	if *withToken || *host != auth.CloudHost {
+		// Data Center is always logged in with a personal access token
+		token, err := readToken()
+		if err != nil {
+			return ExitUsage, err
+		}
+		account = &auth.Account{Name: *name, Type: auth.TypeToken, Host: strings.TrimSuffix(*host, "/"), Username: *username, Token: token}
+	} else {
+		if *clientID == "" || *clientSecret == "" {
+			return ExitUsage, fmt.Errorf("%w: the OAuth flow needs --client-id and --client-secret (or %s and %s) of an OAuth consumer with the callback URL http://127.0.0.1:%d/callback, or use --with-token",
+				errUsage, auth.EnvOAuthClientID, auth.EnvOAuthClientSecret, *port)
+		}
+		var err error
+		account, err = auth.LoginOAuth(*name, *clientID, *clientSecret, *port, util.OpenBrowser, os.Stderr)
+		if err != nil {
+			return ExitError, err
+		}
+	}
+
+	user, err := auth.Verify(account)
+	if err != nil {
+		return ExitError, fmt.Errorf("the credentials were rejected by %s: %w", account.Host, err)
+	}
+
+	creds, _, err := auth.Load()
+	if err != nil {
+		return ExitError, err
+	}
+	creds.Accounts[account.Name] = account
+	creds.Default = account.Name
+	store, err := auth.Save(creds)
+	if err != nil {
+		return ExitError, err
+	}
+
+	fmt.Printf("Logged in to %s as %s, account %q stored in the %s\n", account.Host, user, account.Name, store)
+	return ExitOK, nil
+}
+
+// readToken prompts for a token without echoing it, or reads it from stdin when piped
+func readToken() (string, error) {
+	var token string
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		fmt.Fprint(os.Stderr, "Paste your token: ")
+		raw, err := term.ReadPassword(int(os.Stdin.Fd()))
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return "", fmt.Errorf("failed to read the token: %w", err)
+		}
+		token = string(raw)
+	} else {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && err != io.EOF {
+			return "", fmt.Errorf("failed to read the token: %w", err)
+		}
+		token = line
+	}
+
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return "", fmt.Errorf("%w: no token given", errUsage)
+	}
+	return token, nil
+}
+
+// accountStatus is the JSON and --format shape of `auth status`
+type accountStatus struct {
+	Name      string    `json:"name"`
+	Type      string    `json:"type"`
+	Host      string    `json:"host"`
+	User      string    `json:"user"`
+	Active    bool      `json:"active"`
+	ExpiresAt time.Time `json:"expires_at,omitempty"`
+	Error     string    `json:"error,omitempty"`
+}
+
+func authStatus(args []string) (int, error) {
+	fs, out := newFlagSet("auth status")
+	if _, err := parseArgs(fs, args); err != nil {
+		return ExitUsage, err
+	}
+
+	creds, store, err := auth.Load()
+	if err != nil {
+		return ExitError, err
+	}
+	if len(creds.Accounts) == 0 && !auth.HasEnvCredentials() {
+		return ExitFailed, fmt.Errorf("not logged in, run `bbpr auth login`")
+	}
+
+	code := ExitOK
+	active := auth.ActiveAccountName(creds)
+	var statuses []accountStatus
+	var items []interface{}
+	for _, name := range creds.AccountNames() {
+		account := creds.Accounts[name]
+		status := accountStatus{Name: name, Type: account.Type, Host: account.Host, Active: name == active && !auth.HasEnvCredentials()}
+		if account.Type == auth.TypeOAuth {
+			status.ExpiresAt = account.ExpiresAt
+		}
+		if user, err := auth.VerifyStored(account); err != nil {
+			status.Error = err.Error()
+			code = ExitFailed
+		} else {
+			status.User = user
+		}
+		statuses = append(statuses, status)
+		items = append(items, status)
+	}
+
+	return code, out.print(statuses, items, func(w io.Writer) {
+		if auth.HasEnvCredentials() {
+			fmt.Fprintf(w, "Using credentials from the environment (%s or %s)\n", auth.EnvToken, auth.EnvAppPassword)
+		}
+		if store != "" {
+			fmt.Fprintf(w, "Accounts stored in the %s\n", store)
+		}
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		for _, status := range statuses {
+			marker := " "
+			if status.Active {
+				marker = "*"
+			}
+			result := "logged in as " + status.User
+			if status.Error != "" {
+				result = "invalid: " + status.Error
+			}
+			fmt.Fprintf(tw, "%s %s\t%s\t%s\t%s\n", marker, status.Name, status.Host, status.Type, result)
+		}
+		tw.Flush()
+	})
+}
+
+func authLogout(args []string) (int, error) {
+	fs, _ := newFlagSet("auth logout")
+	name := fs.String("account", "", "Account to log out of, the active one by default")
+	if _, err := parseArgs(fs, args); err != nil {
+		return ExitUsage, err
+	}
+
+	creds, _, err := auth.Load()
+	if err != nil {
+		return ExitError, err
+	}
+	if *name == "" {
+		*name = auth.ActiveAccountName(creds)
+	}
+	if _, ok := creds.Accounts[*name]; !ok {
+		return ExitUsage, fmt.Errorf("%w: no account named %q", errUsage, *name)
+	}
+
+	delete(creds.Accounts, *name)
+	if creds.Default == *name {
+		creds.Default = ""
+		if names := creds.AccountNames(); len(names) > 0 {
+			creds.Default = names[0]
+		}
+	}
+	if _, err := auth.Save(creds); err != nil {
+		return ExitError, err
+	}
+
+	fmt.Printf("Logged out of %q\n", *name)
+	if creds.Default != "" {
+		fmt.Printf("Using %q from now on\n", creds.Default)
+	}
+	return ExitOK, nil
+}
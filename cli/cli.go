@@ -0,0 +1,155 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"simple-git-terminal/state"
+	"simple-git-terminal/util"
+	"text/template"
+)
+
+// Exit codes of the non-interactive commands
+const (
+	ExitOK     = 0 // Command succeeded, e.g. the watched pipeline passed
+	ExitFailed = 1 // Command ran but the result is a failure, e.g. the pipeline or step failed
+	ExitUsage  = 2 // Unknown command, bad arguments or not inside a Bitbucket repository
+	ExitError  = 3 // Bitbucket API or network error
+)
+
+// errUsage marks errors caused by how the command was called
+var errUsage = errors.New("invalid usage")
+
+const usage = `Usage: bbpr <command> [flags]
+
+Commands:
+  auth login                    Log in with OAuth, or a token (--with-token, --username, --host, --account)
+  auth status                   Show the stored accounts and check their credentials
+  auth logout                   Remove an account (--account)
+  pr list                       List pull requests (--state, --query, --limit)
+  pr view <id>                  Show a single pull request
+  pr diff <id>                  Print the diff of a pull request
+  pipeline list                 List recent pipelines (--limit)
+  pipeline logs <uuid> <step>   Print the log of a pipeline step, by step name or UUID
+  pipeline watch <uuid>         Wait for a pipeline to finish, exiting non-zero unless it passed
+
+Output flags (every command):
+  --json                        Print JSON instead of text
+  --format <template>           Go template applied to each item, e.g. '{{.ID}} {{.Title}}'
+
+Global flags (before the command):
+  -workspace <ws> -repo <slug>  Use this repository instead of detecting it from the git remotes
+  -account <name>               Use this stored account instead of the default
+  -theme <name>                 Use this color theme instead of the configured one
+
+Exit codes: 0 ok, 1 failed result, 2 usage error, 3 API error
+`
+
+// output holds the flags shared by every command
+type output struct {
+	json   bool
+	format string
+}
+
+// Run executes a subcommand and returns the process exit code
+func Run(args []string) int {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		fmt.Fprint(os.Stdout, usage)
+		return ExitOK
+	}
+
+	var err error
+	code := ExitOK
+	switch args[0] {
+	case "auth":
+		code, err = runAuth(args[1:])
+	case "pr":
+		code, err = runPR(args[1:])
+	case "pipeline":
+		code, err = runPipeline(args[1:])
+	default:
+		err = fmt.Errorf("%w: unknown command %q", errUsage, args[0])
+	}
+
+	if err != nil {
+		log.Printf("[CLI] %v", err)
+		fmt.Fprintf(os.Stderr, "bbpr: %v\n", err)
+		if errors.Is(err, errUsage) {
+			fmt.Fprint(os.Stderr, "\n"+usage)
+			return ExitUsage
+		}
+		return ExitError
+	}
+	return code
+}
+
+// newFlagSet creates the flag set of a subcommand with the shared output flags registered
+func newFlagSet(name string) (*flag.FlagSet, *output) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	out := &output{}
+	fs.BoolVar(&out.json, "json", false, "Print JSON")
+	fs.StringVar(&out.format, "format", "", "Go template applied to each item")
+	return fs, out
+}
+
+// parseArgs parses flags anywhere in args, so `pr view 12 --json` works like `pr view --json 12`.
+// Returns the positional arguments.
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, fmt.Errorf("%w: %v", errUsage, err)
+		}
+		if fs.NArg() == 0 {
+			return positional, nil
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+// setupRepo points the API layer at the Bitbucket repository of the current directory
+func setupRepo() error {
+	workspace, repo, err := util.GetRepoAndWorkspace()
+	if err != nil || workspace == "" || repo == "" {
+		return fmt.Errorf("%w: not inside a Bitbucket repository: %v", errUsage, err)
+	}
+	state.SetWorkspaceRepo(workspace, repo)
+	return nil
+}
+
+// print writes value as JSON, through the format template (once per item for slices) or with printText
+func (out *output) print(value interface{}, items []interface{}, printText func(w io.Writer)) error {
+	switch {
+	case out.json:
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(value)
+
+	case out.format != "":
+		tmpl, err := template.New("format").Parse(out.format)
+		if err != nil {
+			return fmt.Errorf("%w: invalid --format template: %v", errUsage, err)
+		}
+		if items == nil {
+			items = []interface{}{value}
+		}
+		for _, item := range items {
+			if err := tmpl.Execute(os.Stdout, item); err != nil {
+				return fmt.Errorf("%w: --format template failed: %v", errUsage, err)
+			}
+			fmt.Fprintln(os.Stdout)
+		}
+		return nil
+
+	default:
+		printText(os.Stdout)
+		return nil
+	}
+}
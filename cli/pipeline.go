@@ -0,0 +1,186 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"simple-git-terminal/apis/bitbucket"
+	"simple-git-terminal/apis/provider"
+	"simple-git-terminal/state"
+	"simple-git-terminal/types"
+	"simple-git-terminal/util"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+func runPipeline(args []string) (int, error) {
+	if len(args) == 0 {
+		return ExitUsage, fmt.Errorf("%w: missing pipeline subcommand", errUsage)
+	}
+	if err := setupRepo(); err != nil {
+		return ExitUsage, err
+	}
+	if !provider.IsCloud() {
+		return ExitError, fmt.Errorf("pipelines are only available on Bitbucket Cloud")
+	}
+
+	switch args[0] {
+	case "list":
+		return pipelineList(args[1:])
+	case "logs":
+		return pipelineLogs(args[1:])
+	case "watch":
+		return pipelineWatch(args[1:])
+	}
+	return ExitUsage, fmt.Errorf("%w: unknown pipeline subcommand %q", errUsage, args[0])
+}
+
+func pipelineList(args []string) (int, error) {
+	fs, out := newFlagSet("pipeline list")
+	limit := fs.Int("limit", 20, "Maximum number of pipelines")
+	if _, err := parseArgs(fs, args); err != nil {
+		return ExitUsage, err
+	}
+	if *limit < 1 {
+		return ExitUsage, fmt.Errorf("%w: --limit must be at least 1", errUsage)
+	}
+
+	pipelines, err := bitbucket.FetchRecentPipelines(state.CurrentRepo(), *limit)
+	if err != nil {
+		return ExitError, err
+	}
+
+	items := make([]interface{}, len(pipelines))
+	for i := range pipelines {
+		items[i] = pipelines[i]
+	}
+	return ExitOK, out.print(pipelines, items, func(w io.Writer) {
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		for _, pipeline := range pipelines {
+			fmt.Fprintf(tw, "#%d\t%s\t%s\t%s\t%s\t%s\n", pipeline.BuildNumber, pipelineResult(pipeline.State),
+				pipeline.Target.RefName, pipeline.Creator.DisplayName, util.FormatTimeAgo(pipeline.CreatedOn), pipeline.UUID)
+		}
+		tw.Flush()
+	})
+}
+
+// stepLog is the JSON and --format shape of `pipeline logs`
+type stepLog struct {
+	Step   string `json:"step"`
+	UUID   string `json:"uuid"`
+	Result string `json:"result"`
+	Log    string `json:"log"`
+}
+
+func pipelineLogs(args []string) (int, error) {
+	fs, out := newFlagSet("pipeline logs")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return ExitUsage, err
+	}
+	if len(positional) != 2 {
+		return ExitUsage, fmt.Errorf("%w: expected a pipeline UUID and a step name or UUID", errUsage)
+	}
+	pipelineUUID := normalizeUUID(positional[0])
+
+	steps, err := bitbucket.FetchPipelineSteps(pipelineUUID)
+	if err != nil {
+		return ExitError, err
+	}
+	step, found := findStep(steps, positional[1])
+	if !found {
+		return ExitUsage, fmt.Errorf("%w: no step %q in pipeline %s", errUsage, positional[1], pipelineUUID)
+	}
+
+	logText, err := bitbucket.FetchPipelineStepLog(pipelineUUID, step.UUID)
+	if err != nil {
+		return ExitError, err
+	}
+
+	result := pipelineResult(step.State)
+	err = out.print(stepLog{Step: step.Name, UUID: step.UUID, Result: string(result), Log: logText}, nil, func(w io.Writer) {
+		fmt.Fprint(w, logText)
+	})
+	if err != nil {
+		return ExitUsage, err
+	}
+	if step.State.Name.NeedsTracking() {
+		return ExitOK, nil
+	}
+	return exitCodeFor(step.State), nil
+}
+
+// findStep matches a step by UUID (with or without braces) or by name, ignoring case
+func findStep(steps []types.StepDetail, key string) (types.StepDetail, bool) {
+	for _, step := range steps {
+		if step.UUID == normalizeUUID(key) || strings.EqualFold(step.Name, key) {
+			return step, true
+		}
+	}
+	return types.StepDetail{}, false
+}
+
+func pipelineWatch(args []string) (int, error) {
+	fs, out := newFlagSet("pipeline watch")
+	interval := fs.Duration("interval", 5*time.Second, "Polling interval")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return ExitUsage, err
+	}
+	if len(positional) != 1 {
+		return ExitUsage, fmt.Errorf("%w: expected a pipeline UUID", errUsage)
+	}
+	pipelineUUID := normalizeUUID(positional[0])
+	quiet := out.json || out.format != ""
+
+	var lastState types.PipelineStatus
+	for {
+		pipeline, err := bitbucket.FetchPipeline(pipelineUUID)
+		if err != nil {
+			return ExitError, err
+		}
+		if pipeline.UUID == "" {
+			return ExitError, fmt.Errorf("could not fetch pipeline %s", pipelineUUID)
+		}
+
+		current := pipelineResult(pipeline.State)
+		if current != lastState && !quiet {
+			fmt.Fprintf(os.Stdout, "%s #%d %s\n", time.Now().Format("15:04:05"), pipeline.BuildNumber, current)
+		}
+		lastState = current
+
+		if !pipeline.State.Name.NeedsTracking() {
+			err := out.print(pipeline, nil, func(w io.Writer) {})
+			if err != nil {
+				return ExitUsage, err
+			}
+			return exitCodeFor(pipeline.State), nil
+		}
+		time.Sleep(max(*interval, time.Second))
+	}
+}
+
+// pipelineResult is the final result of a completed pipeline or step, otherwise its current state
+func pipelineResult(state types.State) types.PipelineStatus {
+	if state.Result.Name != "" {
+		return state.Result.Name
+	}
+	return state.Name
+}
+
+func exitCodeFor(state types.State) int {
+	result := pipelineResult(state)
+	if result.Successful() || result.Passed() {
+		return ExitOK
+	}
+	return ExitFailed
+}
+
+// normalizeUUID adds the braces Bitbucket UUIDs are addressed with, so they can be passed without shell quoting
+func normalizeUUID(uuid string) string {
+	if strings.HasPrefix(uuid, "{") {
+		return uuid
+	}
+	return "{" + uuid + "}"
+}
@@ -0,0 +1,193 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"simple-git-terminal/apis/bitbucket"
+	"simple-git-terminal/apis/provider"
+	"simple-git-terminal/state"
+	"simple-git-terminal/types"
+	"simple-git-terminal/util"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+func runPR(args []string) (int, error) {
+	if len(args) == 0 {
+		return ExitUsage, fmt.Errorf("%w: missing pr subcommand", errUsage)
+	}
+	if err := setupRepo(); err != nil {
+		return ExitUsage, err
+	}
+
+	switch args[0] {
+	case "list":
+		return prList(args[1:])
+	case "view":
+		return prView(args[1:])
+	case "diff":
+		return prDiff(args[1:])
+	}
+	return ExitUsage, fmt.Errorf("%w: unknown pr subcommand %q", errUsage, args[0])
+}
+
+func prList(args []string) (int, error) {
+	fs, out := newFlagSet("pr list")
+	prState := fs.String("state", "OPEN", "OPEN, MERGED, DECLINED or SUPERSEDED")
+	query := fs.String("query", "", "Additional BBQL query")
+	limit := fs.Int("limit", 30, "Maximum number of PRs")
+	if _, err := parseArgs(fs, args); err != nil {
+		return ExitUsage, err
+	}
+	if *limit < 1 {
+		return ExitUsage, fmt.Errorf("%w: --limit must be at least 1", errUsage)
+	}
+
+	if !provider.IsCloud() {
+		if *query != "" {
+			return ExitUsage, fmt.Errorf("%w: --query needs Bitbucket Cloud", errUsage)
+		}
+		prs, err := fetchPRsWithProvider(*prState, *limit)
+		if err != nil {
+			return ExitError, err
+		}
+		return ExitOK, printPRs(out, prs)
+	}
+
+	var filters []string
+	if *prState != "" {
+		filters = append(filters, fmt.Sprintf("state=\"%s\"", strings.ToUpper(*prState)))
+	}
+	if *query != "" {
+		filters = append(filters, "("+*query+")")
+	}
+
+	prs, err := bitbucket.SearchPRs(state.CurrentRepo(), strings.Join(filters, " AND "), *limit)
+	if err != nil {
+		return ExitError, err
+	}
+	return ExitOK, printPRs(out, prs)
+}
+
+// fetchPRsWithProvider lists PRs through the provider, which has no BBQL, a page at a time until limit
+// PRs or the last page are reached
+func fetchPRsWithProvider(prState string, limit int) ([]types.PR, error) {
+	filter := bitbucket.PRFilter{}
+	if prState != "" {
+		filter.States = []string{strings.ToUpper(prState)}
+	}
+
+	var prs []types.PR
+	for page := 1; len(prs) < limit; page++ {
+		state.Pagination.Page = page
+		found, pagination, err := provider.Current().FetchPRs(filter, nil)
+		if err != nil {
+			return nil, err
+		}
+		prs = append(prs, found...)
+		if pagination.Size <= page*pagination.PageLen {
+			break
+		}
+	}
+	return prs[:min(limit, len(prs))], nil
+}
+
+func printPRs(out *output, prs []types.PR) error {
+	items := make([]interface{}, len(prs))
+	for i := range prs {
+		items[i] = prs[i]
+	}
+	return out.print(prs, items, func(w io.Writer) {
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		for _, pr := range prs {
+			fmt.Fprintf(tw, "#%d\t%s\t%s\t%s -> %s\t%s\n", pr.ID, pr.State, pr.Title, pr.Source.Branch.Name, pr.Destination.Branch.Name, pr.Author.DisplayName)
+		}
+		tw.Flush()
+	})
+}
+
+func prView(args []string) (int, error) {
+	fs, out := newFlagSet("pr view")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return ExitUsage, err
+	}
+	id, err := parsePRID(positional)
+	if err != nil {
+		return ExitUsage, err
+	}
+
+	pr, err := provider.Current().FetchPR(id)
+	if err != nil {
+		return ExitError, err
+	}
+
+	return ExitOK, out.print(pr, nil, func(w io.Writer) {
+		fmt.Fprintf(w, "#%d %s\n", pr.ID, pr.Title)
+		fmt.Fprintf(w, "State:    %s\n", pr.State)
+		fmt.Fprintf(w, "Author:   %s\n", pr.Author.DisplayName)
+		fmt.Fprintf(w, "Branches: %s -> %s\n", pr.Source.Branch.Name, pr.Destination.Branch.Name)
+		fmt.Fprintf(w, "Updated:  %s\n", util.FormatTimeAgo(pr.UpdatedOn))
+		for _, reviewer := range pr.Participants {
+			if reviewer.Role != "REVIEWER" || reviewer.User == nil {
+				continue
+			}
+			status := "pending"
+			if reviewer.Approved {
+				status = "approved"
+			} else if reviewer.State != "" {
+				status = string(reviewer.State)
+			}
+			fmt.Fprintf(w, "Reviewer: %s (%s)\n", reviewer.User.DisplayName, status)
+		}
+		if description, ok := pr.Description.(string); ok && description != "" {
+			fmt.Fprintf(w, "\n%s\n", description)
+		}
+		fmt.Fprintf(w, "\n%s\n", pr.Links.HTML.Href)
+	})
+}
+
+// fileDiff is the JSON and --format shape of a single file of `pr diff`
+type fileDiff struct {
+	Path string `json:"path"`
+	Diff string `json:"diff"`
+}
+
+func prDiff(args []string) (int, error) {
+	fs, out := newFlagSet("pr diff")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return ExitUsage, err
+	}
+	id, err := parsePRID(positional)
+	if err != nil {
+		return ExitUsage, err
+	}
+
+	diff, err := provider.Current().FetchDiff(id)
+	if err != nil {
+		return ExitError, err
+	}
+
+	var files []fileDiff
+	var items []interface{}
+	for _, file := range util.SplitDiffFiles(diff) {
+		files = append(files, fileDiff{Path: file.Path, Diff: file.Text})
+		items = append(items, files[len(files)-1])
+	}
+	return ExitOK, out.print(files, items, func(w io.Writer) {
+		fmt.Fprint(w, diff)
+	})
+}
+
+func parsePRID(positional []string) (int, error) {
+	if len(positional) != 1 {
+		return 0, fmt.Errorf("%w: expected a single PR id", errUsage)
+	}
+	id, err := strconv.Atoi(strings.TrimPrefix(positional[0], "#"))
+	if err != nil {
+		return 0, fmt.Errorf("%w: invalid PR id %q", errUsage, positional[0])
+	}
+	return id, nil
+}
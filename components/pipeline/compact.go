@@ -0,0 +1,38 @@
+package pipeline
+
+import (
+	"fmt"
+	"simple-git-terminal/components/shared"
+	"simple-git-terminal/state"
+
+	"github.com/rivo/tview"
+)
+
+var responsive *shared.ResponsiveLayout
+
+// NewResponsiveLayout wraps the pipeline layout so small terminals show one pane at a time,
+// navigated as pipelines → pipeline → step. The number keys switch panes.
+func NewResponsiveLayout(app *tview.Application, full, list, debugInfo, steps, commands, step, log tview.Primitive) *tview.Flex {
+	responsive = shared.NewResponsiveLayout(app, full, []shared.CompactPane{
+		{Name: "Pipelines", Key: "1", Level: 0, View: list, Crumb: func() string { return "Pipelines" }},
+		{Name: "Info", Key: "2", Level: 1, View: debugInfo, Crumb: selectedPipelineCrumb},
+		{Name: "Steps", Key: "3", Level: 1, View: steps, Crumb: selectedPipelineCrumb},
+		{Name: "Commands", Key: "4", Level: 1, View: commands, Crumb: selectedPipelineCrumb},
+		{Name: "Step", Key: "5", Level: 2, View: step, Crumb: func() string { return "Step" }},
+		{Name: "Log", Key: "6", Level: 2, View: log, Crumb: func() string { return "Step" }},
+	})
+	return responsive.Flex
+}
+
+func selectedPipelineCrumb() string {
+	pipeline := state.PipelineUIState.SelectedPipeline
+	if pipeline == nil {
+		return ""
+	}
+	return fmt.Sprintf("#%d", pipeline.BuildNumber)
+}
+
+// IsCompact reports whether the pipeline mode shows one pane at a time
+func IsCompact() bool {
+	return responsive != nil && responsive.IsCompact()
+}
@@ -4,11 +4,11 @@ import (
 	"fmt"
 	"simple-git-terminal/constants"
 	"simple-git-terminal/support"
+	"simple-git-terminal/theme"
 	"simple-git-terminal/types"
 	"simple-git-terminal/util"
 	"strings"
 
-	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 )
 
@@ -41,7 +41,7 @@ func GeneratePPDebugInfo(pipeline types.PipelineResponse) *tview.TextView {
 	sb.WriteString(fmt.Sprintf("[::b]Commit     :[-] %s\n", pipeline.Target.Commit.Hash))
 
 	textView.
-		SetText(sb.String()).SetTextColor(tcell.ColorDarkGray)
+		SetText(sb.String()).SetTextColor(theme.Current.Faint)
 
 	return textView
 }
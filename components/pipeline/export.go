@@ -0,0 +1,56 @@
+package pipeline
+
+import (
+	"fmt"
+	"regexp"
+	"simple-git-terminal/state"
+	"simple-git-terminal/support"
+	"simple-git-terminal/util"
+	"strings"
+)
+
+var fileNameUnsafe = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// ShowStepLogExports offers the log of the shown command, the failing command of a failed step and the full
+// step log to copy or save
+func ShowStepLogExports() {
+	logState := currentStepLog
+	if logState == nil {
+		showOpenMessage("Open a step command to yank its log")
+		return
+	}
+
+	name := fmt.Sprintf("pipeline-%d-%s", logState.pipeline.BuildNumber, fileNameUnsafe.ReplaceAllString(logState.step.Name, "-"))
+	exports := []support.Export{{
+		Label:    "Command log",
+		FileName: name + "-" + fileNameUnsafe.ReplaceAllString(util.EllipsizeText(logState.command, 30), "-") + ".log",
+		Text: func() (string, error) {
+			return logState.commandLog, nil
+		},
+	}}
+
+	result := logState.step.State.Result.Name
+	if result.Failed() || result.Error() {
+		var commands []string
+		for _, command := range logState.step.ScriptCommands {
+			commands = append(commands, command.Name)
+		}
+		exports = append(exports, support.Export{
+			Label:    "Failing command log",
+			FileName: name + "-failure.log",
+			Text: func() (string, error) {
+				return util.FailingCommandLog(logState.fullLog, commands)
+			},
+		})
+	}
+
+	exports = append(exports, support.Export{
+		Label:    "Full step log",
+		FileName: name + ".log",
+		Text: func() (string, error) {
+			return strings.TrimRight(logState.fullLog, "\n"), nil
+		},
+	})
+
+	support.ShowExportMenu(state.PipelineUIState.App, state.PipelineUIState.MainFlexWrapper, exports)
+}
@@ -3,27 +3,34 @@ package pipeline
 import (
 	"simple-git-terminal/state"
 	"simple-git-terminal/support"
+	"simple-git-terminal/theme"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 )
 
-const (
-	VIEW_ACTIVE_BORDER_COLOR = tcell.ColorOrange
-)
-
 func SetupKeyBindings() {
 	focusOrder := []tview.Primitive{
 		state.PipelineUIState.PipelineList, state.PipelineUIState.PipelineSteps, state.PipelineUIState.PipelineStepCommandsView,
 	}
 	// Define focus order
 	currentFocusIndex := 0
-	support.UpdateFocusBorders(focusOrder, currentFocusIndex, VIEW_ACTIVE_BORDER_COLOR)
+	support.UpdateFocusBorders(focusOrder, currentFocusIndex, theme.Current.Accent)
 
 	state.PipelineUIState.App.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		// Modals handle their own keys
+		if state.IsModalOpen {
+			return event
+		}
+
 		// Handle keybindings when not in search mode
 		switch event.Key() {
 		case tcell.KeyTAB:
+			if IsCompact() {
+				// Every pane takes a turn in compact mode
+				responsive.Cycle(1)
+				return nil
+			}
 			// Cycle focus between views
 			currentFocusIndex = (currentFocusIndex + 1) % len(focusOrder)
 			if currentFocusIndex >= len(focusOrder) {
@@ -36,14 +43,36 @@ func SetupKeyBindings() {
 				state.PipelineUIState.PipelineStepCommandsView: state.PipelineUIState.PipelineScriptCommandsTable,
 			})
 
+		case tcell.KeyBackspace, tcell.KeyBackspace2:
+			if IsCompact() {
+				responsive.Back()
+			}
+
 		case tcell.KeyRune:
 			switch event.Rune() {
 			case 'r':
 				PopulatePipelineList()
+			case 'W':
+				OpenFocusedInBrowser()
+				return nil
+			case 'Y':
+				CopyFocusedURL()
+				return nil
+			case 'y':
+				ShowStepLogExports()
+				return nil
+			case '!':
+				ShowNotifications()
+				return nil
+			case '1', '2', '3', '4', '5', '6':
+				if IsCompact() {
+					responsive.Show(int(event.Rune() - '1'))
+					return nil
+				}
 			}
 		}
 		// Update focus borders after focus change
-		support.UpdateFocusBorders(focusOrder, currentFocusIndex, VIEW_ACTIVE_BORDER_COLOR)
+		support.UpdateFocusBorders(focusOrder, currentFocusIndex, theme.Current.Accent)
 
 		return event
 	})
@@ -44,10 +44,10 @@ func PopulatePipelineList() {
 		isLoading = true
 
 		support.ShowPipelineLoadingSpinner(state.PipelineUIState.PipelineList, func() (interface{}, error) {
-			pps, pagination := bitbucket.FetchPipelinesByQuery(query)
-			if pps == nil {
-				log.Println("Failed to fetch pipelines, nil returned")
-				return nil, fmt.Errorf("failed to fetch pipelines")
+			pps, pagination, err := bitbucket.FetchPipelinesByQuery(query)
+			if err != nil {
+				log.Printf("[ERROR] %v", err)
+				return nil, err
 			}
 
 			// If page size is less than 10, we assume it's the last page
@@ -64,7 +64,7 @@ func PopulatePipelineList() {
 
 			pps, ok := result.([]types.PipelineResponse)
 			if !ok {
-				support.UpdateView(state.PipelineUIState.PipelineList, fmt.Sprintf("[red]Error: %v[-]", err))
+				support.UpdateView(state.PipelineUIState.PipelineList, fmt.Sprintf("[danger]Error: %v[-]", err))
 				return
 			}
 
@@ -131,7 +131,11 @@ func PopulatePipelineList() {
 					}
 
 					// Fetch updated pipeline status
-					updated := bitbucket.FetchPipeline(pp.UUID)
+					updated, err := bitbucket.FetchPipeline(pp.UUID)
+					if err != nil {
+						log.Printf("[WARN] %v", err)
+						continue
+					}
 					pipelineCache[pp.UUID] = pipelineCacheEntry{
 						lastFetched: time.Now(),
 						data:        *updated,
@@ -191,16 +195,16 @@ func HandleOnPipelineSelect(pipelines []types.PipelineResponse, row int, frame i
 	support.ShowPipelineLoadingSpinner(state.PipelineUIState.PipelineSteps, func() (interface{}, error) {
 		EmptyAllPipelineListDependentViews()
 
-		steps := bitbucket.FetchPipelineSteps(selectedPipeline.UUID)
-		if steps == nil {
-			log.Println("Failed to fetch pipeline steps, nil returned")
-			return nil, fmt.Errorf("failed to fetch pipeline steps")
+		steps, err := bitbucket.FetchPipelineSteps(selectedPipeline.UUID)
+		if err != nil {
+			log.Printf("[ERROR] %v", err)
+			return nil, err
 		}
 		return steps, nil
 	}, func(result interface{}, err error) {
 		steps, ok := result.([]types.StepDetail)
 		if !ok {
-			support.UpdateView(state.PipelineUIState.PipelineSteps, fmt.Sprintf("[red]Error: %v[-]", err))
+			support.UpdateView(state.PipelineUIState.PipelineSteps, fmt.Sprintf("[danger]Error: %v[-]", err))
 			return
 		}
 
@@ -254,15 +258,15 @@ func TrackPipelineLive(ctx context.Context, pipeline types.PipelineResponse) {
 			state.PipelineUIState.PipelineSteps.PatchSteps(steps, frame)
 
 		case <-fetchTicker.C:
-			updatedPipeline := bitbucket.FetchPipeline(pipeline.UUID)
-			if updatedPipeline.UUID == "" {
-				log.Printf("[WARN] Could not fetch pipeline update for %s", pipeline.UUID)
+			updatedPipeline, err := bitbucket.FetchPipeline(pipeline.UUID)
+			if err != nil || updatedPipeline.UUID == "" {
+				log.Printf("[WARN] Could not fetch pipeline update for %s: %v", pipeline.UUID, err)
 				continue
 			}
 
-			newSteps := bitbucket.FetchPipelineSteps(updatedPipeline.UUID)
-			if newSteps == nil {
-				log.Printf("[WARN] Could not fetch updated steps for pipeline %s", updatedPipeline.UUID)
+			newSteps, err := bitbucket.FetchPipelineSteps(updatedPipeline.UUID)
+			if err != nil {
+				log.Printf("[WARN] Could not fetch updated steps for pipeline %s: %v", updatedPipeline.UUID, err)
 				continue
 			}
 
@@ -0,0 +1,61 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"simple-git-terminal/apis/provider"
+	"simple-git-terminal/components/pr"
+	"simple-git-terminal/notifications"
+	"simple-git-terminal/state"
+	"simple-git-terminal/support"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+const (
+	PIPELINE_LIST_TITLE = "Pipelines p|P"
+	NOTIFICATIONS_TITLE = "Notifications [key]![-] [muted]esc close[-]"
+)
+
+// StartNotifications runs the notification poller for the lifetime of the app and keeps the badge on the
+// pipeline list up to date
+func StartNotifications() {
+	if !provider.IsCloud() {
+		log.Printf("[NOTIFY] Notifications are only available on Bitbucket Cloud")
+		return
+	}
+	notifications.Start(context.Background(), state.CurrentRepo(), func() {
+		state.PipelineUIState.App.QueueUpdateDraw(updateNotificationBadge)
+	})
+}
+
+// updateNotificationBadge shows the unread count in the pipeline list title
+func updateNotificationBadge() {
+	title := PIPELINE_LIST_TITLE
+	if count := notifications.UnreadCount(); count > 0 {
+		title += fmt.Sprintf("  [warning]%s%d[-]", pr.ICON_BELL, count)
+	}
+	state.PipelineUIState.PipelineList.SetTitle(title)
+}
+
+// ShowNotifications lists the received events in an overlay and marks them as read. PRs can only be
+// opened from PR mode, so the panel is read-only here.
+func ShowNotifications() {
+	events := notifications.Events()
+	notifications.MarkAllRead()
+	updateNotificationBadge()
+
+	app := state.PipelineUIState.App
+	background := state.PipelineUIState.MainFlexWrapper
+	table := pr.NewNotificationsTable(events, NOTIFICATIONS_TITLE)
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEsc {
+			support.CloseModal(app, background)
+			return nil
+		}
+		return event
+	})
+
+	support.ShowOverlay(app, background, table, 110, 20)
+}
@@ -0,0 +1,62 @@
+package pipeline
+
+import (
+	"fmt"
+	"log"
+	"simple-git-terminal/apis/bitbucket"
+	"simple-git-terminal/state"
+	"simple-git-terminal/support"
+	"simple-git-terminal/util"
+)
+
+// focusedURL is the web address of the selected step while a step pane has the focus, or else of the
+// selected pipeline
+func focusedURL() string {
+	pipeline := state.PipelineUIState.SelectedPipeline
+	if pipeline == nil {
+		return ""
+	}
+
+	stepPanes := []interface{ HasFocus() bool }{
+		state.PipelineUIState.PipelineSteps, state.PipelineUIState.PipelineStepCommandsView,
+		state.PipelineUIState.PipelineStep, state.PipelineUIState.PipelineStepCommandLogView,
+	}
+	for _, pane := range stepPanes {
+		if !pane.HasFocus() {
+			continue
+		}
+		if step := state.PipelineUIState.PipelineSteps.GetSelectedStep(); step != nil {
+			return bitbucket.PipelineWebURL(pipeline.BuildNumber, step.UUID)
+		}
+	}
+	return bitbucket.PipelineWebURL(pipeline.BuildNumber, "")
+}
+
+// OpenFocusedInBrowser opens the selected pipeline or step in the browser
+func OpenFocusedInBrowser() {
+	link := focusedURL()
+	if link == "" {
+		return
+	}
+	log.Printf("[OPEN] Opening %s", link)
+	if err := util.OpenBrowser(link); err != nil {
+		showOpenMessage(fmt.Sprintf("[danger]Could not open the browser[-]\n%v", err))
+	}
+}
+
+// CopyFocusedURL copies the address OpenFocusedInBrowser would open
+func CopyFocusedURL() {
+	link := focusedURL()
+	if link == "" {
+		return
+	}
+	if err := util.CopyToClipboard(link); err != nil {
+		showOpenMessage(fmt.Sprintf("[danger]Could not copy the link[-]\n%v", err))
+		return
+	}
+	showOpenMessage(fmt.Sprintf("Copied to the clipboard\n[link]%s[-]", link))
+}
+
+func showOpenMessage(text string) {
+	support.ShowModal(state.PipelineUIState.App, state.PipelineUIState.MainFlexWrapper, text, []string{"OK"}, nil)
+}
@@ -7,6 +7,7 @@ import (
 	"simple-git-terminal/constants"
 	"simple-git-terminal/state"
 	"simple-git-terminal/support"
+	"simple-git-terminal/theme"
 	"simple-git-terminal/types"
 	"simple-git-terminal/util"
 	"strings"
@@ -40,7 +41,7 @@ func GenerateStepView(step types.StepDetail, selectedPipeline types.PipelineResp
 		}
 	}
 
-	textView.SetText(sb.String()).SetTextColor(tcell.ColorDarkGray)
+	textView.SetText(sb.String()).SetTextColor(theme.Current.Faint)
 
 	// ─── TABLE VIEW: Script Commands ─────────────────────────────────────
 	scriptTable := tview.NewTable()
@@ -52,11 +53,11 @@ func GenerateStepView(step types.StepDetail, selectedPipeline types.PipelineResp
 
 	for i, cmd := range step.ScriptCommands {
 		cmdText := fmt.Sprintf("%s [::b]%s", constants.ICON_SIDE_ARROW, cmd.Name)
-		scriptTable.SetCell(i, 0, util.CellFormat(cmdText, tcell.ColorWhite))
+		scriptTable.SetCell(i, 0, util.CellFormat(cmdText, theme.Current.Text))
 	}
 
 	if len(step.ScriptCommands) == 0 {
-		scriptTable.SetCell(0, 0, util.CellFormat(" No script commands available", tcell.ColorGray))
+		scriptTable.SetCell(0, 0, util.CellFormat(" No script commands available", theme.Current.Muted))
 	}
 
 	state.PipelineUIState.PipelineScriptCommandsTable = scriptTable
@@ -72,7 +73,7 @@ func GenerateStepView(step types.StepDetail, selectedPipeline types.PipelineResp
 			HandleOnScriptCommandSelected(step.ScriptCommands, step, selectedPipeline, row)
 		}()
 	})
-	scriptTable.SetSelectedStyle(tcell.StyleDefault.Foreground(tcell.ColorDarkOrange))
+	scriptTable.SetSelectedStyle(tcell.StyleDefault.Foreground(theme.Current.Selected))
 
 	// script table goes into its own view
 	support.UpdateView(state.PipelineUIState.PipelineStepCommandsView, scriptTable)
@@ -96,8 +97,10 @@ func HandleOnScriptCommandSelected(commands []types.CommandDetail, selectedStep
 
 	selectedCommand := commands[row]
 
+	var fullLog string
 	support.ShowPipelineLoadingSpinner(state.PipelineUIState.PipelineStepCommandLogView, func() (interface{}, error) {
-		fullLog, err := bitbucket.FetchPipelineStepLog(selectedPipeline.UUID, selectedStep.UUID)
+		var err error
+		fullLog, err = bitbucket.FetchPipelineStepLog(selectedPipeline.UUID, selectedStep.UUID)
 		if err != nil {
 			return nil, fmt.Errorf("failed to fetch single step command %s", selectedCommand.Name)
 		}
@@ -111,10 +114,17 @@ func HandleOnScriptCommandSelected(commands []types.CommandDetail, selectedStep
 	}, func(result interface{}, err error) {
 		commandLog, ok := result.(string)
 		if !ok {
-			support.UpdateView(state.PipelineUIState.PipelineStepCommandLogView, fmt.Sprintf("[red]Error: %v[-]", err))
+			support.UpdateView(state.PipelineUIState.PipelineStepCommandLogView, fmt.Sprintf("[danger]Error: %v[-]", err))
 			return
 		}
 
+		currentStepLog = &stepLogState{
+			pipeline:   selectedPipeline,
+			step:       selectedStep,
+			command:    selectedCommand.Name,
+			commandLog: commandLog,
+			fullLog:    fullLog,
+		}
 		support.UpdateView(state.PipelineUIState.PipelineStepCommandLogView, GenerateStepCommandLogView(commandLog, selectedCommand.Name))
 	})
 }
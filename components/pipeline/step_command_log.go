@@ -3,10 +3,22 @@ package pipeline
 import (
 	"fmt"
 	"simple-git-terminal/support"
+	"simple-git-terminal/types"
 
 	"github.com/rivo/tview"
 )
 
+// stepLogState is the log shown in the log pane, kept for yanking
+type stepLogState struct {
+	pipeline   types.PipelineResponse
+	step       types.StepDetail
+	command    string
+	commandLog string
+	fullLog    string
+}
+
+var currentStepLog *stepLogState
+
 // GenerateStepCommandLogView renders the raw logs of a selected command step.
 func GenerateStepCommandLogView(logText string, commandName string) tview.Primitive {
 	logView := support.CreateTextviewComponent(fmt.Sprintf("   Logs: %s", commandName), false)
@@ -14,7 +26,7 @@ func GenerateStepCommandLogView(logText string, commandName string) tview.Primit
 		SetScrollable(true)
 
 	if logText == "" {
-		logView.SetText("[gray]No logs available for this command[-]")
+		logView.SetText("[muted]No logs available for this command[-]")
 	} else {
 		logView.SetText(logText)
 	}
@@ -6,6 +6,7 @@ import (
 	"simple-git-terminal/apis/bitbucket"
 	"simple-git-terminal/state"
 	"simple-git-terminal/support"
+	"simple-git-terminal/theme"
 	"simple-git-terminal/types"
 	"simple-git-terminal/util"
 
@@ -48,7 +49,7 @@ func GenerateStepsView(steps []types.StepDetail, selectedPipeline types.Pipeline
 			HandleOnStepSelect(steps, selectedPipeline, row)
 		}()
 	})
-	stepsTable.SetSelectedStyle(tcell.StyleDefault.Foreground(tcell.ColorDarkOrange))
+	stepsTable.SetSelectedStyle(tcell.StyleDefault.Foreground(theme.Current.Selected))
 
 	state.PipelineUIState.PipelineStepsTable = stepsTable
 	return layout
@@ -85,7 +86,7 @@ func HandleOnStepSelect(steps []types.StepDetail, selectedPipeline types.Pipelin
 	}, func(result interface{}, err error) {
 		step, ok := result.(types.StepDetail)
 		if !ok {
-			support.UpdateView(state.PipelineUIState.PipelineStep, fmt.Sprintf("[red]Error: %v[-]", err))
+			support.UpdateView(state.PipelineUIState.PipelineStep, fmt.Sprintf("[danger]Error: %v[-]", err))
 			return
 		}
 
@@ -48,9 +48,9 @@ func GenerateActivityLogs(activities []types.Activity) string {
 	var logs []string
 
 	// Separate logs into sections
-	updateLogs := []string{ICON_UPDATES + "[red]Updates\n"}
-	approvalLogs := []string{ICON_APPROVAL + "[red]Approvals\n"}
-	commentLogs := []string{ICON_COMMENT + "[red]Comments\n"}
+	updateLogs := []string{ICON_UPDATES + "[danger]Updates\n"}
+	approvalLogs := []string{ICON_APPROVAL + "[danger]Approvals\n"}
+	commentLogs := []string{ICON_COMMENT + "[danger]Comments\n"}
 
 	itemsCount := 0
 	var previousCommitHash string // Track the previous commit hash
@@ -79,7 +79,7 @@ func GenerateActivityLogs(activities []types.Activity) string {
 				openPRFound = true
 				previousCommitHash = activity.Update.Source.Commit.Hash
 				log := fmt.Sprintf(
-					"[mediumaquamarine] [-]%s [mediumaquamarine]opened[-] the pull request: %s [grey](%s)[-]\n",
+					"[open] [-]%s [open]opened[-] the pull request: %s [muted](%s)[-]\n",
 					activity.Update.Author.DisplayName,
 					activity.Update.Title,
 					util.FormatTimeAgo(activity.Update.Date),
@@ -92,7 +92,7 @@ func GenerateActivityLogs(activities []types.Activity) string {
 				itemsCount++
 				previousCommitHash = activity.Update.Source.Commit.Hash
 				log := fmt.Sprintf(
-					"[orange]\ue729 [-]%s [orange]updated[-] the pull request with a new commit: [steelblue]%s[-] [grey](%s)[-]\n",
+					"[accent]\ue729 [-]%s [accent]updated[-] the pull request with a new commit: [info]%s[-] [muted](%s)[-]\n",
 					activity.Update.Author.DisplayName,
 					activity.Update.Source.Commit.Hash,
 					util.FormatTimeAgo(activity.Update.Date),
@@ -105,7 +105,7 @@ func GenerateActivityLogs(activities []types.Activity) string {
 				itemsCount++
 				for _, reviewer := range activity.Update.Changes.Reviewers.Added {
 					log := fmt.Sprintf(
-						"[purple]+ [-]%s added [purple]reviewer[-]: %s [grey](%s)[-]\n",
+						"[reviewer]+ [-]%s added [reviewer]reviewer[-]: %s [muted](%s)[-]\n",
 						activity.Update.Author.DisplayName,
 						reviewer.DisplayName,
 						util.FormatTimeAgo(activity.Update.Date),
@@ -117,7 +117,7 @@ func GenerateActivityLogs(activities []types.Activity) string {
 			if activity.Update.Changes.Title.Old != "" && activity.Update.Changes.Title.New != "" {
 				itemsCount++
 				log := fmt.Sprintf(
-					"[blue]\uea73 [-]%s edited the [blue]title[-]: %s → %s [grey](%s)[-]\n",
+					"[link]\uea73 [-]%s edited the [link]title[-]: %s → %s [muted](%s)[-]\n",
 					activity.Update.Author.DisplayName,
 					activity.Update.Changes.Title.Old,
 					activity.Update.Changes.Title.New,
@@ -129,7 +129,7 @@ func GenerateActivityLogs(activities []types.Activity) string {
 			if activity.Update.Changes.Description.Old != "" && activity.Update.Changes.Description.New != "" {
 				itemsCount++
 				log := fmt.Sprintf(
-					"[blue]\uea73 [-]%s edited the [blue]description[-]: %s → %s [grey](%s)[-]\n",
+					"[link]\uea73 [-]%s edited the [link]description[-]: %s → %s [muted](%s)[-]\n",
 					activity.Update.Author.DisplayName,
 					activity.Update.Changes.Description.Old,
 					activity.Update.Changes.Description.New,
@@ -142,7 +142,7 @@ func GenerateActivityLogs(activities []types.Activity) string {
 			// Handle approvals (if there's an approval activity)
 			itemsCount++
 			log := fmt.Sprintf(
-				"[limegreen] [-]%s [limegreen]APPROVED[-] the pull request [grey](%s)[-]\n",
+				"[approved] [-]%s [approved]APPROVED[-] the pull request [muted](%s)[-]\n",
 				activity.Approval.User.DisplayName,
 				util.FormatTimeAgo(activity.Approval.Date),
 			)
@@ -152,7 +152,7 @@ func GenerateActivityLogs(activities []types.Activity) string {
 			// Handle Changes requested
 			itemsCount++
 			log := fmt.Sprintf(
-				"[yellow]%s[-]%s [yellow]requested changes[-] [grey](%s)[-]\n",
+				"[warning]%s[-]%s [warning]requested changes[-] [muted](%s)[-]\n",
 				ICON_WARNING,
 				activity.ChangesRequested.User.DisplayName,
 				util.FormatTimeAgo(activity.ChangesRequested.Date),
@@ -162,7 +162,7 @@ func GenerateActivityLogs(activities []types.Activity) string {
 			// Handle Comments added
 			itemsCount++
 			log := fmt.Sprintf(
-				"[yellowgreen]%s[-]%s [yellowgreen]added a comment: [-] %s [grey](%s)[-]\n",
+				"[comment]%s[-]%s [comment]added a comment: [-] %s [muted](%s)[-]\n",
 				ICON_COMMENT,
 				activity.Comment.User.DisplayName,
 				activity.Comment.Content.Raw,
@@ -174,7 +174,7 @@ func GenerateActivityLogs(activities []types.Activity) string {
 
 	// Check if there are no activities
 	if itemsCount == 0 {
-		return ICON_EMPTY + "[::b][grey]No activities----![-]"
+		return ICON_EMPTY + "[::b][muted]No activities----![-]"
 	}
 
 	// Add the logs and dividers only if there are actual entries in the section
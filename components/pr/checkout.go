@@ -0,0 +1,178 @@
+package pr
+
+import (
+	"fmt"
+	"log"
+	"simple-git-terminal/apis/bitbucket"
+	"simple-git-terminal/apis/localgit"
+	"simple-git-terminal/apis/provider"
+	"simple-git-terminal/state"
+	"simple-git-terminal/support"
+	"simple-git-terminal/types"
+
+	"github.com/rivo/tview"
+)
+
+const (
+	BUTTON_OK             = "OK"
+	BUTTON_CANCEL         = "Cancel"
+	BUTTON_STASH_CHECKOUT = "Stash and checkout"
+	BUTTON_POP_STASH      = "Pop stash"
+)
+
+// CheckoutSelectedPR fetches and checks out the selected PR's source branch, reporting progress in a modal
+func CheckoutSelectedPR() {
+	pr := state.GlobalState.SelectedPR
+	if pr == nil {
+		return
+	}
+
+	app := state.GlobalState.App
+	background := state.GlobalState.MainFlexWrapper
+	if repo := state.RepoOfPR(pr); !state.IsHomeRepo(repo) {
+		support.ShowModal(app, background, fmt.Sprintf("[warning]%s[-] is not the repository of the current directory", repo.FullName()), []string{BUTTON_OK}, nil)
+		return
+	}
+	modal := support.ShowModal(app, background, fmt.Sprintf("Checking out [warning]%s[-] ...", pr.Source.Branch.Name), nil, nil)
+
+	go func() {
+		dirty, err := localgit.IsWorkingTreeDirty()
+		if err != nil {
+			showCheckoutResult(modal, localgit.CheckoutResult{}, fmt.Errorf("not a usable git repository: %w", err))
+			return
+		}
+
+		if !dirty {
+			runCheckout(modal, pr, false)
+			return
+		}
+
+		app.QueueUpdateDraw(func() {
+			modal.SetText("Your working tree has uncommitted changes.\nStash them before checking out?").
+				ClearButtons().
+				AddButtons([]string{BUTTON_STASH_CHECKOUT, BUTTON_CANCEL})
+			support.SetModalDoneFunc(app, background, modal, func(label string) {
+				if label != BUTTON_STASH_CHECKOUT {
+					return
+				}
+				stashModal := support.ShowModal(app, background, "Stashing and checking out ...", nil, nil)
+				go runCheckout(stashModal, pr, true)
+			})
+		})
+	}()
+}
+
+func runCheckout(modal *tview.Modal, pr *types.PR, stash bool) {
+	result, err := localgit.CheckoutPR(pr, stash, func(message string) {
+		state.GlobalState.App.QueueUpdateDraw(func() {
+			modal.SetText(message + " ...")
+		})
+	})
+	showCheckoutResult(modal, result, err)
+}
+
+// showCheckoutResult reports the checkout and, when local changes were stashed, offers to pop them onto the PR's branch
+func showCheckoutResult(modal *tview.Modal, result localgit.CheckoutResult, err error) {
+	text := fmt.Sprintf("[success]Checked out %s[-]", result.Branch)
+	buttons := []string{BUTTON_OK}
+	if err != nil {
+		log.Printf("[CHECKOUT] %v", err)
+		text = fmt.Sprintf("[danger]Checkout failed[-]\n%v", err)
+	} else {
+		if result.Warning != "" {
+			text += fmt.Sprintf("\n[warning]%s[-]", result.Warning)
+		}
+		if result.Stash != "" {
+			text += fmt.Sprintf("\nYour changes are in stash@{0} (%s)", result.Stash)
+			buttons = []string{BUTTON_POP_STASH, BUTTON_OK}
+		}
+	}
+
+	app := state.GlobalState.App
+	background := state.GlobalState.MainFlexWrapper
+	app.QueueUpdateDraw(func() {
+		modal.SetText(text).
+			ClearButtons().
+			AddButtons(buttons)
+		support.SetModalDoneFunc(app, background, modal, func(label string) {
+			if label != BUTTON_POP_STASH {
+				return
+			}
+			popModal := support.ShowModal(app, background, "Popping stash ...", nil, nil)
+			go func() {
+				text := "[success]Your changes were restored[-]"
+				if err := localgit.PopStash(); err != nil {
+					log.Printf("[CHECKOUT] %v", err)
+					text = fmt.Sprintf("[danger]Could not restore your changes[-], they are still in stash@{0}\n%v", err)
+				}
+				app.QueueUpdateDraw(func() {
+					popModal.SetText(text).
+						ClearButtons().
+						AddButtons([]string{BUTTON_OK})
+					support.SetModalDoneFunc(app, background, popModal, nil)
+				})
+			}()
+		})
+	})
+}
+
+// SelectPRForCurrentBranch finds the open PR whose source is the checked out branch and selects it in the list
+func SelectPRForCurrentBranch() {
+	go func() {
+		branch, err := localgit.CurrentBranch()
+		if err != nil {
+			showPRLookupMessage(fmt.Sprintf("[danger]Could not determine current branch[-]\n%v", err))
+			return
+		}
+
+		// The local branch belongs to the clone, also when the dashboard points the API elsewhere
+		filter := bitbucket.PRFilter{States: []string{"OPEN"}, Source: branch}
+		var prs []types.PR
+		if provider.IsCloud() {
+			prs, err = bitbucket.FetchRepoPRs(state.HomeRepo, bitbucket.BuildFilterQuery(filter, nil), "")
+		} else {
+			prs, _, err = provider.Current().FetchPRs(filter, nil)
+		}
+		if err != nil {
+			showPRLookupMessage(fmt.Sprintf("[danger]Could not look up PRs[-]\n%v", err))
+			return
+		}
+		if len(prs) == 0 {
+			showPRLookupMessage(fmt.Sprintf("No open PR found for branch [warning]%s[-]", branch))
+			return
+		}
+
+		state.GlobalState.App.QueueUpdateDraw(func() {
+			SelectPRInList(prs[0])
+		})
+	}()
+}
+
+// SelectPRInList selects the PR in the list, adding it on top when the current filters hide it
+func SelectPRInList(target types.PR) {
+	prs := *state.GlobalState.FilteredPRs
+	row := -1
+	for i, pr := range prs {
+		if pr.ID == target.ID && pr.Destination.Repository.FullName == target.Destination.Repository.FullName {
+			row = i
+			break
+		}
+	}
+
+	if row == -1 {
+		prs = append([]types.PR{target}, prs...)
+		state.SetFilteredPRs(&prs)
+		renderPRList(state.GlobalState.PrList, prs)
+		row = 0
+	}
+
+	state.GlobalState.PrList.Select(prIndexToRow(row), 0)
+	state.GlobalState.App.SetFocus(state.GlobalState.PrList)
+	HandleOnPrSelect(prs, row)
+}
+
+func showPRLookupMessage(text string) {
+	state.GlobalState.App.QueueUpdateDraw(func() {
+		support.ShowModal(state.GlobalState.App, state.GlobalState.MainFlexWrapper, text, []string{BUTTON_OK}, nil)
+	})
+}
@@ -0,0 +1,50 @@
+package pr
+
+import (
+	"fmt"
+	"simple-git-terminal/components/shared"
+	"simple-git-terminal/state"
+	"simple-git-terminal/util"
+
+	"github.com/rivo/tview"
+)
+
+var responsive *shared.ResponsiveLayout
+
+// NewResponsiveLayout wraps the PR layout so small terminals show one pane at a time,
+// navigated as PRs → PR → file → diff
+func NewResponsiveLayout(app *tview.Application, full, left, description, activities, tree, diff tview.Primitive) *tview.Flex {
+	responsive = shared.NewResponsiveLayout(app, full, []shared.CompactPane{
+		{Name: "PRs", Key: "p", Level: 0, View: left, Crumb: func() string { return "Pull requests" }},
+		{Name: "Description", Key: "d", Level: 1, View: description, Crumb: selectedPRCrumb},
+		{Name: "Activities", Key: "a", Level: 1, View: activities, Crumb: selectedPRCrumb},
+		{Name: "Files", Key: "t", Level: 2, View: tree, Crumb: currentFileCrumb},
+		{Name: "Diff", Key: "c", Level: 3, View: diff, Crumb: func() string { return "Diff" }},
+	})
+	return responsive.Flex
+}
+
+// IsCompact reports whether the PR mode shows one pane at a time
+func IsCompact() bool {
+	return responsive != nil && responsive.IsCompact()
+}
+
+// CompactBack goes up one level of the breadcrumbs, reporting whether it did
+func CompactBack() bool {
+	return IsCompact() && responsive.Back()
+}
+
+func selectedPRCrumb() string {
+	pr := state.GlobalState.SelectedPR
+	if pr == nil {
+		return ""
+	}
+	return fmt.Sprintf("#%d %s", pr.ID, util.EllipsizeText(pr.Title, 40))
+}
+
+func currentFileCrumb() string {
+	if currentFileDiff == nil {
+		return "Files"
+	}
+	return currentFileDiff.path
+}
@@ -0,0 +1,377 @@
+package pr
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"simple-git-terminal/apis/provider"
+	"simple-git-terminal/state"
+	"simple-git-terminal/support"
+	"simple-git-terminal/theme"
+	"simple-git-terminal/types"
+	"simple-git-terminal/util"
+	"sort"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+const (
+	ACTIVITIES_TITLE   = "Activities [key]a|A"
+	CONVERSATION_TITLE = "Conversation [key]x[-] [muted]enter jump to line | u unresolved | @ mentions me | n new task | space resolve task | esc activities[-]"
+)
+
+// commentThread is a top level comment with all of its replies in reading order
+type commentThread struct {
+	root    types.Comment
+	replies []threadReply
+}
+
+type threadReply struct {
+	comment types.Comment
+	depth   int // 1 for direct replies to the root
+}
+
+// conversationRow is what a row of the conversation shows. The rows of a thread share its thread, comment
+// rows also hold their comment and task rows their task.
+type conversationRow struct {
+	thread  *commentThread
+	comment *types.Comment
+	task    *types.Task
+}
+
+// conversationView is the threaded comment list of the selected PR shown in the activity pane
+type conversationView struct {
+	pr             *types.PR
+	threads        []commentThread
+	tasks          []types.Task
+	unresolvedOnly bool
+	mentionsOnly   bool
+	table          *tview.Table // Last rendered, to find the selected thread
+}
+
+var currentConversation *conversationView
+
+// ShowConversation loads every comment and task of the selected PR and shows them grouped into threads
+func ShowConversation() {
+	pr := state.GlobalState.SelectedPR
+	if pr == nil {
+		return
+	}
+	loadConversation(pr, 0)
+}
+
+// loadConversation (re)loads the conversation of pr with row selected
+func loadConversation(pr *types.PR, row int) {
+	var tasksErr error
+	state.GlobalState.ActivityView.SetTitle(CONVERSATION_TITLE)
+	support.ShowLoadingSpinner(state.GlobalState.ActivityView, func() (interface{}, error) {
+		comments, err := provider.ForPR(pr).FetchComments(pr.ID)
+		if err != nil {
+			return nil, err
+		}
+		view := &conversationView{pr: pr, threads: buildThreads(comments)}
+		view.tasks, tasksErr = provider.ForPR(pr).FetchTasks(pr.ID)
+		if tasksErr != nil {
+			log.Printf("[TASKS] %v", tasksErr)
+		}
+		return view, nil
+	}, func(result interface{}, err error) {
+		if err != nil {
+			log.Printf("[CONVERSATION] %v", err)
+			UpdateActivityView(fmt.Sprintf("[danger]Failed to load comments: %v[-]", tview.Escape(err.Error())))
+			return
+		}
+		view := result.(*conversationView)
+		if currentConversation != nil && currentConversation.pr.ID == pr.ID {
+			view.unresolvedOnly = currentConversation.unresolvedOnly
+			view.mentionsOnly = currentConversation.mentionsOnly
+		}
+		currentConversation = view
+		renderConversation()
+		view.table.Select(min(row, view.table.GetRowCount()-1), 0)
+
+		// Keep the task column in step with what the conversation shows
+		if tasksErr == nil && storeTaskCount(pr, view.tasks) {
+			rerenderPRList()
+		}
+	})
+}
+
+// buildThreads groups comments by Parent.ID. Replies whose parent is missing start a thread of their own.
+func buildThreads(comments []types.Comment) []commentThread {
+	sort.SliceStable(comments, func(i, j int) bool {
+		return comments[i].CreatedOn < comments[j].CreatedOn
+	})
+
+	byID := make(map[int]bool)
+	for _, comment := range comments {
+		byID[comment.ID] = true
+	}
+
+	children := make(map[int][]types.Comment)
+	var roots []types.Comment
+	for _, comment := range comments {
+		if comment.Parent.ID > 0 && byID[comment.Parent.ID] {
+			children[comment.Parent.ID] = append(children[comment.Parent.ID], comment)
+		} else {
+			roots = append(roots, comment)
+		}
+	}
+
+	var threads []commentThread
+	for _, root := range roots {
+		thread := commentThread{root: root}
+		var walk func(parentID int, depth int)
+		walk = func(parentID int, depth int) {
+			for _, reply := range children[parentID] {
+				thread.replies = append(thread.replies, threadReply{comment: reply, depth: depth})
+				walk(reply.ID, depth+1)
+			}
+		}
+		walk(root.ID, 1)
+		threads = append(threads, thread)
+	}
+	return threads
+}
+
+func (t commentThread) isResolved() bool {
+	return t.root.Resolution != nil
+}
+
+// mentions reports whether any comment of the thread mentions the user. Bitbucket stores mentions
+// as @{account_id} in the raw markdown, hand-typed ones use the nickname.
+func (t commentThread) mentions(user *types.User) bool {
+	if user == nil {
+		return false
+	}
+	var nickname *regexp.Regexp
+	if user.Nickname != "" {
+		// @bob must not match @bobby or bob@example.com
+		nickname = regexp.MustCompile(`(?:^|\W)@` + regexp.QuoteMeta(user.Nickname) + `(?:$|\W)`)
+	}
+	comments := []types.Comment{t.root}
+	for _, reply := range t.replies {
+		comments = append(comments, reply.comment)
+	}
+	for _, comment := range comments {
+		raw := comment.Content.Raw
+		if user.AccountID != "" && strings.Contains(raw, "@{"+user.AccountID+"}") {
+			return true
+		}
+		if nickname != nil && nickname.MatchString(raw) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t commentThread) location() string {
+	if t.root.Inline.Path == "" {
+		return "General"
+	}
+	return fmt.Sprintf("%s:%d", t.root.Inline.Path, max(t.root.Inline.To, t.root.Inline.From))
+}
+
+func renderConversation() {
+	view := currentConversation
+
+	table := tview.NewTable().
+		SetSelectable(true, false)
+	table.SetBackgroundColor(tcell.ColorDefault)
+	table.SetSelectedStyle(tcell.StyleDefault.Foreground(theme.Current.Selected))
+
+	row := 0
+	addRow := func(text string, reference conversationRow) {
+		table.SetCell(row, 0, tview.NewTableCell(text).
+			SetExpansion(1).
+			SetReference(reference))
+		row++
+	}
+
+	// Tasks are listed below their comment, the others on top
+	commented := make(map[int]bool)
+	for _, thread := range view.threads {
+		commented[thread.root.ID] = true
+		for _, reply := range thread.replies {
+			commented[reply.comment.ID] = true
+		}
+	}
+	tasksByComment := make(map[int][]*types.Task)
+	var prTasks []*types.Task
+	openTasks := 0
+	for i := range view.tasks {
+		task := &view.tasks[i]
+		if !task.IsResolved() {
+			openTasks++
+		}
+		if task.Comment != nil && commented[task.Comment.ID] {
+			tasksByComment[task.Comment.ID] = append(tasksByComment[task.Comment.ID], task)
+		} else {
+			prTasks = append(prTasks, task)
+		}
+	}
+	addComment := func(thread *commentThread, comment *types.Comment, depth int) {
+		for _, line := range formatThreadComment(*comment, depth) {
+			addRow(line, conversationRow{thread: thread, comment: comment})
+		}
+		for _, task := range tasksByComment[comment.ID] {
+			addRow(formatTask(*task, depth+1), conversationRow{thread: thread, comment: comment, task: task})
+		}
+	}
+
+	if len(prTasks) > 0 {
+		addRow("[accent]●[-] Tasks", conversationRow{})
+		for _, task := range prTasks {
+			addRow(formatTask(*task, 1), conversationRow{task: task})
+		}
+		addRow("", conversationRow{})
+	}
+
+	shown, unresolved := 0, 0
+	for i := range view.threads {
+		thread := &view.threads[i]
+		if !thread.isResolved() {
+			unresolved++
+		}
+		if view.unresolvedOnly && thread.isResolved() {
+			continue
+		}
+		if view.mentionsOnly && !thread.mentions(state.CurrentUser) {
+			continue
+		}
+		shown++
+
+		status := "[warning]unresolved[-]"
+		if thread.isResolved() {
+			status = "[resolved]✔ resolved[-]"
+		}
+		addRow(fmt.Sprintf("[accent]●[-] %s %s [muted]%d repl(ies)[-]", tview.Escape(thread.location()), status, len(thread.replies)), conversationRow{thread: thread, comment: &thread.root})
+
+		addComment(thread, &thread.root, 1)
+		for j := range thread.replies {
+			addComment(thread, &thread.replies[j].comment, thread.replies[j].depth+1)
+		}
+		addRow("", conversationRow{thread: thread})
+	}
+
+	if shown == 0 && len(prTasks) == 0 {
+		table.SetCell(0, 0, util.CellFormat(" No comments match the filters", theme.Current.Muted).
+			SetReference(conversationRow{}))
+	}
+	view.table = table
+
+	table.SetSelectedFunc(func(row, column int) {
+		if selected, ok := table.GetCell(row, 0).GetReference().(conversationRow); ok && selected.thread != nil {
+			jumpToThread(view.pr, selected.thread)
+		}
+	})
+
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEsc {
+			loadActivities()
+			return nil
+		}
+		if event.Key() != tcell.KeyRune {
+			return event
+		}
+		row, _ := table.GetSelection()
+		selected, _ := table.GetCell(row, 0).GetReference().(conversationRow)
+		switch event.Rune() {
+		case 'n':
+			createTask(view.pr, selected.comment, row)
+			return nil
+		case ' ':
+			if selected.task != nil {
+				toggleTask(view.pr, *selected.task, row)
+			}
+			return nil
+		case 'u':
+			view.unresolvedOnly = !view.unresolvedOnly
+			renderConversation()
+			return nil
+		case '@':
+			view.mentionsOnly = !view.mentionsOnly
+			renderConversation()
+			return nil
+		}
+		return event
+	})
+
+	var filters []string
+	if view.unresolvedOnly {
+		filters = append(filters, "unresolved only")
+	}
+	if view.mentionsOnly {
+		filters = append(filters, "mentions me")
+	}
+	title := fmt.Sprintf("%s [warning]%d unresolved / %d threads, %d open tasks[-]", CONVERSATION_TITLE, unresolved, len(view.threads), openTasks)
+	if len(filters) > 0 {
+		title += " [accent](" + strings.Join(filters, ", ") + ")[-]"
+	}
+
+	state.GlobalState.ActivityView.SetTitle(title)
+	UpdateActivityView(table)
+	state.GlobalState.App.SetFocus(table)
+}
+
+// formatThreadComment renders a comment as an author line followed by its text, indented by depth
+func formatThreadComment(comment types.Comment, depth int) []string {
+	indent := strings.Repeat("  ", depth)
+
+	author := fmt.Sprintf("[info]%s[-]", tview.Escape(comment.User.DisplayName))
+	if comment.Pending {
+		author += " [warning](Draft)[-]"
+	}
+	lines := []string{fmt.Sprintf("%s%s%s [muted](%s)[-]", indent, ICON_COMMENT, author, util.FormatTimeAgo(comment.CreatedOn))}
+
+	if comment.Deleted {
+		return append(lines, indent+"  [muted](deleted)[-]")
+	}
+	for _, line := range strings.Split(strings.TrimSpace(comment.Content.Raw), "\n") {
+		lines = append(lines, indent+"  "+tview.Escape(line))
+	}
+	return lines
+}
+
+// jumpToThread opens the diff of the thread's file with the commented line selected
+func jumpToThread(pr *types.PR, thread *commentThread) {
+	inline := thread.root.Inline
+	if inline.Path == "" {
+		return
+	}
+
+	type fileDiff struct {
+		text     string
+		comments []types.Comment
+	}
+
+	support.ShowLoadingSpinner(state.GlobalState.DiffDetails, func() (interface{}, error) {
+		text, err := fetchDiffContent(pr, inline.Path)
+		if err != nil {
+			return nil, err
+		}
+		return fileDiff{text: text, comments: getInlineComments(*pr, inline.Path)}, nil
+	}, func(result interface{}, err error) {
+		if err != nil {
+			UpdateDiffDetailsView(fmt.Sprintf("[danger]Failed to load diff of %s: %v[-]", inline.Path, err))
+			return
+		}
+		diff := result.(fileDiff)
+		table := ShowFileDiff(inline.Path, diff.text, diff.comments)
+		state.GlobalState.App.SetFocus(table)
+
+		lineIndex := util.DisplayLineOf(util.DisplayLinePositions(currentFileDiff.hunks), inline.From, inline.To)
+		if lineIndex < 0 {
+			state.GlobalState.DiffDetails.SetTitle(DIFF_TITLE + " " + DIFF_KEYS_HINT + " [warning]commented line is no longer part of the diff[-]")
+			return
+		}
+		for row := 0; row < table.GetRowCount(); row++ {
+			if index, ok := table.GetCell(row, 0).GetReference().(int); ok && index == lineIndex {
+				table.Select(row, 0)
+				break
+			}
+		}
+	})
+}
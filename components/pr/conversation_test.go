@@ -0,0 +1,65 @@
+package pr
+
+import (
+	"simple-git-terminal/types"
+	"testing"
+)
+
+func comment(id int, parent int, createdOn string, raw string) types.Comment {
+	var c types.Comment
+	c.ID = id
+	c.Parent.ID = parent
+	c.CreatedOn = createdOn
+	c.Content.Raw = raw
+	return c
+}
+
+func TestBuildThreads(t *testing.T) {
+	threads := buildThreads([]types.Comment{
+		comment(4, 2, "2024-01-04T00:00:00Z", "reply to reply"),
+		comment(1, 0, "2024-01-01T00:00:00Z", "first"),
+		comment(3, 0, "2024-01-03T00:00:00Z", "second"),
+		comment(2, 1, "2024-01-02T00:00:00Z", "reply"),
+		comment(5, 99, "2024-01-05T00:00:00Z", "parent is gone"),
+	})
+
+	if len(threads) != 3 {
+		t.Fatalf("got %d threads, want 3", len(threads))
+	}
+	if threads[0].root.ID != 1 || threads[1].root.ID != 3 || threads[2].root.ID != 5 {
+		t.Errorf("roots = %d, %d, %d, want 1, 3, 5 in creation order", threads[0].root.ID, threads[1].root.ID, threads[2].root.ID)
+	}
+	replies := threads[0].replies
+	if len(replies) != 2 || replies[0].comment.ID != 2 || replies[0].depth != 1 || replies[1].comment.ID != 4 || replies[1].depth != 2 {
+		t.Errorf("replies of the first thread = %+v, want 2 at depth 1 and 4 at depth 2", replies)
+	}
+}
+
+func TestThreadMentions(t *testing.T) {
+	me := &types.User{AccountID: "557058:abc", Nickname: "bob"}
+
+	tests := []struct {
+		name string
+		raw  string
+		want bool
+	}{
+		{name: "account id", raw: "thoughts @{557058:abc}?", want: true},
+		{name: "nickname", raw: "@bob can you check", want: true},
+		{name: "nickname before punctuation", raw: "ping @bob.", want: true},
+		{name: "longer nickname", raw: "@bobby can you check", want: false},
+		{name: "email address", raw: "mail bob@example.com or alice@bob", want: false},
+		{name: "other account id", raw: "@{557058:abcd}", want: false},
+		{name: "no mention", raw: "looks good", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			thread := commentThread{root: comment(1, 0, "", "root"), replies: []threadReply{{comment: comment(2, 1, "", tt.raw), depth: 1}}}
+			if got := thread.mentions(me); got != tt.want {
+				t.Errorf("mentions(%q) = %v, want %v", tt.raw, got, tt.want)
+			}
+		})
+	}
+	if (commentThread{root: comment(1, 0, "", "@bob")}).mentions(nil) {
+		t.Error("no user is never mentioned")
+	}
+}
@@ -0,0 +1,64 @@
+package pr
+
+import (
+	"fmt"
+	"log"
+	"simple-git-terminal/apis/bitbucket"
+	"simple-git-terminal/apis/provider"
+	"simple-git-terminal/config"
+	"simple-git-terminal/state"
+	"simple-git-terminal/types"
+	"simple-git-terminal/ui"
+)
+
+// DashboardSource builds the repositories of dashboard mode from the config. Without any configured,
+// the workspace of the local clone is used.
+func DashboardSource() (bitbucket.DashboardSource, error) {
+	dashboard := config.Current.Dashboard
+	source := bitbucket.DashboardSource{
+		Workspace:          dashboard.Workspace,
+		WorkspaceRepoLimit: dashboard.WorkspaceRepoLimit,
+	}
+	for _, fullName := range dashboard.Repos {
+		repo, ok := state.ParseRepoContext(fullName)
+		if !ok {
+			log.Printf("[DASHBOARD] Ignoring invalid repository %q, use workspace/repo", fullName)
+			continue
+		}
+		source.Repos = append(source.Repos, repo)
+	}
+
+	if len(source.Repos) == 0 && source.Workspace == "" {
+		source.Workspace = state.HomeRepo.Workspace
+	}
+	if len(source.Repos) == 0 && source.Workspace == "" {
+		return source, fmt.Errorf("no dashboard repositories configured, set dashboard.repos or dashboard.workspace in %s", config.Path())
+	}
+	return source, nil
+}
+
+// fetchPRList fetches the PR list, from the current repository or across the dashboard repositories
+func fetchPRList(searchTerm string) ([]types.PR, types.Pagination, error) {
+	if !state.DashboardMode {
+		return provider.Current().FetchPRs(bitbucket.CurrentFilter(searchTerm), state.CurrentUser)
+	}
+
+	source, err := DashboardSource()
+	if err != nil {
+		return nil, types.Pagination{}, err
+	}
+	prs, err := bitbucket.FetchDashboardPRs(source, state.CurrentUser, bitbucket.CurrentFilter(searchTerm), state.PRSort)
+	if err != nil {
+		return nil, types.Pagination{}, err
+	}
+
+	// Every repository was sorted on its own, so merge them by sorting again
+	sortKey := currentSortKey()
+	if sortKey == "" {
+		sortKey = "-updated"
+	}
+	ui.SortPRs(prs, sortKey, prListContext(prs))
+
+	// The dashboard shows the first page of every repository at once
+	return prs, types.Pagination{Page: 1, Size: len(prs), PageLen: len(prs)}, nil
+}
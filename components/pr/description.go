@@ -2,7 +2,7 @@ package pr
 
 import (
 	"fmt"
-	"github.com/gdamore/tcell/v2"
+	"simple-git-terminal/theme"
 	"strings"
 
 	"simple-git-terminal/types"
@@ -16,7 +16,7 @@ func GeneratePRDetail(pr *types.PR) string {
 	// Get the color based on the state
 	stateColor := util.GetPRStateColor(pr.State)
 
-	otherColor := tcell.ColorSlateGray
+	otherColor := theme.Current.Muted
 
 	reviewers := StyleReviewerNames(GetReviewerNames(pr))
 
@@ -28,6 +28,7 @@ func GeneratePRDetail(pr *types.PR) string {
 			"[::b]Author:[-] [%s]%s[-]\n"+
 			"[::b]Created On:[-] [%s]%s[-]\n"+
 			"[::b]Updated On:[-] [%s]%s[-]\n"+
+			"[::b]Tasks:[-] %s\n"+
 			"[::b]Link:[-] [%s]%s[-]\n"+
 			"[::b]Description:[-] \n%s\n",
 		pr.ID,
@@ -36,6 +37,7 @@ func GeneratePRDetail(pr *types.PR) string {
 		otherColor, pr.Author.DisplayName,
 		otherColor, util.FormatCombinedTimeAgo(pr.CreatedOn),
 		otherColor, util.FormatCombinedTimeAgo(pr.UpdatedOn),
+		formatTaskCount(*pr),
 		otherColor, pr.Links.HTML.Href,
 		description, // Rendered Markdown content
 	)
@@ -66,11 +68,11 @@ func StyleReviewerNames(names []string) string {
 	// Apply individual styling (e.g., color) to each name
 	for _, name := range names {
 		// Apply the desired style (e.g., orange color) to each reviewer name
-		styledNames = append(styledNames, fmt.Sprintf("[orange]%s[-]", name))
+		styledNames = append(styledNames, fmt.Sprintf("[accent]%s[-]", name))
 	}
 
 	// Join all styled names with a pipe (" | ") separator
-	return strings.Join(styledNames, " [grey]|[-] ")
+	return strings.Join(styledNames, " [muted]|[-] ")
 }
 
 // Formats the PR description for display
@@ -0,0 +1,242 @@
+package pr
+
+import (
+	"fmt"
+	"log"
+	"simple-git-terminal/apis/provider"
+	"simple-git-terminal/state"
+	"simple-git-terminal/support"
+	"simple-git-terminal/types"
+	"simple-git-terminal/util"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+const (
+	DIFF_CONTEXT_STEP = 10 // Lines added per expand key press
+	DIFF_TITLE        = "Diff Content [key]c|C"
+	DIFF_KEYS_HINT    = "[muted] [ ] expand | f/F full file | n draft comment | v mark | y yank[-]"
+)
+
+// fileDiffState keeps the diff currently shown in the diff pane so it can be re-rendered with more context
+type fileDiffState struct {
+	path        string
+	header      string
+	hunks       []util.DiffHunk
+	comments    []types.Comment
+	sourceLines []string     // Full file at the source commit, fetched on first expand
+	table       *tview.Table // Last rendered diff
+	mark        int          // Rendered line a yank range starts at, -1 for none
+}
+
+var currentFileDiff *fileDiffState
+
+// ShowFileDiff renders the diff of a single file and remembers it for context expansion
+func ShowFileDiff(path string, diffText string, comments []types.Comment) *tview.Table {
+	header, hunks := util.ParseDiffHunks(diffText)
+	currentFileDiff = &fileDiffState{
+		path:     path,
+		header:   header,
+		hunks:    hunks,
+		comments: comments,
+		mark:     -1,
+	}
+	return renderCurrentFileDiff()
+}
+
+func renderCurrentFileDiff() *tview.Table {
+	diff := currentFileDiff
+	table := util.GenerateColorizedDiffView(util.BuildDiffText(diff.header, diff.hunks), diff.comments)
+	diff.table = table
+	diff.mark = -1 // Expanding moves the lines
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() != tcell.KeyRune {
+			return event
+		}
+		switch event.Rune() {
+		case '[', ']':
+			row, _ := table.GetSelection()
+			expandContextAt(table, row, event.Rune() == '[')
+			return nil
+		case 'f':
+			showFullFile(true)
+			return nil
+		case 'F':
+			showFullFile(false)
+			return nil
+		case 'n':
+			row, _ := table.GetSelection()
+			DraftInlineCommentAtRow(table, row)
+			return nil
+		case 'v':
+			row, _ := table.GetSelection()
+			toggleDiffMark(table, row)
+			return nil
+		}
+		return event
+	})
+
+	state.GlobalState.DiffDetails.SetTitle(DIFF_TITLE + " " + DIFF_KEYS_HINT)
+	UpdateDiffDetailsView(table)
+	return table
+}
+
+// toggleDiffMark starts a yank range at the line of the table row, or drops the one already started
+func toggleDiffMark(table *tview.Table, row int) {
+	diff := currentFileDiff
+	title := DIFF_TITLE + " " + DIFF_KEYS_HINT
+	if diff.mark >= 0 {
+		diff.mark = -1
+	} else if lineIndex, ok := displayLineAtRow(table, row); ok {
+		diff.mark = lineIndex
+		title += fmt.Sprintf(" [accent]marked row %d, y yanks up to the cursor[-]", row+1)
+	}
+	state.GlobalState.DiffDetails.SetTitle(title)
+}
+
+// selectedDiffLines is the range of rendered lines from the mark to the cursor, or else the hunk at the cursor.
+// ok is false when the diff has no selected line.
+func selectedDiffLines() (from int, to int, marked bool, ok bool) {
+	diff := currentFileDiff
+	if diff == nil || diff.table == nil || len(diff.hunks) == 0 {
+		return 0, 0, false, false
+	}
+	row, _ := diff.table.GetSelection()
+	cursor, ok := displayLineAtRow(diff.table, row)
+	if !ok {
+		return 0, 0, false, false
+	}
+	if diff.mark >= 0 {
+		return diff.mark, cursor, true, true
+	}
+	from, to = util.HunkDisplayRange(diff.hunks, util.HunkAtDisplayLine(diff.hunks, cursor))
+	return from, to, false, true
+}
+
+// expandContextAt grows the hunk containing the given table row upwards or downwards
+func expandContextAt(table *tview.Table, row int, above bool) {
+	lineIndex, ok := displayLineAtRow(table, row)
+	if !ok || len(currentFileDiff.hunks) == 0 {
+		return
+	}
+	hunkIndex := util.HunkAtDisplayLine(currentFileDiff.hunks, lineIndex)
+
+	expand := func() {
+		hunks, added := util.ExpandHunk(currentFileDiff.hunks, hunkIndex, above, DIFF_CONTEXT_STEP, currentFileDiff.sourceLines)
+		currentFileDiff.hunks = hunks
+		log.Printf("[DIFF] Expanded hunk %d of %s by %d lines", hunkIndex, currentFileDiff.path, added)
+		newTable := renderCurrentFileDiff()
+		newTable.Select(row+boolToInt(above)*added, 0)
+		state.GlobalState.App.SetFocus(newTable)
+	}
+
+	if currentFileDiff.sourceLines != nil {
+		expand()
+		return
+	}
+
+	pr := state.GlobalState.SelectedPR
+	support.ShowLoadingSpinner(state.GlobalState.DiffDetails, func() (interface{}, error) {
+		return fetchFileAtCommit(pr, pr.Source.Commit.Hash, currentFileDiff.path)
+	}, func(result interface{}, err error) {
+		if err != nil {
+			UpdateDiffDetailsView(fmt.Sprintf("[danger]Failed to load file for context: %v[-]", err))
+			return
+		}
+		content, _ := result.(string)
+		currentFileDiff.sourceLines = strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+		expand()
+	})
+}
+
+// displayLineAtRow resolves the diff line index of a table row, skipping over rendered comment rows
+func displayLineAtRow(table *tview.Table, row int) (int, bool) {
+	for r := row; r >= 0; r-- {
+		if lineIndex, ok := table.GetCell(r, 0).GetReference().(int); ok {
+			return lineIndex, true
+		}
+	}
+	return 0, false
+}
+
+// showFullFile shows the whole file at the PR's source or destination commit with the changed lines marked
+func showFullFile(atSource bool) {
+	if currentFileDiff == nil || state.GlobalState.SelectedPR == nil {
+		return
+	}
+	pr := state.GlobalState.SelectedPR
+
+	commitHash := pr.Destination.Commit.Hash
+	side := "destination"
+	markColor := "removed"
+	if atSource {
+		commitHash = pr.Source.Commit.Hash
+		side = "source"
+		markColor = "added"
+	}
+	if commitHash == "" {
+		UpdateDiffDetailsView(fmt.Sprintf("[danger]No %s commit known for this PR[-]", side))
+		return
+	}
+
+	path := currentFileDiff.path
+	changedLines := util.ChangedLineNumbers(currentFileDiff.hunks, atSource)
+
+	support.ShowLoadingSpinner(state.GlobalState.DiffDetails, func() (interface{}, error) {
+		return fetchFileAtCommit(pr, commitHash, path)
+	}, func(result interface{}, err error) {
+		if err != nil {
+			UpdateDiffDetailsView(fmt.Sprintf("[danger]Failed to load %s at %s: %v[-]", path, side, err))
+			return
+		}
+		content, _ := result.(string)
+		if atSource {
+			currentFileDiff.sourceLines = strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+		}
+
+		table := util.GenerateFullFileView(content, changedLines, markColor)
+		table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+			if event.Key() == tcell.KeyEsc {
+				state.GlobalState.App.SetFocus(renderCurrentFileDiff())
+				return nil
+			}
+			return event
+		})
+
+		state.GlobalState.DiffDetails.SetTitle(fmt.Sprintf("%s @ %s (%s) [muted]esc back to diff[-]", path, util.ShortHash(commitHash), side))
+		UpdateDiffDetailsView(table)
+		if firstChanged := firstKey(changedLines); firstChanged > 0 {
+			table.Select(firstChanged-1, 0)
+		}
+		state.GlobalState.App.SetFocus(table)
+	})
+}
+
+// fetchFileAtCommit prefers the local git object store and falls back to the src API of the PR's repository
+func fetchFileAtCommit(pr *types.PR, commitHash string, path string) (string, error) {
+	content, err := util.ReadFileAtCommit(commitHash, path)
+	if err == nil {
+		return content, nil
+	}
+	log.Printf("[DIFF] %v, falling back to API", err)
+	return provider.ForPR(pr).FetchFileAtCommit(commitHash, path)
+}
+
+func firstKey(lines map[int]bool) int {
+	first := 0
+	for line := range lines {
+		if first == 0 || line < first {
+			first = line
+		}
+	}
+	return first
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
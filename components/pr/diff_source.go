@@ -0,0 +1,79 @@
+package pr
+
+import (
+	"fmt"
+	"log"
+	"simple-git-terminal/apis/bitbucket"
+	"simple-git-terminal/apis/localgit"
+	"simple-git-terminal/apis/provider"
+	"simple-git-terminal/config"
+	"simple-git-terminal/state"
+	"simple-git-terminal/types"
+	"simple-git-terminal/util"
+)
+
+// useLocalDiff is true when configured and pr belongs to the local clone
+func useLocalDiff(pr *types.PR) bool {
+	return config.UseLocalDiff() && state.IsHomeRepo(state.RepoOfPR(pr))
+}
+
+// fetchDiffstat uses the local clone when configured, falling back to the Bitbucket API
+func fetchDiffstat(pr *types.PR) ([]types.DiffstatEntry, error) {
+	if useLocalDiff(pr) {
+		entries, err := localgit.FetchDiffstat(pr)
+		if err == nil {
+			return entries, nil
+		}
+		log.Printf("[DIFF] Local diffstat failed for PR #%d, falling back to API: %v", pr.ID, err)
+	}
+	return provider.ForPR(pr).FetchDiffstat(pr.ID)
+}
+
+// fetchDiffContent uses the local clone when configured, falling back to the Bitbucket API
+func fetchDiffContent(pr *types.PR, path string) (string, error) {
+	if useLocalDiff(pr) {
+		diff, err := localgit.FetchDiffContent(pr, path)
+		if err == nil {
+			return diff, nil
+		}
+		log.Printf("[DIFF] Local diff failed for %s in PR #%d, falling back to API: %v", path, pr.ID, err)
+	}
+	return provider.ForPR(pr).FetchDiffContent(pr.ID, path)
+}
+
+// fetchChangedFilesBetween lists the files touched between two commits of repo, locally when both commits are available
+func fetchChangedFilesBetween(repo state.RepoContext, oldCommit string, newCommit string) ([]string, error) {
+	if paths, err := localgit.ChangedFilesBetween(oldCommit, newCommit); err == nil {
+		return paths, nil
+	}
+
+	if !provider.IsCloud() {
+		return nil, fmt.Errorf("commits %s..%s are not in the local clone", util.ShortHash(oldCommit), util.ShortHash(newCommit))
+	}
+	entries, err := bitbucket.FetchDiffstatBetween(repo, newCommit, oldCommit)
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, entry := range entries {
+		if entry.New != nil {
+			paths = append(paths, entry.New.Path)
+		}
+		if entry.Old != nil && (entry.New == nil || entry.Old.Path != entry.New.Path) {
+			paths = append(paths, entry.Old.Path)
+		}
+	}
+	return paths, nil
+}
+
+// fetchCommitDiff returns a single commit's diff of repo, or the diff between two commits when oldCommit is given
+func fetchCommitDiff(repo state.RepoContext, newCommit string, oldCommit string) (string, error) {
+	if diff, err := localgit.CommitDiff(newCommit, oldCommit); err == nil {
+		return diff, nil
+	}
+	if !provider.IsCloud() {
+		return "", fmt.Errorf("commit %s is not in the local clone", util.ShortHash(newCommit))
+	}
+	return bitbucket.FetchCommitDiff(repo, newCommit, oldCommit)
+}
@@ -2,11 +2,12 @@ package pr
 
 import (
 	"fmt"
-	"simple-git-terminal/apis/bitbucket"
+	"log"
+	"simple-git-terminal/apis/provider"
 	"simple-git-terminal/state"
 	"simple-git-terminal/support"
+	"simple-git-terminal/theme"
 	"simple-git-terminal/types"
-	"simple-git-terminal/util"
 	"strings"
 	"time"
 
@@ -16,8 +17,6 @@ import (
 
 const (
 	ROOT_COLOR     = tcell.ColorDefault
-	DIR_COLOR      = tcell.ColorBlue
-	FILE_COLOR     = tcell.ColorGrey
 	ICON_DIRECTORY = "\uf07b " // Folder icon
 	ICON_FILE      = "\uf15b " // File icon
 	ICON_CONFLICT  = "\u26A0"
@@ -27,6 +26,7 @@ const (
 type NodeReference struct {
 	Path  string
 	IsDir bool
+	Label string // Display text without the viewed marker
 }
 
 var debounceTimer *time.Timer
@@ -47,16 +47,17 @@ func GenerateDiffStatTree(data []types.DiffstatEntry) *tview.TreeView {
 		ref := &NodeReference{
 			Path:  path,
 			IsDir: isDir,
+			Label: displayName,
 		}
 		node := tview.NewTreeNode(displayName). // Display text with icon
 							SetReference(ref).
 							SetSelectable(true)
 		node.SetTextStyle(tcell.StyleDefault.Background(tcell.ColorDefault))
 		if isDir {
-			node.SetColor(DIR_COLOR)
+			node.SetColor(theme.Current.Link)
 			node.SetExpanded(true)
 		} else {
-			node.SetColor(FILE_COLOR)
+			node.SetColor(theme.Current.Muted)
 		}
 		target.AddChild(node)
 		return node
@@ -76,6 +77,7 @@ func GenerateDiffStatTree(data []types.DiffstatEntry) *tview.TreeView {
 					part = commentSymbol + part // Prepend comment symbol to file name
 					displayName := fmt.Sprintf("%s%s | %s", ICON_FILE, part, diffStatText)
 					currentNode = add(currentNode, fullPath, false, displayName)
+					setFileNodeText(currentNode)
 
 					// Trigger the UI refresh to make sure the node is updated correctly
 					// Set the node's expanded state after the comment is fetched
@@ -102,6 +104,8 @@ func GenerateDiffStatTree(data []types.DiffstatEntry) *tview.TreeView {
 		}
 	}
 
+	var files []string
+
 	// Iterate through the diffstat entries and create nodes for files and directories
 	for _, entry := range data {
 		var fileName string
@@ -110,23 +114,42 @@ func GenerateDiffStatTree(data []types.DiffstatEntry) *tview.TreeView {
 		} else if entry.Old != nil {
 			fileName = entry.Old.Path
 		}
+		files = append(files, fileName)
 
 		// Prepare diff stat text with + for lines added and - for lines removed
 		var diffStatText string
 		if entry.LinesAdded > 0 {
-			diffStatText = fmt.Sprintf("[%s]+ %d[-]", tcell.ColorGreen, entry.LinesAdded)
+			diffStatText = fmt.Sprintf("[added]+ %d[-]", entry.LinesAdded)
 		}
 		if entry.LinesRemoved > 0 {
 			if len(diffStatText) > 0 {
 				diffStatText += " | "
 			}
-			diffStatText += fmt.Sprintf("[%s]- %d[-]", tcell.ColorRed, entry.LinesRemoved)
+			diffStatText += fmt.Sprintf("[removed]- %d[-]", entry.LinesRemoved)
 		}
 
 		// Create the path structure in the tree
 		createPathTree(root, fileName, diffStatText)
 	}
 
+	state.GlobalState.DiffStatView.SetTitle(diffTreeTitle(files))
+
+	// Mark files as viewed
+	tree.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyRune && event.Rune() == 'v' {
+			if node := tree.GetCurrentNode(); node != nil {
+				toggleViewedAt(node, files)
+				root.Walk(func(node, parent *tview.TreeNode) bool {
+					setFileNodeText(node)
+					return true
+				})
+				state.GlobalState.DiffStatView.SetTitle(diffTreeTitle(files))
+			}
+			return nil
+		}
+		return event
+	})
+
 	tree.SetSelectedFunc(func(node *tview.TreeNode) {
 		if node.IsExpanded() {
 			node.SetExpanded(false)
@@ -147,12 +170,25 @@ func GenerateDiffStatTree(data []types.DiffstatEntry) *tview.TreeView {
 			if state.GlobalState.CurrentView != state.GlobalState.DiffStatView {
 				OpenFileSpecificDiff(node, false)
 			}
-			node.SetSelectedTextStyle(tcell.StyleDefault.Foreground(tcell.ColorOrange))
+			node.SetSelectedTextStyle(tcell.StyleDefault.Foreground(theme.Current.Accent))
 		})
 	})
 	return tree
 }
 
+// setFileNodeText prefixes file nodes with the viewed marker
+func setFileNodeText(node *tview.TreeNode) {
+	ref, ok := node.GetReference().(*NodeReference)
+	if !ok || ref.IsDir {
+		return
+	}
+	if isFileViewed(ref.Path) {
+		node.SetText(ICON_VIEWED + ref.Label)
+	} else {
+		node.SetText(ref.Label)
+	}
+}
+
 // OpenFileSpecificDiff opens the diff of the selected file
 func OpenFileSpecificDiff(node *tview.TreeNode, fullScreen bool) {
 	ref := node.GetReference()
@@ -162,24 +198,27 @@ func OpenFileSpecificDiff(node *tview.TreeNode, fullScreen bool) {
 
 			// Use the spinner utility for asynchronous fetch
 			support.ShowLoadingSpinner(state.GlobalState.DiffDetails, func() (interface{}, error) {
-				return bitbucket.FetchBitbucketDiffContent(state.GlobalState.SelectedPR.ID, nodeRef.Path)
+				return fetchDiffContent(state.GlobalState.SelectedPR, nodeRef.Path)
 			}, func(result interface{}, err error) {
 				if err != nil {
 					UpdateDiffDetailsView(err.Error())
 				} else {
 					result, ok := result.(string)
 					if !ok {
-						UpdateActivityView("[red]Failed to cast diff details[-]")
+						UpdateActivityView("[danger]Failed to cast diff details[-]")
 						return
 					}
 					// Retrieve inline comments for the file and add comment markers to lines
 					comments := getInlineComments(*state.GlobalState.SelectedPR, nodeRef.Path)
 
-					UpdateDiffDetailsView(util.GenerateColorizedDiffView(result, comments))
+					ShowFileDiff(nodeRef.Path, result, comments)
 				}
 			})
 
-			if fullScreen {
+			if fullScreen && IsCompact() {
+				// Compact mode moves on to the diff pane
+				state.GlobalState.App.SetFocus(state.GlobalState.DiffDetails)
+			} else if fullScreen {
 				// Set the DiffDetails view as the active root
 				state.GlobalState.App.SetRoot(state.GlobalState.DiffDetails, true)
 			}
@@ -192,7 +231,10 @@ func getInlineComments(pr types.PR, file string) []types.Comment {
 	resultCh := make(chan []types.Comment)
 
 	go func() {
-		comments := bitbucket.FetchBitbucketComments(pr.ID)
+		comments, err := provider.ForPR(&pr).FetchComments(pr.ID)
+		if err != nil {
+			log.Printf("[DIFFSTAT] %v", err)
+		}
 		var inlineComments []types.Comment
 
 		for _, comment := range comments {
@@ -212,7 +254,7 @@ func getCommentSymbolAsync(path string, callback func(string)) {
 	go func() {
 		comments := getInlineComments(*state.GlobalState.SelectedPR, path)
 		if len(comments) > 0 {
-			callback("[yellow]" + ICON_COMMENT + "[-]") // Show the comment icon
+			callback("[warning]" + ICON_COMMENT + "[-]") // Show the comment icon
 		} else {
 			callback("") // No comment icon
 		}
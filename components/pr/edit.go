@@ -0,0 +1,292 @@
+package pr
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"simple-git-terminal/apis/provider"
+	"simple-git-terminal/state"
+	"simple-git-terminal/support"
+	"simple-git-terminal/theme"
+	"simple-git-terminal/types"
+	"simple-git-terminal/util"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+const (
+	EDIT_PR_TITLE         = "Edit PR [muted]tab next field | ctrl-e description in $EDITOR | ctrl-s save | esc cancel[-]"
+	EDIT_PREVIEW_DELAY    = 200 * time.Millisecond
+	EDIT_MAX_SUGGESTIONS  = 10
+	EDIT_LABEL_TITLE      = "Title        "
+	EDIT_LABEL_DEST       = "Destination  "
+	EDIT_LABEL_DRAFT      = "Draft        "
+	EDIT_DESCRIPTION_HINT = "Description"
+)
+
+// prEditor is the form editing the title, description, destination and draft flag of a PR
+type prEditor struct {
+	pr           *types.PR
+	branches     []string
+	title        *tview.InputField
+	destination  *tview.InputField
+	draft        *tview.Checkbox
+	description  *tview.TextArea
+	preview      *tview.TextView
+	previewTimer *time.Timer
+}
+
+// ShowPREditor loads the selected PR and the repository's branches, then opens the PR editor
+func ShowPREditor() {
+	pr := state.GlobalState.SelectedPR
+	if pr == nil {
+		return
+	}
+	app := state.GlobalState.App
+	background := state.GlobalState.MainFlexWrapper
+
+	modal := support.ShowModal(app, background, "Loading PR ...", nil, nil)
+	go func() {
+		api := provider.ForPR(pr)
+		current, err := api.FetchPR(pr.ID)
+		branches, branchErr := api.FetchBranchNames()
+		if branchErr != nil {
+			// Only the destination suggestions are missing
+			log.Printf("[EDIT] %v", branchErr)
+		}
+
+		app.QueueUpdateDraw(func() {
+			if err != nil {
+				modal.SetText(fmt.Sprintf("[danger]Could not load the PR[-]\n%v", err)).
+					ClearButtons().
+					AddButtons([]string{BUTTON_OK})
+				support.SetModalDoneFunc(app, background, modal, nil)
+				return
+			}
+			support.CloseModal(app, background)
+			editor := &prEditor{pr: current, branches: branches}
+			editor.show()
+		})
+	}()
+}
+
+func (e *prEditor) show() {
+	app := state.GlobalState.App
+	background := state.GlobalState.MainFlexWrapper
+	description, _ := e.pr.Description.(string)
+
+	e.title = newEditField(EDIT_LABEL_TITLE, e.pr.Title)
+	e.destination = newEditField(EDIT_LABEL_DEST, e.pr.Destination.Branch.Name)
+	e.destination.SetAutocompleteStyles(theme.Current.Background,
+		tcell.StyleDefault.Foreground(theme.Current.Text).Background(theme.Current.Background),
+		tcell.StyleDefault.Foreground(theme.Current.AccentText).Background(theme.Current.Accent))
+	e.destination.SetAutocompleteFunc(e.suggestBranches)
+
+	e.draft = support.CreateCheckBoxComponent(EDIT_LABEL_DRAFT, nil)
+	e.draft.SetLabelColor(theme.Current.Key)
+	e.draft.SetChecked(e.pr.Draft)
+
+	e.description = support.CreateTextAreaComponent(EDIT_DESCRIPTION_HINT, "Markdown description")
+	e.description.SetText(description, false)
+	e.description.SetChangedFunc(e.schedulePreview)
+
+	e.preview = support.CreateTextviewComponent("Preview", true)
+	e.preview.SetWrap(true).SetWordWrap(true)
+	e.renderPreview(description)
+
+	fields := []tview.Primitive{e.title, e.destination, e.draft, e.description}
+	focused := 0
+
+	layout := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(e.title, 1, 0, true).
+		AddItem(e.destination, 1, 0, false).
+		AddItem(e.draft, 1, 0, false).
+		AddItem(tview.NewFlex().
+			AddItem(e.description, 0, 1, false).
+			AddItem(e.preview, 0, 1, false), 0, 1, false)
+	layout.SetBackgroundColor(tcell.ColorDefault)
+	layout.SetBorder(true).
+		SetTitle(EDIT_PR_TITLE).
+		SetTitleAlign(tview.AlignLeft).
+		SetBorderColor(theme.Current.Accent)
+
+	// Esc first closes the branch suggestions, the destination field cancels once they are gone
+	e.destination.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEsc {
+			support.CloseModal(app, background)
+		}
+	})
+
+	layout.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyTab, tcell.KeyBacktab:
+			step := 1
+			if event.Key() == tcell.KeyBacktab {
+				step = len(fields) - 1
+			}
+			focused = (focused + step) % len(fields)
+			app.SetFocus(fields[focused])
+			return nil
+		case tcell.KeyEsc:
+			if e.destination.HasFocus() {
+				return event
+			}
+			support.CloseModal(app, background)
+			return nil
+		case tcell.KeyCtrlS:
+			e.save()
+			return nil
+		case tcell.KeyCtrlE:
+			e.editDescriptionExternally()
+			return nil
+		}
+		return event
+	})
+
+	support.ShowOverlay(app, background, layout, 140, 32)
+}
+
+func newEditField(label string, text string) *tview.InputField {
+	field := tview.NewInputField().
+		SetLabel(label).
+		SetText(text).
+		SetLabelColor(theme.Current.Key).
+		SetFieldStyle(tcell.StyleDefault.Foreground(tcell.ColorDefault).Background(tcell.ColorDefault))
+	field.SetBackgroundColor(tcell.ColorDefault)
+	return field
+}
+
+// suggestBranches lists the branches containing the typed text, leaving out the source branch
+func (e *prEditor) suggestBranches(text string) []string {
+	text = strings.ToLower(strings.TrimSpace(text))
+	if text == "" || strings.EqualFold(text, e.pr.Destination.Branch.Name) {
+		return nil
+	}
+	var entries []string
+	for _, branch := range e.branches {
+		if branch != e.pr.Source.Branch.Name && strings.Contains(strings.ToLower(branch), text) {
+			entries = append(entries, branch)
+		}
+		if len(entries) == EDIT_MAX_SUGGESTIONS {
+			break
+		}
+	}
+	return entries
+}
+
+// schedulePreview renders the description once typing pauses, glamour is too slow for every key stroke
+func (e *prEditor) schedulePreview() {
+	if e.previewTimer != nil {
+		e.previewTimer.Stop()
+	}
+	text := e.description.GetText()
+	e.previewTimer = time.AfterFunc(EDIT_PREVIEW_DELAY, func() {
+		state.GlobalState.App.QueueUpdateDraw(func() {
+			e.renderPreview(text)
+		})
+	})
+}
+
+func (e *prEditor) renderPreview(text string) {
+	if strings.TrimSpace(text) == "" {
+		e.preview.SetText("[muted]No description[-]")
+		return
+	}
+	e.preview.SetText(util.RenderMarkdown(text))
+}
+
+// editDescriptionExternally hands the description to $EDITOR through a temporary file
+func (e *prEditor) editDescriptionExternally() {
+	file, err := os.CreateTemp("", fmt.Sprintf("bbpr-pr-%d-*.md", e.pr.ID))
+	if err != nil {
+		log.Printf("[EDIT] Could not create a temporary file: %v", err)
+		return
+	}
+	path := file.Name()
+	defer os.Remove(path)
+
+	_, err = file.WriteString(e.description.GetText())
+	file.Close()
+	if err != nil {
+		log.Printf("[EDIT] Could not write %s: %v", path, err)
+		return
+	}
+
+	state.GlobalState.App.Suspend(func() {
+		err = util.EditorCommand(path, 0).Run()
+	})
+	if err != nil {
+		log.Printf("[EDIT] The editor failed: %v", err)
+		return
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		log.Printf("[EDIT] Could not read %s: %v", path, err)
+		return
+	}
+	text := strings.TrimRight(string(content), "\n")
+	e.description.SetText(text, false)
+	e.renderPreview(text)
+}
+
+// save puts the changes to the PR, then reloads it so the details and activities show the edit
+func (e *prEditor) save() {
+	app := state.GlobalState.App
+	background := state.GlobalState.MainFlexWrapper
+
+	update := types.PRUpdate{
+		Title:       strings.TrimSpace(e.title.GetText()),
+		Description: e.description.GetText(),
+		Destination: strings.TrimSpace(e.destination.GetText()),
+		Draft:       e.draft.IsChecked(),
+	}
+	if update.Title == "" || update.Destination == "" {
+		e.title.SetLabelColor(theme.Current.Danger)
+		e.destination.SetLabelColor(theme.Current.Danger)
+		return
+	}
+
+	support.CloseModal(app, background)
+	modal := support.ShowModal(app, background, "Saving PR ...", nil, nil)
+
+	go func() {
+		updated, err := provider.ForPR(e.pr).UpdatePR(e.pr.ID, update)
+		text := fmt.Sprintf("[success]Saved #%d[-]", e.pr.ID)
+		if err != nil {
+			log.Printf("[EDIT] %v", err)
+			text = fmt.Sprintf("[danger]Saving the PR failed[-]\n%v", err)
+		}
+
+		app.QueueUpdateDraw(func() {
+			modal.SetText(text).
+				ClearButtons().
+				AddButtons([]string{BUTTON_OK})
+			support.SetModalDoneFunc(app, background, modal, func(label string) {
+				if updated != nil {
+					applyPRUpdate(e.pr, updated)
+				}
+			})
+		})
+	}()
+}
+
+// applyPRUpdate copies the edited fields into the PR list and reloads the selected PR
+func applyPRUpdate(original *types.PR, updated *types.PR) {
+	prs := *state.GlobalState.FilteredPRs
+	for i := range prs {
+		if prs[i].ID == original.ID && prs[i].Destination.Repository.FullName == original.Destination.Repository.FullName {
+			prs[i].Title = updated.Title
+			prs[i].Description = updated.Description
+			prs[i].Destination.Branch = updated.Destination.Branch
+			prs[i].Draft = updated.Draft
+		}
+	}
+	rerenderPRList()
+
+	row, _ := state.GlobalState.PrList.GetSelection()
+	HandleOnPrSelect(prs, prRowToIndex(row))
+}
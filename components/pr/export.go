@@ -0,0 +1,114 @@
+package pr
+
+import (
+	"fmt"
+	"path/filepath"
+	"simple-git-terminal/apis/bitbucket"
+	"simple-git-terminal/apis/provider"
+	"simple-git-terminal/state"
+	"simple-git-terminal/support"
+	"simple-git-terminal/types"
+	"simple-git-terminal/util"
+	"strings"
+)
+
+// ShowPRExports offers the selected PR's summary and, while a file diff is shown, the selected lines and the
+// whole file as patches to copy or save
+func ShowPRExports() {
+	pr := state.GlobalState.SelectedPR
+	if pr == nil {
+		return
+	}
+
+	exports := []support.Export{{
+		Label:    "PR summary",
+		FileName: fmt.Sprintf("pr-%d.md", pr.ID),
+		Text: func() (string, error) {
+			return prSummaryMarkdown(pr), nil
+		},
+	}}
+
+	if diff := currentFileDiff; diff != nil {
+		name := fmt.Sprintf("pr-%d-%s", pr.ID, filepath.Base(diff.path))
+		if from, to, marked, ok := selectedDiffLines(); ok {
+			label := "Hunk at the cursor as patch"
+			if marked {
+				label = "Selected diff lines as patch"
+			}
+			exports = append(exports, support.Export{
+				Label:    label,
+				FileName: name + ".patch",
+				Text: func() (string, error) {
+					return util.BuildDiffText(diff.header, util.SliceHunks(diff.hunks, from, to)), nil
+				},
+			})
+		}
+		exports = append(exports, support.Export{
+			Label:    "File diff as patch",
+			FileName: name + ".patch",
+			Text: func() (string, error) {
+				return util.BuildDiffText(diff.header, diff.hunks), nil
+			},
+		})
+	}
+
+	support.ShowExportMenu(state.GlobalState.App, state.GlobalState.MainFlexWrapper, exports)
+}
+
+// prSummaryMarkdown describes the PR for pasting into chats and tickets: title, branches, reviewers and state,
+// build status and unresolved threads
+func prSummaryMarkdown(pr *types.PR) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "**[#%d %s](%s)**\n\n", pr.ID, pr.Title, pr.Links.HTML.Href)
+	fmt.Fprintf(&sb, "- Branches: `%s` → `%s`\n", pr.Source.Branch.Name, pr.Destination.Branch.Name)
+	fmt.Fprintf(&sb, "- State: %s\n", pr.State)
+	fmt.Fprintf(&sb, "- Author: %s\n", pr.Author.DisplayName)
+
+	var reviewers []string
+	for _, participant := range pr.Participants {
+		if participant.Role != "REVIEWER" || participant.User == nil {
+			continue
+		}
+		review := "pending"
+		switch {
+		case participant.State == types.StateRequestedChanges:
+			review = "changes requested"
+		case participant.Approved || participant.State == types.StateApproved:
+			review = "approved"
+		}
+		reviewers = append(reviewers, fmt.Sprintf("%s (%s)", participant.User.DisplayName, review))
+	}
+	if len(reviewers) == 0 {
+		reviewers = append(reviewers, "none")
+	}
+	fmt.Fprintf(&sb, "- Reviewers: %s\n", strings.Join(reviewers, ", "))
+
+	if provider.IsCloud() {
+		build := "unknown"
+		if statuses, err := bitbucket.FetchPRStatuses(state.RepoOfPR(pr), pr.ID); err == nil {
+			build = strings.ToLower(combineBuildStates(statuses))
+			for _, status := range statuses {
+				build += fmt.Sprintf(", %s: %s", status.Name, strings.ToLower(status.State))
+			}
+		}
+		fmt.Fprintf(&sb, "- Build: %s\n", build)
+	}
+
+	comments, err := provider.ForPR(pr).FetchComments(pr.ID)
+	if err != nil {
+		fmt.Fprintf(&sb, "- Unresolved threads: unknown (%v)\n", err)
+		return sb.String()
+	}
+	var unresolved []commentThread
+	for _, thread := range buildThreads(comments) {
+		if !thread.isResolved() && !thread.root.Deleted {
+			unresolved = append(unresolved, thread)
+		}
+	}
+	fmt.Fprintf(&sb, "- Unresolved threads: %d\n", len(unresolved))
+	for _, thread := range unresolved {
+		text := strings.SplitN(strings.TrimSpace(thread.root.Content.Raw), "\n", 2)[0]
+		fmt.Fprintf(&sb, "  - `%s` %s: %s\n", thread.location(), thread.root.User.DisplayName, util.EllipsizeText(text, 100))
+	}
+	return sb.String()
+}
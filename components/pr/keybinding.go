@@ -4,15 +4,12 @@ import (
 	"log"
 	"simple-git-terminal/state"
 	"simple-git-terminal/support"
+	"simple-git-terminal/theme"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 )
 
-const (
-	VIEW_ACTIVE_BORDER_COLOR = tcell.ColorOrange
-)
-
 func SetupKeyBindings(callback func()) {
 	focusOrder := []tview.Primitive{
 		state.GlobalState.PrListFlex, state.GlobalState.PrDetails, state.GlobalState.ActivityView,
@@ -20,9 +17,20 @@ func SetupKeyBindings(callback func()) {
 	}
 	// Define focus order
 	currentFocusIndex := 0
-	support.UpdateFocusBorders(focusOrder, currentFocusIndex, VIEW_ACTIVE_BORDER_COLOR)
+	support.UpdateFocusBorders(focusOrder, currentFocusIndex, theme.Current.Accent)
+
+	// handled updates the focus borders and keeps the key from reaching the focused pane
+	handled := func() *tcell.EventKey {
+		support.UpdateFocusBorders(focusOrder, currentFocusIndex, theme.Current.Accent)
+		return nil
+	}
 
 	state.GlobalState.App.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		// Modals handle their own keys
+		if state.IsModalOpen {
+			return event
+		}
+
 		// If in search mode, only allow Esc or Enter keys
 		if state.IsSearchMode {
 			switch event.Key() {
@@ -31,11 +39,14 @@ func SetupKeyBindings(callback func()) {
 				state.SetIsSearchMode(false)
 				state.GlobalState.App.SetFocus(state.GlobalState.PrList) // Focus back to PrList or another view
 				log.Printf("Esc pressed escaping now......")
-				support.UpdateFocusBorders(focusOrder, currentFocusIndex, VIEW_ACTIVE_BORDER_COLOR)
+				support.UpdateFocusBorders(focusOrder, currentFocusIndex, theme.Current.Accent)
 			case tcell.KeyEnter:
-				currentFocusIndex = 0
-				state.SetSearchTerm(state.GlobalState.PrListSearchBar.GetText())
-				ShowSpinnerFetchPRsByQueryAndUpdatePrList()
+				if SubmitSearch() {
+					currentFocusIndex = 0
+				}
+			case tcell.KeyUp, tcell.KeyDown:
+				RecallSearchHistory(event.Key() == tcell.KeyUp)
+				return nil
 			default:
 				return event // Ignore other keys in search mode
 			}
@@ -43,16 +54,28 @@ func SetupKeyBindings(callback func()) {
 			// Handle keybindings when not in search mode
 			switch event.Key() {
 			case tcell.KeyTAB:
-				// Cycle focus between views
-				currentFocusIndex = (currentFocusIndex + 1) % len(focusOrder)
-				if currentFocusIndex >= len(focusOrder) {
-					currentFocusIndex = 0 // If we go out of bounds, set to the first element
+				// Cycle focus between views, skipping hidden panes
+				for range focusOrder {
+					currentFocusIndex = (currentFocusIndex + 1) % len(focusOrder)
+					if IsPaneVisible(focusOrder[currentFocusIndex]) {
+						break
+					}
 				}
 				state.GlobalState.App.SetFocus(focusOrder[currentFocusIndex])
 
 			case tcell.KeyCtrlC:
 				state.GlobalState.App.Stop()
 
+			case tcell.KeyBackspace, tcell.KeyBackspace2:
+				// Compact mode goes up the breadcrumbs
+				if CompactBack() {
+					for i, view := range focusOrder {
+						if view.HasFocus() {
+							currentFocusIndex = i
+						}
+					}
+				}
+
 			case tcell.KeyRune:
 				switch event.Rune() {
 				case 's':
@@ -62,13 +85,14 @@ func SetupKeyBindings(callback func()) {
 
 					state.GlobalState.App.SetFocus(state.GlobalState.PrListSearchBar)
 					// state.GlobalState.PrListSearchBar.SetText("")
-					support.UpdateFocusBorders(focusOrder, currentFocusIndex, VIEW_ACTIVE_BORDER_COLOR) // TODO: This is repeated here as we need to return nil from event rune otherwise it adds pressed key rune to textarea
+					support.UpdateFocusBorders(focusOrder, currentFocusIndex, theme.Current.Accent) // TODO: This is repeated here as we need to return nil from event rune otherwise it adds pressed key rune to textarea
 					return nil
 
 				case 't', 'T':
 					currentFocusIndex = len(focusOrder) - 3
 					switch event.Rune() {
 					case 't':
+						RevealPane(state.GlobalState.DiffStatView)
 						state.GlobalState.App.SetFocus(state.GlobalState.DiffStatView)
 					case 'T':
 						state.GlobalState.App.SetRoot(state.GlobalState.DiffStatView, true)
@@ -78,6 +102,7 @@ func SetupKeyBindings(callback func()) {
 					currentFocusIndex = len(focusOrder) - 2
 					switch event.Rune() {
 					case 'c':
+						RevealPane(state.GlobalState.DiffDetails)
 						state.GlobalState.App.SetFocus(state.GlobalState.DiffDetails)
 					case 'C':
 						state.GlobalState.App.SetRoot(state.GlobalState.DiffDetails, true)
@@ -87,6 +112,7 @@ func SetupKeyBindings(callback func()) {
 					currentFocusIndex = len(focusOrder) - 4
 					switch event.Rune() {
 					case 'a':
+						RevealPane(state.GlobalState.ActivityView)
 						state.GlobalState.App.SetFocus(state.GlobalState.ActivityView)
 					case 'A':
 						state.GlobalState.App.SetRoot(state.GlobalState.ActivityView, true)
@@ -105,16 +131,119 @@ func SetupKeyBindings(callback func()) {
 					currentFocusIndex = len(focusOrder) - 5
 					switch event.Rune() {
 					case 'd':
+						RevealPane(state.GlobalState.PrDetails)
 						state.GlobalState.App.SetFocus(state.GlobalState.PrDetails)
 					case 'D':
 						state.GlobalState.App.SetRoot(state.GlobalState.PrDetails, true)
 					}
 
+				case 'b':
+					CheckoutSelectedPR()
+					return nil
+
+				case 'B':
+					currentFocusIndex = 0
+					SelectPRForCurrentBranch()
+					return handled()
+
+				case 'R':
+					currentFocusIndex = len(focusOrder) - 2
+					ShowRevisions()
+					return handled()
+
+				case 'x':
+					currentFocusIndex = len(focusOrder) - 4
+					ShowConversation()
+					return handled()
+
+				case '!':
+					ShowNotifications()
+					return nil
+
+				case 'w':
+					ShowPendingReview()
+					return nil
+
+				case 'N':
+					DraftGeneralComment()
+					return nil
+
+				case '1', '2', '3', '4', '5', '6', '7', '8', '9':
+					ApplySavedView(int(event.Rune() - '1'))
+					return nil
+
+				case 'S':
+					SaveCurrentAsView()
+					return nil
+
+				case 'O':
+					ShowColumnSettings()
+					return nil
+
+				case '+':
+					ResizeFocusedPane(1)
+					return nil
+
+				case '-':
+					ResizeFocusedPane(-1)
+					return nil
+
+				case '>':
+					ResizeFocusedColumn(1)
+					return nil
+
+				case '<':
+					ResizeFocusedColumn(-1)
+					return nil
+
+				case 'z':
+					HideFocusedPane()
+					if state.GlobalState.PrList.HasFocus() {
+						currentFocusIndex = 0
+					}
+					return handled()
+
+				case 'Z':
+					ShowAllPanes()
+					return nil
+
+				case 'L':
+					CycleLayoutPreset()
+					if state.GlobalState.PrList.HasFocus() {
+						currentFocusIndex = 0
+					}
+					return handled()
+
+				case 'W':
+					OpenFocusedInBrowser()
+					return nil
+
+				case 'Y':
+					CopyFocusedURL()
+					return nil
+
+				case 'E':
+					OpenCurrentFileInEditor()
+					return nil
+
+				case 'y':
+					ShowPRExports()
+					return nil
+
+				case 'V':
+					ShowReviewerEditor()
+					return nil
+
+				case 'e':
+					ShowPREditor()
+					return nil
+
 				case 'q':
 					currentFocusIndex = 0
 					state.GlobalState.App.SetRoot(state.GlobalState.MainFlexWrapper, true)
+					return handled()
 
-				case 'm', 'o', 'r', 'i', 'I':
+				case 'm', 'o', 'r', 'i', 'I', 'U':
 					// Toggle PR filters
 					switch event.Rune() {
 					case 'm':
@@ -127,12 +256,14 @@ func SetupKeyBindings(callback func()) {
 						UpdatePRListWithFilter("iamreviewer", !state.PRStatusFilter.IAmReviewer)
 					case 'I':
 						UpdatePRListWithFilter("iamauthor", !state.PRStatusFilter.IAmAuthor)
+					case 'U':
+						UpdatePRListWithFilter("myopentasks", !state.PRStatusFilter.MyOpenTasks)
 					}
 					callback()
 				}
 			}
 			// Update focus borders after focus change
-			support.UpdateFocusBorders(focusOrder, currentFocusIndex, VIEW_ACTIVE_BORDER_COLOR)
+			support.UpdateFocusBorders(focusOrder, currentFocusIndex, theme.Current.Accent)
 		}
 
 		return event
@@ -0,0 +1,243 @@
+package pr
+
+import (
+	"log"
+	"simple-git-terminal/state"
+	"simple-git-terminal/storage"
+	"slices"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// Layout presets, cycled with L
+const (
+	LAYOUT_DEFAULT = "default"
+	LAYOUT_REVIEW  = "review" // Wide diff
+	LAYOUT_TRIAGE  = "triage" // Wide list, no diff
+
+	LAYOUT_MAX_WEIGHT = 40
+)
+
+// Panes of the PR layout. The middle and right columns hold the other panes.
+const (
+	PANE_LEFT        = "left"
+	PANE_MIDDLE      = "middle"
+	PANE_RIGHT       = "right"
+	PANE_HEADER      = "header"
+	PANE_DESCRIPTION = "description"
+	PANE_ACTIVITIES  = "activities"
+	PANE_TREE        = "tree"
+	PANE_DIFF        = "diff"
+)
+
+var layoutPresets = []string{LAYOUT_DEFAULT, LAYOUT_REVIEW, LAYOUT_TRIAGE}
+
+var presetWeights = map[string]map[string]int{
+	LAYOUT_DEFAULT: {PANE_LEFT: 1, PANE_MIDDLE: 1, PANE_RIGHT: 2, PANE_HEADER: 2, PANE_DESCRIPTION: 4, PANE_ACTIVITIES: 14, PANE_TREE: 1, PANE_DIFF: 1},
+	LAYOUT_REVIEW:  {PANE_LEFT: 1, PANE_MIDDLE: 1, PANE_RIGHT: 5, PANE_HEADER: 2, PANE_DESCRIPTION: 4, PANE_ACTIVITIES: 14, PANE_TREE: 1, PANE_DIFF: 4},
+	LAYOUT_TRIAGE:  {PANE_LEFT: 3, PANE_MIDDLE: 2, PANE_RIGHT: 2, PANE_HEADER: 2, PANE_DESCRIPTION: 6, PANE_ACTIVITIES: 12, PANE_TREE: 1, PANE_DIFF: 1},
+}
+
+var presetHidden = map[string][]string{
+	LAYOUT_TRIAGE: {PANE_TREE, PANE_DIFF},
+}
+
+// layoutChildren lists the panes of each flex in display order, "" being the row of columns
+var layoutChildren = map[string][]string{
+	"":          {PANE_LEFT, PANE_MIDDLE, PANE_RIGHT},
+	PANE_MIDDLE: {PANE_HEADER, PANE_DESCRIPTION, PANE_ACTIVITIES},
+	PANE_RIGHT:  {PANE_TREE, PANE_DIFF},
+}
+
+// mainLayout arranges the panes of PR mode by the weights and hidden panes of the saved layout
+type mainLayout struct {
+	flexes  map[string]*tview.Flex     // By parent pane, "" for the root
+	panes   map[string]tview.Primitive // By pane
+	current *storage.Layout
+}
+
+var layout *mainLayout
+
+// NewMainLayout arranges the PR mode panes in three columns and returns the root flex.
+// The layout saved by the last session is restored.
+func NewMainLayout(left, header, description, activities, tree, diff tview.Primitive) *tview.Flex {
+	root := tview.NewFlex()
+	middle := tview.NewFlex().SetDirection(tview.FlexRow)
+	right := tview.NewFlex()
+	for _, flex := range []*tview.Flex{root, middle, right} {
+		flex.SetBackgroundColor(tcell.ColorDefault)
+	}
+
+	layout = &mainLayout{
+		flexes: map[string]*tview.Flex{"": root, PANE_MIDDLE: middle, PANE_RIGHT: right},
+		panes: map[string]tview.Primitive{
+			PANE_LEFT: left, PANE_MIDDLE: middle, PANE_RIGHT: right,
+			PANE_HEADER: header, PANE_DESCRIPTION: description, PANE_ACTIVITIES: activities,
+			PANE_TREE: tree, PANE_DIFF: diff,
+		},
+		current: storage.LoadLayout(),
+	}
+	if layout.current == nil || presetWeights[layout.current.Preset] == nil {
+		layout.current = presetLayout(LAYOUT_DEFAULT)
+	}
+	if layout.current.Weights == nil {
+		layout.current.Weights = make(map[string]int)
+	}
+	// Panes added after the layout was saved start from the preset
+	for pane, weight := range presetWeights[layout.current.Preset] {
+		if layout.current.Weights[pane] < 1 {
+			layout.current.Weights[pane] = weight
+		}
+	}
+
+	layout.apply()
+	return root
+}
+
+func presetLayout(preset string) *storage.Layout {
+	weights := make(map[string]int)
+	for pane, weight := range presetWeights[preset] {
+		weights[pane] = weight
+	}
+	return &storage.Layout{Preset: preset, Weights: weights, Hidden: slices.Clone(presetHidden[preset])}
+}
+
+// apply rebuilds the flexes, inner columns first
+func (l *mainLayout) apply() {
+	for _, parent := range []string{PANE_MIDDLE, PANE_RIGHT, ""} {
+		flex := l.flexes[parent]
+		flex.Clear()
+		for _, pane := range layoutChildren[parent] {
+			if l.visible(pane) {
+				flex.AddItem(l.panes[pane], 0, l.current.Weights[pane], pane == PANE_LEFT)
+			}
+		}
+	}
+}
+
+// visible reports whether a pane is shown. A column is hidden with all its panes, the header aside.
+func (l *mainLayout) visible(pane string) bool {
+	if slices.Contains(l.current.Hidden, pane) {
+		return false
+	}
+	children, isColumn := layoutChildren[pane]
+	if !isColumn {
+		return true
+	}
+	for _, child := range children {
+		if child != PANE_HEADER && l.visible(child) {
+			return true
+		}
+	}
+	return false
+}
+
+// paneOf returns the innermost pane holding the primitive, or the focus when p is nil
+func (l *mainLayout) paneOf(p tview.Primitive) string {
+	for _, pane := range []string{PANE_DESCRIPTION, PANE_ACTIVITIES, PANE_TREE, PANE_DIFF, PANE_LEFT} {
+		if (p == nil && l.panes[pane].HasFocus()) || (p != nil && l.panes[pane] == p) {
+			return pane
+		}
+	}
+	return ""
+}
+
+func (l *mainLayout) column(pane string) string {
+	for parent, children := range layoutChildren {
+		if parent != "" && slices.Contains(children, pane) {
+			return parent
+		}
+	}
+	return pane
+}
+
+func (l *mainLayout) resize(pane string, delta int) {
+	if pane == "" {
+		return
+	}
+	l.current.Weights[pane] = min(max(l.current.Weights[pane]+delta, 1), LAYOUT_MAX_WEIGHT)
+	l.save()
+}
+
+func (l *mainLayout) save() {
+	l.apply()
+	if err := storage.SaveLayout(l.current); err != nil {
+		log.Printf("[LAYOUT] Failed to save the layout: %v", err)
+	}
+}
+
+// ResizeFocusedPane grows or shrinks the focused pane inside its column, or the column
+// when the pane is one on its own
+func ResizeFocusedPane(delta int) {
+	if layout != nil {
+		layout.resize(layout.paneOf(nil), delta)
+	}
+}
+
+// ResizeFocusedColumn widens or narrows the column of the focused pane
+func ResizeFocusedColumn(delta int) {
+	if layout != nil {
+		if pane := layout.paneOf(nil); pane != "" {
+			layout.resize(layout.column(pane), delta)
+		}
+	}
+}
+
+// HideFocusedPane collapses the focused pane out of the layout and focuses the PR list.
+// The PR list itself always stays.
+func HideFocusedPane() {
+	if layout == nil {
+		return
+	}
+	pane := layout.paneOf(nil)
+	if pane == "" || pane == PANE_LEFT {
+		return
+	}
+	layout.current.Hidden = append(layout.current.Hidden, pane)
+	layout.save()
+	state.GlobalState.App.SetFocus(state.GlobalState.PrList)
+}
+
+// ShowAllPanes brings back every hidden pane
+func ShowAllPanes() {
+	if layout != nil {
+		layout.current.Hidden = nil
+		layout.save()
+	}
+}
+
+// RevealPane shows the pane of p when it is hidden, so focusing it by key brings it back
+func RevealPane(p tview.Primitive) {
+	if layout == nil {
+		return
+	}
+	pane := layout.paneOf(p)
+	if i := slices.Index(layout.current.Hidden, pane); i >= 0 {
+		layout.current.Hidden = slices.Delete(layout.current.Hidden, i, i+1)
+		layout.save()
+	}
+}
+
+// IsPaneVisible reports whether p is part of the layout. Primitives outside of it count as visible.
+func IsPaneVisible(p tview.Primitive) bool {
+	if layout == nil {
+		return true
+	}
+	pane := layout.paneOf(p)
+	return pane == "" || (layout.visible(pane) && layout.visible(layout.column(pane)))
+}
+
+// CycleLayoutPreset switches to the next preset, dropping any resizing
+func CycleLayoutPreset() {
+	if layout == nil {
+		return
+	}
+	next := layoutPresets[(slices.Index(layoutPresets, layout.current.Preset)+1)%len(layoutPresets)]
+	layout.current = presetLayout(next)
+	layout.save()
+	log.Printf("[LAYOUT] Switched to the %s layout", next)
+	if focused := layout.paneOf(nil); focused != "" && !IsPaneVisible(layout.panes[focused]) {
+		state.GlobalState.App.SetFocus(state.GlobalState.PrList)
+	}
+}
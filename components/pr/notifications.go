@@ -0,0 +1,111 @@
+package pr
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"simple-git-terminal/apis/provider"
+	"simple-git-terminal/notifications"
+	"simple-git-terminal/state"
+	"simple-git-terminal/support"
+	"simple-git-terminal/theme"
+	"simple-git-terminal/util"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+const (
+	PR_LIST_TITLE       = "Pull Requests   [key]p|P"
+	NOTIFICATIONS_TITLE = "Notifications [key]![-] [muted]enter open PR | esc close[-]"
+	ICON_BELL           = " "
+)
+
+// StartNotifications runs the notification poller for the lifetime of the app and keeps the badge up to date
+func StartNotifications() {
+	if state.DashboardMode {
+		// The poller follows a single repository, while the dashboard spans several
+		log.Printf("[NOTIFY] Notifications are off in dashboard mode")
+		return
+	}
+	if !provider.IsCloud() {
+		log.Printf("[NOTIFY] Notifications are only available on Bitbucket Cloud")
+		return
+	}
+	notifications.Start(context.Background(), state.CurrentRepo(), func() {
+		state.GlobalState.App.QueueUpdateDraw(updateNotificationBadge)
+	})
+}
+
+// updateNotificationBadge shows the unread count in the PR list title
+func updateNotificationBadge() {
+	title := PR_LIST_TITLE
+	if count := notifications.UnreadCount(); count > 0 {
+		title += fmt.Sprintf("[-]  [warning]%s%d[-]", ICON_BELL, count)
+	}
+	state.GlobalState.PrListFlex.SetTitle(title)
+}
+
+// ShowNotifications lists the received events in an overlay and marks them as read
+func ShowNotifications() {
+	events := notifications.Events()
+	notifications.MarkAllRead()
+	updateNotificationBadge()
+
+	table := NewNotificationsTable(events, NOTIFICATIONS_TITLE)
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEsc:
+			support.CloseModal(state.GlobalState.App, state.GlobalState.MainFlexWrapper)
+			return nil
+		case tcell.KeyEnter:
+			row, _ := table.GetSelection()
+			if row >= 0 && row < len(events) && events[row].PR != nil {
+				support.CloseModal(state.GlobalState.App, state.GlobalState.MainFlexWrapper)
+				SelectPRInList(*events[row].PR)
+			}
+			return nil
+		}
+		return event
+	})
+
+	support.ShowOverlay(state.GlobalState.App, state.GlobalState.MainFlexWrapper, table, 110, 20)
+}
+
+// NewNotificationsTable renders events newest first, colored by kind. Pipeline mode shows it too.
+func NewNotificationsTable(events []notifications.Event, title string) *tview.Table {
+	table := tview.NewTable().
+		SetSelectable(true, false)
+	table.SetBackgroundColor(tcell.ColorDefault)
+	table.SetSelectedStyle(tcell.StyleDefault.Foreground(theme.Current.Selected))
+	table.SetBorder(true).
+		SetTitle(title).
+		SetTitleAlign(tview.AlignLeft).
+		SetBorderColor(theme.Current.Accent)
+
+	if len(events) == 0 {
+		table.SetCell(0, 0, util.CellFormat(" Nothing new since bbpr started", theme.Current.Muted))
+	}
+
+	for i, event := range events {
+		color := theme.Current.Info
+		switch event.Kind {
+		case notifications.KindReviewRequest:
+			color = theme.Current.Accent
+		case notifications.KindApproval:
+			color = theme.Current.Approved
+		case notifications.KindPipeline:
+			color = theme.Current.Danger
+			if event.Success {
+				color = theme.Current.Success
+			}
+		}
+
+		table.SetCell(i, 0, util.CellFormat(util.FormatTimeAgo(event.Time.Format(time.RFC3339)), theme.Current.Muted))
+		table.SetCell(i, 1, util.CellFormat(tview.Escape(event.Title), color))
+		table.SetCell(i, 2, util.CellFormat(tview.Escape(strings.SplitN(strings.TrimSpace(event.Detail), "\n", 2)[0]), tcell.ColorDefault).SetExpansion(1))
+	}
+	return table
+}
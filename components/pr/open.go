@@ -0,0 +1,136 @@
+package pr
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"simple-git-terminal/apis/localgit"
+	"simple-git-terminal/apis/provider"
+	"simple-git-terminal/state"
+	"simple-git-terminal/support"
+	"simple-git-terminal/types"
+	"simple-git-terminal/util"
+)
+
+// focusedURL is the web address of what the focused pane shows: the selected comment thread, the selected
+// commit of the revisions, the file of the diff, or else the PR itself
+func focusedURL() string {
+	pr := state.GlobalState.SelectedPR
+	if pr == nil {
+		return ""
+	}
+
+	if view := currentConversation; view != nil && view.table != nil && view.table.HasFocus() {
+		row, _ := view.table.GetSelection()
+		if selected, ok := view.table.GetCell(row, 0).GetReference().(conversationRow); ok && selected.thread != nil && selected.thread.root.Links.HTML.Href != "" {
+			return selected.thread.root.Links.HTML.Href
+		}
+	}
+	if view := currentRevisions; view != nil && view.table != nil && view.table.HasFocus() {
+		row, _ := view.table.GetSelection()
+		if row >= 0 && row < len(view.commits) && view.commits[row].Links.HTML.Href != "" {
+			return view.commits[row].Links.HTML.Href
+		}
+	}
+	if currentFileDiff != nil && (state.GlobalState.DiffDetails.HasFocus() || state.GlobalState.DiffStatView.HasFocus()) {
+		return prFileURL(pr, currentFileDiff.path)
+	}
+	return pr.Links.HTML.Href
+}
+
+// prFileURL links to a file in the diff tab of the PR
+func prFileURL(pr *types.PR, path string) string {
+	if provider.IsCloud() {
+		return pr.Links.HTML.Href + "/diff#chg-" + path
+	}
+	return pr.Links.HTML.Href + "/diff#" + path
+}
+
+// OpenFocusedInBrowser opens the PR, comment, commit or file of the focused pane in the browser
+func OpenFocusedInBrowser() {
+	link := focusedURL()
+	if link == "" {
+		return
+	}
+	log.Printf("[OPEN] Opening %s", link)
+	if err := util.OpenBrowser(link); err != nil {
+		showOpenMessage(fmt.Sprintf("[danger]Could not open the browser[-]\n%v", err))
+	}
+}
+
+// CopyFocusedURL copies the address OpenFocusedInBrowser would open
+func CopyFocusedURL() {
+	link := focusedURL()
+	if link == "" {
+		return
+	}
+	if err := util.CopyToClipboard(link); err != nil {
+		showOpenMessage(fmt.Sprintf("[danger]Could not copy the link[-]\n%v", err))
+		return
+	}
+	showOpenMessage(fmt.Sprintf("Copied to the clipboard\n[link]%s[-]", link))
+}
+
+// OpenCurrentFileInEditor opens the file of the diff pane in $EDITOR, at the selected diff line or else the
+// first commented line. The PR's branch has to be checked out, so the file matches what is reviewed.
+func OpenCurrentFileInEditor() {
+	pr := state.GlobalState.SelectedPR
+	if pr == nil || currentFileDiff == nil {
+		return
+	}
+	if repo := state.RepoOfPR(pr); !state.IsHomeRepo(repo) {
+		showOpenMessage(fmt.Sprintf("[warning]%s[-] is not the repository of the current directory", repo.FullName()))
+		return
+	}
+	checkedOut, err := localgit.IsCheckedOut(pr)
+	if err != nil {
+		showOpenMessage(fmt.Sprintf("[danger]Could not determine current branch[-]\n%v", err))
+		return
+	}
+	if !checkedOut {
+		showOpenMessage(fmt.Sprintf("Check out [warning]%s[-] with [key]b[-] to edit its files", localgit.LocalBranchName(pr)))
+		return
+	}
+
+	path := util.RepoPath(currentFileDiff.path)
+	if _, err := os.Stat(path); err != nil {
+		showOpenMessage(fmt.Sprintf("[warning]%s[-] does not exist in the working tree", currentFileDiff.path))
+		return
+	}
+
+	line := editorLine()
+	log.Printf("[OPEN] Editing %s at line %d", path, line)
+	var runErr error
+	state.GlobalState.App.Suspend(func() {
+		runErr = util.EditorCommand(path, line).Run()
+	})
+	if runErr != nil {
+		showOpenMessage(fmt.Sprintf("[danger]The editor failed[-]\n%v", runErr))
+	}
+}
+
+// editorLine picks the new side line of the selected diff row, else the first commented line, 0 for none
+func editorLine() int {
+	diff := currentFileDiff
+	if diff.table != nil && diff.table.HasFocus() {
+		row, _ := diff.table.GetSelection()
+		if lineIndex, ok := displayLineAtRow(diff.table, row); ok {
+			positions := util.DisplayLinePositions(diff.hunks)
+			if lineIndex < len(positions) && positions[lineIndex].New > 0 {
+				return positions[lineIndex].New
+			}
+		}
+	}
+
+	line := 0
+	for _, comment := range diff.comments {
+		if comment.Inline.To > 0 && (line == 0 || comment.Inline.To < line) {
+			line = comment.Inline.To
+		}
+	}
+	return line
+}
+
+func showOpenMessage(text string) {
+	support.ShowModal(state.GlobalState.App, state.GlobalState.MainFlexWrapper, text, []string{BUTTON_OK}, nil)
+}
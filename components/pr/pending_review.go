@@ -0,0 +1,324 @@
+package pr
+
+import (
+	"fmt"
+	"log"
+	"simple-git-terminal/apis/provider"
+	"simple-git-terminal/state"
+	"simple-git-terminal/support"
+	"simple-git-terminal/theme"
+	"simple-git-terminal/types"
+	"simple-git-terminal/util"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+const (
+	PENDING_REVIEW_TITLE = "Pending Review [key]w[-] [muted]e edit | x delete | P publish | A approve | X request changes | esc close[-]"
+
+	REVIEW_PUBLISH         = "publish"
+	REVIEW_APPROVE         = "approve"
+	REVIEW_REQUEST_CHANGES = "request-changes"
+)
+
+// DraftInlineCommentAtRow opens the editor for a pending comment anchored at the diff line of the given table row
+func DraftInlineCommentAtRow(table *tview.Table, row int) {
+	pr := state.GlobalState.SelectedPR
+	if pr == nil || currentFileDiff == nil {
+		return
+	}
+	lineIndex, ok := displayLineAtRow(table, row)
+	if !ok {
+		return
+	}
+
+	positions := util.DisplayLinePositions(currentFileDiff.hunks)
+	if lineIndex >= len(positions) {
+		return
+	}
+	oldLine, newLine := positions[lineIndex].Old, positions[lineIndex].New
+	if oldLine == 0 && newLine == 0 {
+		return
+	}
+	// Anchor on the new side whenever the line still exists there, like Bitbucket's web UI
+	inline := &types.Inline{Path: currentFileDiff.path, To: newLine}
+	lineLabel := newLine
+	if newLine == 0 {
+		inline = &types.Inline{Path: currentFileDiff.path, From: oldLine}
+		lineLabel = oldLine
+	}
+
+	title := fmt.Sprintf("Draft comment on %s:%d", currentFileDiff.path, lineLabel)
+	support.ShowTextAreaModal(state.GlobalState.App, state.GlobalState.MainFlexWrapper, title, "", func(text string) {
+		createDraftComment(pr, text, inline, func() {
+			refreshCurrentFileDiffComments(pr, row)
+		})
+	})
+}
+
+// DraftGeneralComment opens the editor for a pending comment on the PR itself
+func DraftGeneralComment() {
+	pr := state.GlobalState.SelectedPR
+	if pr == nil {
+		return
+	}
+	title := fmt.Sprintf("Draft comment on #%d", pr.ID)
+	support.ShowTextAreaModal(state.GlobalState.App, state.GlobalState.MainFlexWrapper, title, "", func(text string) {
+		createDraftComment(pr, text, nil, nil)
+	})
+}
+
+func createDraftComment(pr *types.PR, text string, inline *types.Inline, onCreated func()) {
+	if strings.TrimSpace(text) == "" {
+		return
+	}
+	go func() {
+		if _, err := provider.ForPR(pr).CreateComment(pr.ID, text, inline, true); err != nil {
+			log.Printf("[REVIEW] Failed to create draft comment: %v", err)
+			showPRLookupMessage(fmt.Sprintf("[danger]Failed to save draft comment[-]\n%v", err))
+			return
+		}
+		if onCreated != nil {
+			state.GlobalState.App.QueueUpdateDraw(onCreated)
+		}
+	}()
+}
+
+// refreshCurrentFileDiffComments re-renders the open file diff with its comments reloaded, keeping the selection
+func refreshCurrentFileDiffComments(pr *types.PR, row int) {
+	if currentFileDiff == nil {
+		return
+	}
+	path := currentFileDiff.path
+	go func() {
+		comments := getInlineComments(*pr, path)
+		state.GlobalState.App.QueueUpdateDraw(func() {
+			if currentFileDiff == nil || currentFileDiff.path != path {
+				return
+			}
+			currentFileDiff.comments = comments
+			table := renderCurrentFileDiff()
+			table.Select(row, 0)
+			state.GlobalState.App.SetFocus(table)
+		})
+	}()
+}
+
+// pendingComments filters the current user's drafts out of all comments of the PR
+func pendingComments(pr *types.PR) ([]types.Comment, error) {
+	comments, err := provider.ForPR(pr).FetchComments(pr.ID)
+	if err != nil {
+		return nil, err
+	}
+	var pending []types.Comment
+	for _, comment := range comments {
+		if !comment.Pending || comment.Deleted {
+			continue
+		}
+		if state.CurrentUser != nil && state.CurrentUser.UUID != "" && comment.User.UUID != state.CurrentUser.UUID {
+			continue
+		}
+		pending = append(pending, comment)
+	}
+	return pending, nil
+}
+
+// ShowPendingReview lists the draft comments of the selected PR in an overlay, where they can be edited,
+// deleted and finally published together with a review decision
+func ShowPendingReview() {
+	showPendingReview("")
+}
+
+// showPendingReview opens the panel with notice, e.g. a failed edit, shown in its title
+func showPendingReview(notice string) {
+	pr := state.GlobalState.SelectedPR
+	if pr == nil {
+		return
+	}
+
+	table := tview.NewTable().
+		SetSelectable(true, false)
+	table.SetBackgroundColor(tcell.ColorDefault)
+	table.SetSelectedStyle(tcell.StyleDefault.Foreground(theme.Current.Selected))
+	table.SetBorder(true).
+		SetTitle(PENDING_REVIEW_TITLE).
+		SetTitleAlign(tview.AlignLeft).
+		SetBorderColor(theme.Current.Accent)
+	table.SetCell(0, 0, util.CellFormat(" Loading drafts ...", theme.Current.Muted))
+
+	support.ShowOverlay(state.GlobalState.App, state.GlobalState.MainFlexWrapper, table, 110, 20)
+	loadPendingReview(pr, table, notice)
+}
+
+func loadPendingReview(pr *types.PR, table *tview.Table, notice string) {
+	go func() {
+		comments, err := pendingComments(pr)
+		state.GlobalState.App.QueueUpdateDraw(func() {
+			if err != nil {
+				log.Printf("[REVIEW] %v", err)
+				table.Clear()
+				table.SetCell(0, 0, util.CellFormat(fmt.Sprintf(" Failed to load drafts: %s", tview.Escape(err.Error())), theme.Current.Danger))
+				return
+			}
+			renderPendingReview(pr, table, comments)
+			if notice != "" {
+				table.SetTitle(table.GetTitle() + " " + notice)
+			}
+		})
+	}()
+}
+
+func renderPendingReview(pr *types.PR, table *tview.Table, comments []types.Comment) {
+	table.Clear()
+	table.SetTitle(fmt.Sprintf("%s [warning]%d draft(s)[-]", PENDING_REVIEW_TITLE, len(comments)))
+
+	if len(comments) == 0 {
+		table.SetCell(0, 0, util.CellFormat(" No draft comments. Press n on a diff line or N to write one.", theme.Current.Muted))
+	}
+
+	for i, comment := range comments {
+		location := "general"
+		if comment.Inline.Path != "" {
+			location = fmt.Sprintf("%s:%d", comment.Inline.Path, max(comment.Inline.To, comment.Inline.From))
+		}
+		text := strings.SplitN(strings.TrimSpace(comment.Content.Raw), "\n", 2)[0]
+
+		table.SetCell(i, 0, util.CellFormat(tview.Escape(location), theme.Current.Info))
+		table.SetCell(i, 1, util.CellFormat(tview.Escape(text), tcell.ColorDefault).SetExpansion(1))
+	}
+
+	selected := func() (types.Comment, bool) {
+		row, _ := table.GetSelection()
+		if row < 0 || row >= len(comments) {
+			return types.Comment{}, false
+		}
+		return comments[row], true
+	}
+
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEsc {
+			support.CloseModal(state.GlobalState.App, state.GlobalState.MainFlexWrapper)
+			return nil
+		}
+		if event.Key() == tcell.KeyEnter {
+			if comment, ok := selected(); ok {
+				editDraftComment(pr, comment)
+			}
+			return nil
+		}
+		if event.Key() != tcell.KeyRune {
+			return event
+		}
+		switch event.Rune() {
+		case 'e':
+			if comment, ok := selected(); ok {
+				editDraftComment(pr, comment)
+			}
+		case 'x':
+			if comment, ok := selected(); ok {
+				deleteDraftComment(pr, table, comment)
+			}
+		case 'P':
+			submitReview(pr, comments, REVIEW_PUBLISH)
+		case 'A':
+			submitReview(pr, comments, REVIEW_APPROVE)
+		case 'X':
+			submitReview(pr, comments, REVIEW_REQUEST_CHANGES)
+		}
+		return nil
+	})
+}
+
+func editDraftComment(pr *types.PR, comment types.Comment) {
+	app := state.GlobalState.App
+	background := state.GlobalState.MainFlexWrapper
+
+	// The editor replaces the panel overlay, so the panel is reopened afterwards
+	support.CloseModal(app, background)
+	textArea := support.ShowTextAreaModal(app, background, "Edit draft comment", comment.Content.Raw, func(text string) {
+		if strings.TrimSpace(text) == "" {
+			ShowPendingReview()
+			return
+		}
+		go func() {
+			notice := ""
+			if _, err := provider.ForPR(pr).UpdateComment(pr.ID, comment.ID, text, true); err != nil {
+				log.Printf("[REVIEW] Failed to update draft comment %d: %v", comment.ID, err)
+				notice = fmt.Sprintf("[danger]Failed to update draft: %s[-]", tview.Escape(err.Error()))
+			}
+			app.QueueUpdateDraw(func() {
+				showPendingReview(notice)
+			})
+		}()
+	})
+
+	// Esc on the editor returns to the panel instead of the main view
+	capture := textArea.GetInputCapture()
+	textArea.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEsc {
+			support.CloseModal(app, background)
+			ShowPendingReview()
+			return nil
+		}
+		return capture(event)
+	})
+}
+
+func deleteDraftComment(pr *types.PR, table *tview.Table, comment types.Comment) {
+	table.SetTitle(PENDING_REVIEW_TITLE + " [muted]deleting ...[-]")
+	go func() {
+		notice := ""
+		if err := provider.ForPR(pr).DeleteComment(pr.ID, comment.ID); err != nil {
+			log.Printf("[REVIEW] Failed to delete draft comment %d: %v", comment.ID, err)
+			notice = fmt.Sprintf("[danger]Failed to delete draft: %s[-]", tview.Escape(err.Error()))
+		}
+		loadPendingReview(pr, table, notice)
+	}()
+}
+
+// submitReview publishes every draft and then records the review decision, stopping at the first failure
+// so nothing is approved with comments still unpublished
+func submitReview(pr *types.PR, comments []types.Comment, decision string) {
+	app := state.GlobalState.App
+	background := state.GlobalState.MainFlexWrapper
+
+	support.CloseModal(app, background)
+	modal := support.ShowModal(app, background, fmt.Sprintf("Publishing %d draft comment(s) ...", len(comments)), nil, nil)
+
+	go func() {
+		err := provider.ForPR(pr).PublishDrafts(pr.ID, comments)
+		if err == nil {
+			switch decision {
+			case REVIEW_APPROVE:
+				err = provider.ForPR(pr).ApprovePR(pr.ID)
+			case REVIEW_REQUEST_CHANGES:
+				err = provider.ForPR(pr).RequestChangesPR(pr.ID)
+			}
+		}
+
+		text := fmt.Sprintf("[success]Published %d comment(s)[-]", len(comments))
+		switch decision {
+		case REVIEW_APPROVE:
+			text += " and [success]approved[-]"
+		case REVIEW_REQUEST_CHANGES:
+			text += " and [warning]requested changes[-]"
+		}
+		if err != nil {
+			log.Printf("[REVIEW] %v", err)
+			text = fmt.Sprintf("[danger]Submitting review failed[-]\n%v", err)
+		}
+
+		app.QueueUpdateDraw(func() {
+			modal.SetText(text).
+				ClearButtons().
+				AddButtons([]string{BUTTON_OK})
+			support.SetModalDoneFunc(app, background, modal, func(label string) {
+				// Reload the PR so activities and reviewer states reflect the submitted review
+				row, _ := state.GlobalState.PrList.GetSelection()
+				HandleOnPrSelect(*state.GlobalState.FilteredPRs, prRowToIndex(row))
+			})
+		})
+	}()
+}
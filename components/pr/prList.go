@@ -3,25 +3,32 @@ package pr
 import (
 	"fmt"
 	"log"
-	"simple-git-terminal/apis/bitbucket"
+	"simple-git-terminal/apis/provider"
 	"simple-git-terminal/components/shared"
+	"simple-git-terminal/config"
 	"simple-git-terminal/constants"
 	"simple-git-terminal/state"
 	"simple-git-terminal/support"
+	"simple-git-terminal/theme"
 	"simple-git-terminal/types"
+	"simple-git-terminal/ui"
+	"simple-git-terminal/util"
+	"slices"
 
 	"github.com/rivo/tview"
 )
 
 func PopulatePRList(prList *tview.Table) *tview.Table {
-	UpdateFilteredPRs()
+	if state.GlobalState.FilteredPRs == nil {
+		UpdateFilteredPRs()
+	}
 	prs := *state.GlobalState.FilteredPRs
+	renderPRList(prList, prs)
 	if len(prs) > 0 {
-		prList.Select(0, 0)
+		prList.Select(prIndexToRow(0), 0)
 		HandleOnPrSelect(prs, 0)
 	}
-	// Populate PR list
-	//	PopulatePRList(prList, prs)
+	trackPRListWidth(prList)
 
 	// Populate pagination
 	//
@@ -36,9 +43,12 @@ func PopulatePRList(prList *tview.Table) *tview.Table {
 	prList.SetSelectedFunc(func(row, column int) {
 		go func() {
 			prs := *state.GlobalState.FilteredPRs // use updated prs inside routine
-			HandleOnPrSelect(prs, row)
-			ShowSpinnerFetchPRsByQueryAndUpdatePrList()
+			HandleOnPrSelect(prs, prRowToIndex(row))
 		}()
+		// Compact mode moves on to the PR
+		if IsCompact() {
+			state.GlobalState.App.SetFocus(state.GlobalState.PrDetails)
+		}
 	})
 
 	prList.SetSelectionChangedFunc(func(row, column int) {
@@ -48,11 +58,57 @@ func PopulatePRList(prList *tview.Table) *tview.Table {
 	return prList
 }
 
+// renderPRList redraws the rows of the PR list table with the configured columns.
+// Columns the API cannot sort by are sorted here, which reorders prs in place.
+func renderPRList(prList *tview.Table, prs []types.PR) {
+	ctx := prListContext(prs)
+	if state.PRLocalSort != "" {
+		ui.SortPRs(prs, state.PRLocalSort, ctx)
+	}
+
+	prList.Clear()
+	columns := config.Current.PRList.Columns
+	if state.DashboardMode && !slices.Contains(columns, "repo") {
+		columns = append([]string{"repo"}, columns...)
+	}
+	ui.PopulatePRList(prList, prs, columns, ctx)
+
+	if slices.Contains(columns, "build") {
+		fetchBuildStatuses(prs)
+	}
+	if slices.Contains(columns, "tasks") {
+		fetchTaskCounts(prs)
+	}
+}
+
+// prRowToIndex converts a table row of the PR list into an index of the filtered PRs
+func prRowToIndex(row int) int {
+	return row - ui.PR_LIST_HEADER_ROWS
+}
+
+// prIndexToRow converts an index of the filtered PRs into a table row of the PR list
+func prIndexToRow(index int) int {
+	return index + ui.PR_LIST_HEADER_ROWS
+}
+
+// markSelectedPRRow moves the selection marker to the PR at index
+func markSelectedPRRow(index int) {
+	prList := state.GlobalState.PrList
+	for row := ui.PR_LIST_HEADER_ROWS; row < prList.GetRowCount(); row++ {
+		marker := ""
+		if row == prIndexToRow(index) {
+			marker = constants.ICON_SELECTED
+		}
+		prList.SetCell(row, 0, util.CellFormat(marker, theme.Current.Accent))
+	}
+}
+
 func HandleOnPrSelect(prs []types.PR, row int) {
 	if state.GlobalState != nil {
 		fetchMore := row == len(prs)
 		if fetchMore {
 			log.Printf("Fetch more...")
+			state.GlobalState.App.QueueUpdateDraw(ShowSpinnerFetchPRsByQueryAndUpdatePrList)
 		} else {
 			handleNormalPRSelect(prs, row)
 		}
@@ -66,11 +122,14 @@ func HandleOnPrSelect(prs []types.PR, row int) {
 func formatPRHeaderBranch(pr types.PR) string {
 	// Use fmt.Sprintf to format the header and apply tview's dynamic color syntax
 	headerText := fmt.Sprintf(
-		"[yellow]%s[white] "+constants.ICON_SIDE_ARROW+
-			"[green]%s",
+		"[warning]%s[text] "+constants.ICON_SIDE_ARROW+
+			"[success]%s",
 		pr.Source.Branch.Name,
 		pr.Destination.Branch.Name,
 	)
+	if state.DashboardMode {
+		headerText = fmt.Sprintf("[info]%s[text] %s", pr.Destination.Repository.FullName, headerText)
+	}
 
 	return headerText
 }
@@ -82,68 +141,53 @@ func handleNormalPRSelect(prs []types.PR, row int) {
 			state.SetSelectedPR(&prs[row])
 
 			//	 Update right panel and set header
-			state.GlobalState.RightPanelHeader.SetTitle(formatPRHeaderBranch(*state.GlobalState.SelectedPR))
-			state.GlobalState.RightPanelHeader.SetText(state.GlobalState.SelectedPR.Title)
+			state.GlobalState.App.QueueUpdateDraw(func() {
+				markSelectedPRRow(row)
+				state.GlobalState.RightPanelHeader.SetTitle(formatPRHeaderBranch(prs[row]))
+				state.GlobalState.RightPanelHeader.SetText(prs[row].Title)
+			})
 
 			// Show loading spinner for PR details
 			support.ShowLoadingSpinner(state.GlobalState.PrDetails, func() (interface{}, error) {
-				singlePR := bitbucket.FetchPR(prs[row].ID)
-				if singlePR == nil {
-					return nil, fmt.Errorf("Failed to fetch PR details")
+				pr, err := provider.ForPR(&prs[row]).FetchPR(prs[row].ID)
+				if err == nil {
+					// Cloud only counts the open tasks along with the PR
+					loadTaskCount(*pr)
 				}
-				return singlePR, nil
+				return pr, err
 			}, func(result interface{}, err error) {
 				if err != nil {
-					UpdatePRDetailView(fmt.Sprintf("[red]Error: %v[-]", err))
+					UpdatePRDetailView(fmt.Sprintf("[danger]Error: %v[-]", err))
 				} else {
 					// Assert result as the correct type: *types.PR
 					pr, ok := result.(*types.PR)
 					if !ok {
-						UpdatePRDetailView("[red]Failed to cast PR details[-]")
+						UpdatePRDetailView("[danger]Failed to cast PR details[-]")
 						return
 					}
 					UpdatePRDetailView(GeneratePRDetail(pr))
 				}
 			})
 
-			// Show loading spinner for activities
-			support.ShowLoadingSpinner(state.GlobalState.ActivityView, func() (interface{}, error) {
-				// Fetch activities
-				prActivities := bitbucket.FetchBitbucketActivities(state.GlobalState.SelectedPR.ID)
-				if prActivities == nil {
-					return nil, fmt.Errorf("Failed to fetch activities")
-				}
-				return prActivities, nil
-			}, func(result interface{}, err error) {
-				if err != nil {
-					UpdateActivityView(err.Error())
-				} else {
-					// Assert result as a slice of Activity
-					activities, ok := result.([]types.Activity)
-					if !ok {
-						UpdateActivityView("[red]Failed to cast activities[-]")
-						return
-					}
-					UpdateActivityView(CreateActivitiesView(activities))
-				}
-			})
+			loadActivities()
 
 			// Show loading spinner for diff stats
 			support.ShowLoadingSpinner(state.GlobalState.DiffStatView, func() (interface{}, error) {
 				// Fetch diff stats
-				diffStatData := bitbucket.FetchBitbucketDiffstat(state.GlobalState.SelectedPR.ID)
-				if diffStatData == nil {
-					return nil, fmt.Errorf("Failed to fetch diff stats")
+				diffStatData, err := fetchDiffstat(state.GlobalState.SelectedPR)
+				if err != nil {
+					return nil, err
 				}
+				loadReviewProgress(state.GlobalState.SelectedPR)
 				return diffStatData, nil
 			}, func(result interface{}, err error) {
 				if err != nil {
-					UpdateDiffStatView(err.Error())
+					UpdateDiffStatView(fmt.Sprintf("[danger]Failed to fetch diff stats: %v[-]", tview.Escape(err.Error())))
 				} else {
 					// Assert result as string
 					diffStat, ok := result.([]types.DiffstatEntry)
 					if !ok {
-						UpdateDiffStatView("[red]Failed to cast diff stats[-]")
+						UpdateDiffStatView("[danger]Failed to cast diff stats[-]")
 						return
 					}
 					UpdateDiffStatView(GenerateDiffStatTree(diffStat))
@@ -155,3 +199,25 @@ func handleNormalPRSelect(prs []types.PR, row int) {
 		}()
 	}
 }
+
+// loadActivities fetches the activities of the selected PR into the activity pane
+func loadActivities() {
+	state.GlobalState.ActivityView.SetTitle(ACTIVITIES_TITLE)
+	support.ShowLoadingSpinner(state.GlobalState.ActivityView, func() (interface{}, error) {
+		// Fetch activities
+		pr := state.GlobalState.SelectedPR
+		return provider.ForPR(pr).FetchActivities(pr.ID)
+	}, func(result interface{}, err error) {
+		if err != nil {
+			UpdateActivityView(fmt.Sprintf("[danger]Failed to fetch activities: %v[-]", tview.Escape(err.Error())))
+		} else {
+			// Assert result as a slice of Activity
+			activities, ok := result.([]types.Activity)
+			if !ok {
+				UpdateActivityView("[danger]Failed to cast activities[-]")
+				return
+			}
+			UpdateActivityView(CreateActivitiesView(activities))
+		}
+	})
+}
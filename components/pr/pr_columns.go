@@ -0,0 +1,333 @@
+package pr
+
+import (
+	"fmt"
+	"log"
+	"simple-git-terminal/apis/bitbucket"
+	"simple-git-terminal/apis/provider"
+	"simple-git-terminal/config"
+	"simple-git-terminal/state"
+	"simple-git-terminal/support"
+	"simple-git-terminal/theme"
+	"simple-git-terminal/types"
+	"simple-git-terminal/ui"
+	"simple-git-terminal/util"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+const PR_COLUMNS_TITLE = " PR list columns [muted]space show/hide  J/K move  s sort  Esc done[-] "
+
+// Number of PRs whose build statuses or tasks are fetched at the same time
+const columnFetchConcurrency = 4
+
+// buildStatus is the combined build state of a PR at its source commit
+type buildStatus struct {
+	commit string
+	state  string
+}
+
+var (
+	prListWidth   int
+	buildStatuses = make(map[string]buildStatus) // By PR key
+	buildFetching = make(map[string]bool)
+	buildMutex    sync.Mutex
+)
+
+// SetPRSort sorts the PR list by a column ID or API field, "-" prefix for descending.
+// Columns the API can sort by are sorted on the server, the others locally.
+func SetPRSort(sortKey string) {
+	id, descending := strings.CutPrefix(sortKey, "-")
+	sign := ""
+	if descending {
+		sign = "-"
+	}
+
+	state.PRSort, state.PRLocalSort = "", ""
+	column, ok := ui.FindPRListColumn(id)
+	switch {
+	case id == "":
+	case ok && column.SortField != "":
+		state.PRSort = sign + column.SortField
+	case ok:
+		state.PRLocalSort = sign + column.ID
+	default:
+		// Any other API field, e.g. from a saved view
+		state.PRSort = sortKey
+	}
+}
+
+// currentSortKey returns the current sort as a column ID when a column matches, otherwise as the API field
+func currentSortKey() string {
+	if state.PRLocalSort != "" {
+		return state.PRLocalSort
+	}
+	field, descending := strings.CutPrefix(state.PRSort, "-")
+	for _, column := range ui.PRListColumns {
+		if column.SortField != "" && column.SortField == field {
+			if descending {
+				return "-" + column.ID
+			}
+			return column.ID
+		}
+	}
+	return state.PRSort
+}
+
+func prListContext(prs []types.PR) ui.PRListContext {
+	ctx := ui.PRListContext{
+		Me:          state.CurrentUser,
+		BuildStates: make(map[string]string),
+		TaskCounts:  make(map[string]types.TaskCount),
+		Sort:        currentSortKey(),
+		Width:       prListWidth,
+	}
+	for _, pr := range prs {
+		if count, ok := knownTaskCount(pr); ok {
+			ctx.TaskCounts[pr.Key()] = count
+		}
+	}
+
+	buildMutex.Lock()
+	defer buildMutex.Unlock()
+	for _, pr := range prs {
+		if status, ok := buildStatuses[pr.Key()]; ok && status.commit == pr.Source.Commit.Hash {
+			ctx.BuildStates[pr.Key()] = status.state
+		}
+	}
+	return ctx
+}
+
+// trackPRListWidth re-renders the PR list when its width changes, so columns adapt to the terminal size
+func trackPRListWidth(prList *tview.Table) {
+	// The table has no border or padding, so its inner rect is the whole box
+	prList.SetDrawFunc(func(screen tcell.Screen, x, y, width, height int) (int, int, int, int) {
+		if width != prListWidth {
+			prListWidth = width
+			go state.GlobalState.App.QueueUpdateDraw(rerenderPRList)
+		}
+		return x, y, width, height
+	})
+}
+
+// rerenderPRList redraws the PR list from the loaded PRs, keeping the selection
+func rerenderPRList() {
+	if state.GlobalState.FilteredPRs == nil {
+		return
+	}
+	prList := state.GlobalState.PrList
+	row, _ := prList.GetSelection()
+	selected := ""
+	if state.GlobalState.SelectedPR != nil {
+		selected = state.GlobalState.SelectedPR.Key()
+	}
+
+	prs := *state.GlobalState.FilteredPRs
+	renderPRList(prList, prs)
+
+	// Local sorting may have moved the PRs around, the selection follows the marked PR
+	for i, pr := range prs {
+		if pr.Key() == selected {
+			markSelectedPRRow(i)
+			row = prIndexToRow(i)
+		}
+	}
+	prList.Select(row, 0)
+}
+
+// fetchBuildStatuses loads the build state of PRs whose source commit has none yet and re-renders the list
+func fetchBuildStatuses(prs []types.PR) {
+	if !provider.IsCloud() {
+		return
+	}
+
+	var missing []types.PR
+	buildMutex.Lock()
+	for _, pr := range prs {
+		status, ok := buildStatuses[pr.Key()]
+		if (!ok || status.commit != pr.Source.Commit.Hash) && !buildFetching[pr.Key()] {
+			buildFetching[pr.Key()] = true
+			missing = append(missing, pr)
+		}
+	}
+	buildMutex.Unlock()
+
+	if len(missing) == 0 {
+		return
+	}
+
+	go func() {
+		forEachPR(missing, loadBuildStatus)
+		state.GlobalState.App.QueueUpdateDraw(rerenderPRList)
+	}()
+}
+
+// forEachPR calls load for every PR, at most columnFetchConcurrency at a time, and returns when all are done
+func forEachPR(prs []types.PR, load func(types.PR)) {
+	var (
+		wg    sync.WaitGroup
+		slots = make(chan struct{}, columnFetchConcurrency)
+	)
+	for _, pr := range prs {
+		wg.Add(1)
+		go func(pr types.PR) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			load(pr)
+		}(pr)
+	}
+	wg.Wait()
+}
+
+// loadBuildStatus fetches the statuses of a PR's source commit and caches their combined state
+func loadBuildStatus(pr types.PR) {
+	combined := "NONE"
+	statuses, err := bitbucket.FetchPRStatuses(state.RepoOfPR(&pr), pr.ID)
+	if err != nil {
+		log.Printf("[COLUMNS] %v", err)
+	} else {
+		combined = combineBuildStates(statuses)
+	}
+
+	buildMutex.Lock()
+	buildStatuses[pr.Key()] = buildStatus{commit: pr.Source.Commit.Hash, state: combined}
+	delete(buildFetching, pr.Key())
+	buildMutex.Unlock()
+}
+
+// combineBuildStates reduces the statuses of a commit to one state, a failure wins over running builds
+func combineBuildStates(statuses []types.CommitStatus) string {
+	combined := "NONE"
+	for _, status := range statuses {
+		switch status.State {
+		case "FAILED", "STOPPED":
+			return "FAILED"
+		case "INPROGRESS":
+			combined = "INPROGRESS"
+		case "SUCCESSFUL":
+			if combined == "NONE" {
+				combined = "SUCCESSFUL"
+			}
+		}
+	}
+	return combined
+}
+
+// ShowColumnSettings lets the user pick, order and sort the PR list columns. Changes are saved to the config.
+func ShowColumnSettings() {
+	type columnChoice struct {
+		column  ui.PRListColumn
+		enabled bool
+	}
+
+	// Enabled columns first in their configured order, then the rest
+	var choices []columnChoice
+	for _, id := range config.Current.PRList.Columns {
+		if column, ok := ui.FindPRListColumn(id); ok {
+			choices = append(choices, columnChoice{column: column, enabled: true})
+		}
+	}
+	for _, column := range ui.PRListColumns {
+		if !slices.Contains(config.Current.PRList.Columns, column.ID) {
+			choices = append(choices, columnChoice{column: column})
+		}
+	}
+	initialSort := state.PRSort
+
+	table := tview.NewTable().
+		SetSelectable(true, false)
+	table.SetBackgroundColor(tcell.ColorDefault)
+	table.SetSelectedStyle(tcell.StyleDefault.Foreground(theme.Current.Selected))
+	table.SetBorder(true).
+		SetTitle(PR_COLUMNS_TITLE).
+		SetTitleAlign(tview.AlignLeft).
+		SetBorderColor(theme.Current.Accent)
+
+	render := func() {
+		sortID, descending := strings.CutPrefix(currentSortKey(), "-")
+		for i, choice := range choices {
+			check := "[muted]" + tview.Escape("[ ]") + "[-]"
+			if choice.enabled {
+				check = "[success]" + tview.Escape("[x]") + "[-]"
+			}
+			sortedBy := "local sort"
+			if choice.column.SortField != "" {
+				sortedBy = "server sort"
+			}
+			if choice.column.ID == sortID {
+				direction := "ascending"
+				if descending {
+					direction = "descending"
+				}
+				sortedBy = fmt.Sprintf("[accent]sorted %s[-]", direction)
+			}
+			table.SetCell(i, 0, util.CellFormat(check, tcell.ColorDefault))
+			table.SetCell(i, 1, util.CellFormat(choice.column.ID, theme.Current.Text).SetExpansion(1))
+			table.SetCell(i, 2, util.CellFormat(sortedBy, theme.Current.Muted))
+		}
+	}
+	render()
+
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		row, _ := table.GetSelection()
+		if event.Key() == tcell.KeyEsc || event.Key() == tcell.KeyEnter {
+			var columns []string
+			for _, choice := range choices {
+				if choice.enabled {
+					columns = append(columns, choice.column.ID)
+				}
+			}
+			config.Current.PRList.Columns = columns
+			config.Current.PRList.Sort = currentSortKey()
+			if err := config.Save(); err != nil {
+				log.Printf("[COLUMNS] Failed to save config: %v", err)
+			}
+
+			support.CloseModal(state.GlobalState.App, state.GlobalState.MainFlexWrapper)
+			if state.PRSort != initialSort {
+				state.Pagination.Page = 1
+				ShowSpinnerFetchPRsByQueryAndUpdatePrList()
+			} else {
+				rerenderPRList()
+			}
+			return nil
+		}
+		if event.Key() != tcell.KeyRune || row < 0 || row >= len(choices) {
+			return event
+		}
+
+		switch event.Rune() {
+		case ' ':
+			choices[row].enabled = !choices[row].enabled
+		case 'K':
+			if row > 0 {
+				choices[row-1], choices[row] = choices[row], choices[row-1]
+				table.Select(row-1, 0)
+			}
+		case 'J':
+			if row < len(choices)-1 {
+				choices[row+1], choices[row] = choices[row], choices[row+1]
+				table.Select(row+1, 0)
+			}
+		case 's':
+			// Sort by the column, pressing again flips the direction. Most columns are more useful descending first.
+			id := choices[row].column.ID
+			if currentSortKey() == "-"+id {
+				SetPRSort(id)
+			} else {
+				SetPRSort("-" + id)
+			}
+		default:
+			return event
+		}
+		render()
+		return nil
+	})
+
+	support.ShowOverlay(state.GlobalState.App, state.GlobalState.MainFlexWrapper, table, 60, len(choices)+2)
+}
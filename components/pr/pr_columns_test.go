@@ -0,0 +1,29 @@
+package pr
+
+import (
+	"simple-git-terminal/types"
+	"testing"
+)
+
+func TestCombineBuildStates(t *testing.T) {
+	tests := []struct {
+		states []string
+		want   string
+	}{
+		{nil, "NONE"},
+		{[]string{"SUCCESSFUL", "SUCCESSFUL"}, "SUCCESSFUL"},
+		{[]string{"SUCCESSFUL", "INPROGRESS"}, "INPROGRESS"},
+		{[]string{"INPROGRESS", "SUCCESSFUL"}, "INPROGRESS"},
+		{[]string{"INPROGRESS", "FAILED"}, "FAILED"},
+		{[]string{"STOPPED", "SUCCESSFUL"}, "FAILED"},
+	}
+	for _, tt := range tests {
+		var statuses []types.CommitStatus
+		for _, state := range tt.states {
+			statuses = append(statuses, types.CommitStatus{State: state})
+		}
+		if got := combineBuildStates(statuses); got != tt.want {
+			t.Errorf("combineBuildStates(%v) = %s, want %s", tt.states, got, tt.want)
+		}
+	}
+}
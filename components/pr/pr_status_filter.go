@@ -34,6 +34,10 @@ func CreatePRStatusFilterView() *tview.Flex {
 		support.CreateCheckBoxComponent("I'm Reviewer (i) ", func(checked bool) {
 			UpdatePRListWithFilter("iamreviewer", checked)
 		}).SetChecked(state.PRStatusFilter.IAmReviewer),
+
+		support.CreateCheckBoxComponent("My open tasks (U) ", func(checked bool) {
+			UpdatePRListWithFilter("myopentasks", checked)
+		}).SetChecked(state.PRStatusFilter.MyOpenTasks),
 	}
 
 	rowFlex := tview.NewFlex().SetDirection(tview.FlexColumn)
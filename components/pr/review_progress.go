@@ -0,0 +1,131 @@
+package pr
+
+import (
+	"fmt"
+	"log"
+	"simple-git-terminal/state"
+	"simple-git-terminal/storage"
+	"simple-git-terminal/types"
+	"strings"
+	"sync"
+
+	"github.com/rivo/tview"
+)
+
+const (
+	DIFF_TREE_TITLE = "Diff Tree [key]t|T[-]"
+	ICON_VIEWED     = "[success]✔[-] "
+)
+
+var (
+	currentViewed   *storage.ViewedFiles
+	currentViewedPR *types.PR
+	viewedMutex     sync.Mutex
+)
+
+// loadReviewProgress loads the viewed marks of the PR. When commits were pushed since the files were marked,
+// the marks of every file changed in between are dropped.
+func loadReviewProgress(pr *types.PR) {
+	repo := state.RepoOfPR(pr)
+	viewed := storage.LoadViewedFiles(repo.Workspace, repo.Repo, pr.ID)
+	head := pr.Source.Commit.Hash
+
+	if viewed.SourceCommit != "" && viewed.SourceCommit != head && len(viewed.Files) > 0 {
+		changed, err := fetchChangedFilesBetween(repo, viewed.SourceCommit, head)
+		if err != nil {
+			log.Printf("[REVIEW] Could not tell what changed since %s, clearing viewed files: %v", viewed.SourceCommit, err)
+			viewed.Files = make(map[string]bool)
+		}
+		for _, path := range changed {
+			if viewed.Files[path] {
+				log.Printf("[REVIEW] %s changed since last review, marking as not viewed", path)
+				delete(viewed.Files, path)
+			}
+		}
+		viewed.SourceCommit = head
+		if err := storage.SaveViewedFiles(repo.Workspace, repo.Repo, pr.ID, viewed); err != nil {
+			log.Printf("[REVIEW] Failed to save review progress: %v", err)
+		}
+	}
+
+	viewedMutex.Lock()
+	currentViewed = viewed
+	currentViewedPR = pr
+	viewedMutex.Unlock()
+}
+
+func isFileViewed(path string) bool {
+	viewedMutex.Lock()
+	defer viewedMutex.Unlock()
+	return currentViewed != nil && currentViewed.Files[path]
+}
+
+// setFilesViewed marks or unmarks the given files and persists the progress
+func setFilesViewed(paths []string, viewed bool) {
+	viewedMutex.Lock()
+	defer viewedMutex.Unlock()
+
+	if currentViewed == nil || currentViewedPR == nil {
+		return
+	}
+	for _, path := range paths {
+		if viewed {
+			currentViewed.Files[path] = true
+		} else {
+			delete(currentViewed.Files, path)
+		}
+	}
+	currentViewed.SourceCommit = currentViewedPR.Source.Commit.Hash
+
+	repo := state.RepoOfPR(currentViewedPR)
+	if err := storage.SaveViewedFiles(repo.Workspace, repo.Repo, currentViewedPR.ID, currentViewed); err != nil {
+		log.Printf("[REVIEW] Failed to save review progress: %v", err)
+	}
+}
+
+// toggleViewedAt flips the viewed state of a file node, or of every file below a directory node
+func toggleViewedAt(node *tview.TreeNode, files []string) {
+	ref, ok := node.GetReference().(*NodeReference)
+	if !ok {
+		return
+	}
+
+	targets := []string{ref.Path}
+	if ref.IsDir {
+		targets = nil
+		for _, file := range files {
+			if strings.HasPrefix(file, ref.Path+"/") {
+				targets = append(targets, file)
+			}
+		}
+	}
+	if len(targets) == 0 {
+		return
+	}
+
+	// Directories become viewed unless all of their files already are
+	markViewed := false
+	for _, target := range targets {
+		if !isFileViewed(target) {
+			markViewed = true
+			break
+		}
+	}
+	setFilesViewed(targets, markViewed)
+}
+
+// diffTreeTitle shows how many of the PR's files have been viewed, e.g. "12/30 viewed"
+func diffTreeTitle(files []string) string {
+	viewedCount := 0
+	for _, file := range files {
+		if isFileViewed(file) {
+			viewedCount++
+		}
+	}
+
+	color := "muted"
+	if len(files) > 0 && viewedCount == len(files) {
+		color = "success"
+	}
+	return fmt.Sprintf("%s [%s]%d/%d viewed[-]", DIFF_TREE_TITLE, color, viewedCount, len(files))
+}
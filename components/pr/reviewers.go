@@ -0,0 +1,438 @@
+package pr
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"simple-git-terminal/apis/bitbucket"
+	"simple-git-terminal/apis/provider"
+	"simple-git-terminal/state"
+	"simple-git-terminal/support"
+	"simple-git-terminal/theme"
+	"simple-git-terminal/types"
+	"simple-git-terminal/util"
+	"slices"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+const (
+	REVIEWERS_TITLE         = "Reviewers [muted]a add | x remove | D default reviewers | ctrl-s save | esc cancel[-]"
+	REVIEWERS_SEARCH_LABEL  = "Add: "
+	REVIEWERS_MAX_SUGGESTED = 10
+)
+
+// mergeRequirements are the approvals the branch restrictions of the destination branch ask for
+type mergeRequirements struct {
+	approvals        int // From anyone
+	defaultApprovals int // From default reviewers
+}
+
+// reviewerEditor is the state of the reviewer overlay of a PR
+type reviewerEditor struct {
+	pr           *types.PR
+	reviewers    []types.User
+	participants []types.Participant
+	members      []types.User
+	defaults     []types.Member
+	requirements mergeRequirements
+	suggestions  []types.User // Entries of the search drop-down
+	table        *tview.Table
+	info         *tview.TextView
+}
+
+// ShowReviewerEditor loads the selected PR with the workspace members, default reviewers and branch
+// restrictions, then opens the reviewer editor
+func ShowReviewerEditor() {
+	pr := state.GlobalState.SelectedPR
+	if pr == nil {
+		return
+	}
+	app := state.GlobalState.App
+	background := state.GlobalState.MainFlexWrapper
+	if !provider.IsCloud() {
+		support.ShowModal(app, background, "Reviewers can only be edited on Bitbucket Cloud", []string{BUTTON_OK}, nil)
+		return
+	}
+
+	modal := support.ShowModal(app, background, "Loading reviewers ...", nil, nil)
+	go func() {
+		editor, err := loadReviewerEditor(pr)
+		app.QueueUpdateDraw(func() {
+			if err != nil {
+				log.Printf("[REVIEWERS] %v", err)
+				modal.SetText(fmt.Sprintf("[danger]Could not load reviewers[-]\n%v", err)).
+					ClearButtons().
+					AddButtons([]string{BUTTON_OK})
+				support.SetModalDoneFunc(app, background, modal, nil)
+				return
+			}
+			support.CloseModal(app, background)
+			editor.show()
+		})
+	}()
+}
+
+// loadReviewerEditor fetches what the editor shows. Only the PR and its members are essential, default
+// reviewers and branch restrictions may need admin rights and are left out when they fail.
+func loadReviewerEditor(selected *types.PR) (*reviewerEditor, error) {
+	repo := state.RepoOfPR(selected)
+	pr, err := bitbucket.FetchPR(repo, selected.ID)
+	if err != nil {
+		return nil, err
+	}
+	members, err := bitbucket.FetchWorkspaceMembers(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	editor := &reviewerEditor{pr: pr, members: members, participants: pr.Participants}
+	for _, reviewer := range pr.Reviewers {
+		editor.reviewers = append(editor.reviewers, types.User{DisplayName: reviewer.DisplayName, UUID: reviewer.UUID, AccountID: reviewer.AccountID, Nickname: reviewer.Nickname})
+	}
+
+	if editor.defaults, err = bitbucket.FetchDefaultReviewers(repo); err != nil {
+		log.Printf("[REVIEWERS] %v", err)
+	}
+	restrictions, err := bitbucket.FetchBranchRestrictions(repo)
+	if err != nil {
+		log.Printf("[REVIEWERS] %v", err)
+	}
+	var model *types.BranchingModel
+	if slices.ContainsFunc(restrictions, func(r types.BranchRestriction) bool { return r.BranchMatchKind == "branching_model" }) {
+		if model, err = bitbucket.FetchBranchingModel(repo); err != nil {
+			log.Printf("[REVIEWERS] %v", err)
+		}
+	}
+	editor.requirements = requirementsFor(restrictions, model, pr.Destination.Branch.Name)
+	return editor, nil
+}
+
+// requirementsFor collects the approvals needed to merge into branch, the strictest rule winning
+func requirementsFor(restrictions []types.BranchRestriction, model *types.BranchingModel, branch string) mergeRequirements {
+	var requirements mergeRequirements
+	for _, restriction := range restrictions {
+		if !restrictionApplies(restriction, model, branch) {
+			continue
+		}
+		switch restriction.Kind {
+		case "require_approvals_to_merge":
+			requirements.approvals = max(requirements.approvals, restriction.Value)
+		case "require_default_reviewer_approvals_to_merge":
+			requirements.defaultApprovals = max(requirements.defaultApprovals, restriction.Value)
+		}
+	}
+	return requirements
+}
+
+// restrictionApplies matches a branch against the glob pattern or the branching model type of a restriction
+func restrictionApplies(restriction types.BranchRestriction, model *types.BranchingModel, branch string) bool {
+	switch restriction.BranchMatchKind {
+	case "glob":
+		// Bitbucket's * also matches slashes
+		pattern := "^" + strings.ReplaceAll(regexp.QuoteMeta(restriction.Pattern), `\*`, ".*") + "$"
+		matched, _ := regexp.MatchString(pattern, branch)
+		return matched
+	case "branching_model":
+		if model == nil {
+			return false
+		}
+		switch restriction.BranchType {
+		case "development":
+			return branch == modelBranchName(model.Development)
+		case "production":
+			return branch == modelBranchName(model.Production)
+		}
+		for _, branchType := range model.BranchTypes {
+			if branchType.Kind == restriction.BranchType && branchType.Prefix != "" && strings.HasPrefix(branch, branchType.Prefix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func modelBranchName(branch types.BranchingModelBranch) string {
+	if branch.Branch.Name != "" {
+		return branch.Branch.Name
+	}
+	return branch.Name
+}
+
+func (e *reviewerEditor) show() {
+	app := state.GlobalState.App
+	background := state.GlobalState.MainFlexWrapper
+
+	e.info = tview.NewTextView().SetDynamicColors(true)
+	e.info.SetBackgroundColor(tcell.ColorDefault)
+
+	e.table = tview.NewTable().
+		SetSelectable(true, false)
+	e.table.SetBackgroundColor(tcell.ColorDefault)
+	e.table.SetSelectedStyle(tcell.StyleDefault.Foreground(theme.Current.Selected))
+
+	search := tview.NewInputField().
+		SetLabel(REVIEWERS_SEARCH_LABEL).
+		SetPlaceholder("search workspace members").
+		SetLabelColor(theme.Current.Key).
+		SetFieldStyle(tcell.StyleDefault.Background(tcell.ColorDefault)).
+		SetPlaceholderStyle(tcell.StyleDefault.Foreground(theme.Current.Muted).Background(tcell.ColorDefault))
+	search.SetBackgroundColor(tcell.ColorDefault)
+	search.SetAutocompleteStyles(theme.Current.Background,
+		tcell.StyleDefault.Foreground(theme.Current.Text).Background(theme.Current.Background),
+		tcell.StyleDefault.Foreground(theme.Current.AccentText).Background(theme.Current.Accent))
+
+	search.SetAutocompleteFunc(func(text string) []string {
+		e.suggestions = e.searchMembers(text)
+		var entries []string
+		for _, user := range e.suggestions {
+			entries = append(entries, memberLabel(user))
+		}
+		return entries
+	})
+	search.SetAutocompletedFunc(func(text string, index int, source int) bool {
+		if source == tview.AutocompletedNavigate {
+			return false
+		}
+		if index >= 0 && index < len(e.suggestions) {
+			e.add(e.suggestions[index])
+		}
+		search.SetText("")
+		app.SetFocus(e.table)
+		return true
+	})
+	search.SetDoneFunc(func(key tcell.Key) {
+		// Enter without picking from the drop-down takes the only match
+		if key == tcell.KeyEnter && len(e.suggestions) == 1 {
+			e.add(e.suggestions[0])
+		}
+		search.SetText("")
+		app.SetFocus(e.table)
+	})
+
+	e.table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEsc:
+			support.CloseModal(app, background)
+			return nil
+		case tcell.KeyCtrlS:
+			e.save()
+			return nil
+		case tcell.KeyDelete:
+			row, _ := e.table.GetSelection()
+			e.remove(row)
+			return nil
+		case tcell.KeyRune:
+			switch event.Rune() {
+			case 'x':
+				row, _ := e.table.GetSelection()
+				e.remove(row)
+				return nil
+			case 'a', '/':
+				app.SetFocus(search)
+				return nil
+			case 'D':
+				e.addDefaults()
+				return nil
+			}
+		}
+		return event
+	})
+
+	layout := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(e.info, 2, 0, false).
+		AddItem(search, 1, 0, false).
+		AddItem(e.table, 0, 1, true)
+	layout.SetBackgroundColor(tcell.ColorDefault)
+	layout.SetBorder(true).
+		SetTitle(REVIEWERS_TITLE).
+		SetTitleAlign(tview.AlignLeft).
+		SetBorderColor(theme.Current.Accent)
+
+	e.render()
+	support.ShowOverlay(app, background, layout, 90, 20)
+	app.SetFocus(e.table)
+}
+
+// render lists the reviewers, followed by other participants who approved or requested changes
+func (e *reviewerEditor) render() {
+	e.table.Clear()
+
+	row := 0
+	for _, reviewer := range e.reviewers {
+		var tags []string
+		if e.isDefault(reviewer) {
+			tags = append(tags, "[accent]default[-]")
+			if e.requirements.defaultApprovals > 0 {
+				tags = append(tags, "[warning]required[-]")
+			}
+		}
+		e.setRow(row, reviewer, e.participant(reviewer), strings.Join(tags, " "))
+		row++
+	}
+	for _, participant := range e.participants {
+		if participant.User == nil || participant.State == "" || e.isReviewer(*participant.User) {
+			continue
+		}
+		e.setRow(row, *participant.User, &participant, "[muted]participant[-]")
+		row++
+	}
+	if row == 0 {
+		e.table.SetCell(0, 0, util.CellFormat(" No reviewers, press a to add one", theme.Current.Muted))
+	}
+
+	e.info.SetText(e.requirementsText())
+}
+
+func (e *reviewerEditor) setRow(row int, user types.User, participant *types.Participant, tags string) {
+	review := "[muted]pending[-]"
+	if participant != nil {
+		switch {
+		case participant.State == types.StateRequestedChanges:
+			review = "[danger]✖ changes requested[-]"
+		case participant.Approved || participant.State == types.StateApproved:
+			review = "[approved]✔ approved[-]"
+		}
+		if on, ok := participant.ParticipatedOn.(string); ok && on != "" && review != "[muted]pending[-]" {
+			review += " [muted]" + util.FormatTimeAgo(on) + "[-]"
+		}
+	}
+
+	e.table.SetCell(row, 0, util.CellFormat(tview.Escape(user.DisplayName), theme.Current.Text).SetExpansion(1).SetReference(user.UUID))
+	e.table.SetCell(row, 1, util.CellFormat(review, tcell.ColorDefault))
+	e.table.SetCell(row, 2, util.CellFormat(tags, tcell.ColorDefault))
+}
+
+func (e *reviewerEditor) requirementsText() string {
+	branch := tview.Escape(e.pr.Destination.Branch.Name)
+	var needs []string
+	if e.requirements.approvals > 0 {
+		needs = append(needs, fmt.Sprintf("%d approval(s)", e.requirements.approvals))
+	}
+	if e.requirements.defaultApprovals > 0 {
+		needs = append(needs, fmt.Sprintf("%d from default reviewers", e.requirements.defaultApprovals))
+	}
+	text := fmt.Sprintf(" Merging into [success]%s[-] needs no approvals", branch)
+	if len(needs) > 0 {
+		text = fmt.Sprintf(" Merging into [success]%s[-] needs [warning]%s[-]", branch, strings.Join(needs, ", "))
+	}
+
+	var missing []string
+	for _, member := range e.defaults {
+		if !e.isReviewer(member.User) && !e.isAuthor(member.User) {
+			missing = append(missing, member.User.DisplayName)
+		}
+	}
+	if len(missing) > 0 {
+		text += fmt.Sprintf("\n [muted]Default reviewers not added:[-] %s", tview.Escape(strings.Join(missing, ", ")))
+	}
+	return text
+}
+
+// searchMembers finds workspace members by name or nickname who are not reviewers yet
+func (e *reviewerEditor) searchMembers(text string) []types.User {
+	text = strings.ToLower(strings.TrimSpace(text))
+	if text == "" {
+		return nil
+	}
+	var found []types.User
+	for _, member := range e.members {
+		if e.isReviewer(member) {
+			continue
+		}
+		if strings.Contains(strings.ToLower(member.DisplayName), text) || strings.Contains(strings.ToLower(member.Nickname), text) {
+			found = append(found, member)
+		}
+		if len(found) == REVIEWERS_MAX_SUGGESTED {
+			break
+		}
+	}
+	return found
+}
+
+func memberLabel(user types.User) string {
+	if user.Nickname == "" || user.Nickname == user.DisplayName {
+		return user.DisplayName
+	}
+	return fmt.Sprintf("%s (%s)", user.DisplayName, user.Nickname)
+}
+
+func (e *reviewerEditor) add(user types.User) {
+	if !e.isReviewer(user) {
+		e.reviewers = append(e.reviewers, user)
+		e.render()
+	}
+}
+
+// addDefaults adds the default reviewers that are missing, except the author
+func (e *reviewerEditor) addDefaults() {
+	for _, member := range e.defaults {
+		if !e.isAuthor(member.User) && !e.isReviewer(member.User) {
+			e.reviewers = append(e.reviewers, member.User)
+		}
+	}
+	e.render()
+}
+
+// remove drops the reviewer of the table row. Participants who are not reviewers stay.
+func (e *reviewerEditor) remove(row int) {
+	uuid, ok := e.table.GetCell(row, 0).GetReference().(string)
+	if !ok {
+		return
+	}
+	e.reviewers = slices.DeleteFunc(e.reviewers, func(user types.User) bool { return user.UUID == uuid })
+	e.render()
+	e.table.Select(min(row, max(e.table.GetRowCount()-1, 0)), 0)
+}
+
+func (e *reviewerEditor) isReviewer(user types.User) bool {
+	return slices.ContainsFunc(e.reviewers, func(reviewer types.User) bool { return reviewer.UUID == user.UUID })
+}
+
+// isAuthor compares by UUID, display names are not unique
+func (e *reviewerEditor) isAuthor(user types.User) bool {
+	return e.pr.Author.UUID != "" && user.UUID == e.pr.Author.UUID
+}
+
+func (e *reviewerEditor) isDefault(user types.User) bool {
+	return slices.ContainsFunc(e.defaults, func(member types.Member) bool { return member.User.UUID == user.UUID })
+}
+
+func (e *reviewerEditor) participant(user types.User) *types.Participant {
+	for i, participant := range e.participants {
+		if participant.User != nil && participant.User.UUID == user.UUID {
+			return &e.participants[i]
+		}
+	}
+	return nil
+}
+
+// save replaces the PR's reviewers and reloads the PR
+func (e *reviewerEditor) save() {
+	app := state.GlobalState.App
+	background := state.GlobalState.MainFlexWrapper
+
+	support.CloseModal(app, background)
+	modal := support.ShowModal(app, background, "Saving reviewers ...", nil, nil)
+
+	go func() {
+		_, err := bitbucket.UpdatePRReviewers(state.RepoOfPR(e.pr), e.pr.ID, e.pr.Title, e.reviewers)
+		text := fmt.Sprintf("[success]%d reviewer(s) saved[-]", len(e.reviewers))
+		if err != nil {
+			log.Printf("[REVIEWERS] %v", err)
+			text = fmt.Sprintf("[danger]Saving reviewers failed[-]\n%v", err)
+		}
+
+		app.QueueUpdateDraw(func() {
+			modal.SetText(text).
+				ClearButtons().
+				AddButtons([]string{BUTTON_OK})
+			support.SetModalDoneFunc(app, background, modal, func(label string) {
+				row, _ := state.GlobalState.PrList.GetSelection()
+				HandleOnPrSelect(*state.GlobalState.FilteredPRs, prRowToIndex(row))
+			})
+		})
+	}()
+}
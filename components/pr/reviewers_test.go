@@ -0,0 +1,49 @@
+package pr
+
+import (
+	"encoding/json"
+	"simple-git-terminal/types"
+	"testing"
+)
+
+func TestRequirementsFor(t *testing.T) {
+	var model types.BranchingModel
+	err := json.Unmarshal([]byte(`{
+		"development": {"branch": {"name": "develop"}},
+		"production": {"name": "main"},
+		"branch_types": [{"kind": "release", "prefix": "release/"}]
+	}`), &model)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	restrictions := []types.BranchRestriction{
+		{Kind: "require_approvals_to_merge", BranchMatchKind: "glob", Pattern: "*", Value: 1},
+		{Kind: "require_approvals_to_merge", BranchMatchKind: "glob", Pattern: "release/*.x", Value: 3},
+		{Kind: "require_approvals_to_merge", BranchMatchKind: "branching_model", BranchType: "production", Value: 2},
+		{Kind: "require_default_reviewer_approvals_to_merge", BranchMatchKind: "branching_model", BranchType: "development", Value: 1},
+		{Kind: "require_default_reviewer_approvals_to_merge", BranchMatchKind: "branching_model", BranchType: "release", Value: 2},
+		{Kind: "push", BranchMatchKind: "glob", Pattern: "*", Value: 5},
+	}
+
+	tests := []struct {
+		name   string
+		branch string
+		model  *types.BranchingModel
+		want   mergeRequirements
+	}{
+		{name: "glob only", branch: "feature/x", model: &model, want: mergeRequirements{approvals: 1}},
+		{name: "production by name", branch: "main", model: &model, want: mergeRequirements{approvals: 2}},
+		{name: "development by branch", branch: "develop", model: &model, want: mergeRequirements{approvals: 1, defaultApprovals: 1}},
+		{name: "strictest glob wins, * matches slashes", branch: "release/1/2.x", model: &model,
+			want: mergeRequirements{approvals: 3, defaultApprovals: 2}},
+		{name: "no branching model", branch: "main", want: mergeRequirements{approvals: 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := requirementsFor(restrictions, tt.model, tt.branch); got != tt.want {
+				t.Errorf("requirementsFor(%s) = %+v, want %+v", tt.branch, got, tt.want)
+			}
+		})
+	}
+}
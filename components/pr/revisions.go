@@ -0,0 +1,265 @@
+package pr
+
+import (
+	"fmt"
+	"log"
+	"simple-git-terminal/apis/bitbucket"
+	"simple-git-terminal/apis/provider"
+	"simple-git-terminal/state"
+	"simple-git-terminal/support"
+	"simple-git-terminal/theme"
+	"simple-git-terminal/types"
+	"simple-git-terminal/util"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+const (
+	REVISIONS_TITLE = "Revisions [key]R[-] [muted]enter commit diff | space pick | i interdiff[-]"
+	ICON_PICKED     = "●"
+)
+
+// revisionsView is the commit list of the selected PR used to pick revisions to compare
+type revisionsView struct {
+	pr       *types.PR
+	commits  []types.Commit // Newest first, as returned by the API
+	reviewed string         // Head commit at the time of my last approval or change request
+	picked   []int          // Indices into commits, at most two
+	table    *tview.Table   // Last rendered, to find the selected commit
+}
+
+var currentRevisions *revisionsView
+
+// ShowRevisions lists the commits of the selected PR in the diff pane
+func ShowRevisions() {
+	pr := state.GlobalState.SelectedPR
+	if pr == nil {
+		return
+	}
+
+	state.GlobalState.DiffDetails.SetTitle(REVISIONS_TITLE)
+	support.ShowLoadingSpinner(state.GlobalState.DiffDetails, func() (interface{}, error) {
+		if !provider.IsCloud() {
+			return nil, fmt.Errorf("revisions are only available on Bitbucket Cloud")
+		}
+		commits, err := bitbucket.FetchPRCommits(state.RepoOfPR(pr), pr.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		reviewed := ""
+		if state.CurrentUser != nil && state.CurrentUser.UUID != "" {
+			activities, err := provider.ForPR(pr).FetchActivities(pr.ID)
+			if err != nil {
+				log.Printf("[REVISIONS] %v", err)
+			}
+			reviewed = lastReviewedRevision(activities, state.CurrentUser.UUID)
+		}
+
+		return &revisionsView{pr: pr, commits: commits, reviewed: reviewed}, nil
+	}, func(result interface{}, err error) {
+		if err != nil {
+			UpdateDiffDetailsView(fmt.Sprintf("[danger]Failed to load commits: %v[-]", err))
+			return
+		}
+		currentRevisions = result.(*revisionsView)
+		renderRevisions()
+	})
+}
+
+// lastReviewedRevision finds the PR head at the time of the user's latest approval or change request.
+// Every update activity carries the source commit the PR pointed at when it happened.
+func lastReviewedRevision(activities []types.Activity, userUUID string) string {
+	type revision struct {
+		date time.Time
+		hash string
+	}
+
+	var revisions []revision
+	var lastReview time.Time
+
+	for _, activity := range activities {
+		if hash := activity.Update.Source.Commit.Hash; hash != "" {
+			if date, err := time.Parse(time.RFC3339, activity.Update.Date); err == nil {
+				revisions = append(revisions, revision{date: date, hash: hash})
+			}
+		}
+
+		reviewDate := ""
+		if activity.Approval.User.UUID == userUUID {
+			reviewDate = activity.Approval.Date
+		} else if activity.ChangesRequested.User.UUID == userUUID {
+			reviewDate = activity.ChangesRequested.Date
+		}
+		if date, err := time.Parse(time.RFC3339, reviewDate); err == nil && date.After(lastReview) {
+			lastReview = date
+		}
+	}
+
+	if lastReview.IsZero() {
+		return ""
+	}
+
+	reviewed := revision{}
+	for _, rev := range revisions {
+		if !rev.date.After(lastReview) && rev.date.After(reviewed.date) {
+			reviewed = rev
+		}
+	}
+	log.Printf("[REVISIONS] Last reviewed revision: %s (review at %s)", reviewed.hash, lastReview)
+	return reviewed.hash
+}
+
+// isSameCommit compares hashes that may be abbreviated (activities only carry 12 characters)
+func isSameCommit(a, b string) bool {
+	if a == "" || b == "" {
+		return false
+	}
+	return strings.HasPrefix(a, b) || strings.HasPrefix(b, a)
+}
+
+func renderRevisions() {
+	view := currentRevisions
+
+	table := tview.NewTable().
+		SetSelectable(true, false)
+	table.SetBackgroundColor(tcell.ColorDefault)
+	table.SetSelectedStyle(tcell.StyleDefault.Foreground(theme.Current.Selected))
+
+	if len(view.commits) == 0 {
+		table.SetCell(0, 0, util.CellFormat(" No commits in this PR", theme.Current.Muted))
+	}
+	view.table = table
+
+	for i, commit := range view.commits {
+		marker := ""
+		for _, picked := range view.picked {
+			if picked == i {
+				marker = ICON_PICKED
+			}
+		}
+
+		var tags []string
+		if i == 0 {
+			tags = append(tags, "[accent]head[-]")
+		}
+		if isSameCommit(commit.Hash, view.reviewed) {
+			tags = append(tags, "[approved]✔ last reviewed[-]")
+		}
+
+		message := strings.SplitN(strings.TrimSpace(commit.Message), "\n", 2)[0]
+		author := commit.Author.User.DisplayName
+		if author == "" {
+			author = commit.Author.Raw
+		}
+
+		table.SetCell(i, 0, util.CellFormat(marker, theme.Current.Accent))
+		table.SetCell(i, 1, util.CellFormat(util.ShortHash(commit.Hash), theme.Current.Info))
+		table.SetCell(i, 2, util.CellFormat(tview.Escape(message), tcell.ColorDefault).SetExpansion(1))
+		table.SetCell(i, 3, util.CellFormat(strings.Join(tags, " "), tcell.ColorDefault))
+		table.SetCell(i, 4, util.CellFormat(util.FormatInitials(author), theme.Current.Muted))
+		table.SetCell(i, 5, util.CellFormat(util.FormatTimeAgo(commit.Date), theme.Current.Muted))
+	}
+
+	table.SetSelectedFunc(func(row, column int) {
+		if row >= 0 && row < len(view.commits) {
+			commit := view.commits[row]
+			showRevisionDiff(commit.Hash, "", fmt.Sprintf("Commit %s", util.ShortHash(commit.Hash)))
+		}
+	})
+
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() != tcell.KeyRune {
+			return event
+		}
+		switch event.Rune() {
+		case ' ':
+			row, _ := table.GetSelection()
+			togglePickedRevision(row)
+			renderRevisions()
+			return nil
+		case 'i':
+			showInterdiff()
+			return nil
+		}
+		return event
+	})
+
+	state.GlobalState.DiffDetails.SetTitle(REVISIONS_TITLE)
+	UpdateDiffDetailsView(table)
+	state.GlobalState.App.SetFocus(table)
+}
+
+func togglePickedRevision(row int) {
+	view := currentRevisions
+	if row < 0 || row >= len(view.commits) {
+		return
+	}
+	for i, picked := range view.picked {
+		if picked == row {
+			view.picked = append(view.picked[:i], view.picked[i+1:]...)
+			return
+		}
+	}
+	view.picked = append(view.picked, row)
+	if len(view.picked) > 2 {
+		view.picked = view.picked[1:]
+	}
+}
+
+// showInterdiff compares the two picked revisions, or by default the last reviewed revision with the head
+func showInterdiff() {
+	view := currentRevisions
+	if len(view.commits) == 0 {
+		return
+	}
+
+	var newer, older string
+	switch {
+	case len(view.picked) == 2:
+		// Commits are listed newest first
+		first, second := view.picked[0], view.picked[1]
+		newer, older = view.commits[min(first, second)].Hash, view.commits[max(first, second)].Hash
+	case view.reviewed != "":
+		newer, older = view.commits[0].Hash, view.reviewed
+	default:
+		state.GlobalState.DiffDetails.SetTitle(REVISIONS_TITLE + " [warning]no earlier review found, pick two revisions with space[-]")
+		return
+	}
+
+	if isSameCommit(newer, older) {
+		state.GlobalState.DiffDetails.SetTitle(REVISIONS_TITLE + " [warning]nothing changed since your last review[-]")
+		return
+	}
+
+	showRevisionDiff(newer, older, fmt.Sprintf("Interdiff %s..%s", util.ShortHash(older), util.ShortHash(newer)))
+}
+
+// showRevisionDiff shows a single commit diff, or the diff between two revisions when oldCommit is set
+func showRevisionDiff(newCommit string, oldCommit string, title string) {
+	repo := state.RepoOfPR(currentRevisions.pr)
+	support.ShowLoadingSpinner(state.GlobalState.DiffDetails, func() (interface{}, error) {
+		return fetchCommitDiff(repo, newCommit, oldCommit)
+	}, func(result interface{}, err error) {
+		if err != nil {
+			UpdateDiffDetailsView(fmt.Sprintf("[danger]Failed to load diff: %v[-]", err))
+			return
+		}
+
+		table := util.GenerateMultiFileDiffView(result.(string))
+		table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+			if event.Key() == tcell.KeyEsc {
+				renderRevisions()
+				return nil
+			}
+			return event
+		})
+
+		state.GlobalState.DiffDetails.SetTitle(title + " [muted]esc back to revisions[-]")
+		UpdateDiffDetailsView(table)
+		state.GlobalState.App.SetFocus(table)
+	})
+}
@@ -0,0 +1,73 @@
+package pr
+
+import (
+	"simple-git-terminal/types"
+	"testing"
+)
+
+func updateActivity(date string, hash string) types.Activity {
+	var activity types.Activity
+	activity.Update.Date = date
+	activity.Update.Source.Commit.Hash = hash
+	return activity
+}
+
+func approvalActivity(date string, uuid string) types.Activity {
+	var activity types.Activity
+	activity.Approval.Date = date
+	activity.Approval.User.UUID = uuid
+	return activity
+}
+
+func changesActivity(date string, uuid string) types.Activity {
+	var activity types.Activity
+	activity.ChangesRequested.Date = date
+	activity.ChangesRequested.User.UUID = uuid
+	return activity
+}
+
+func TestLastReviewedRevision(t *testing.T) {
+	// Newest first, like the API returns them
+	activities := []types.Activity{
+		updateActivity("2024-01-04T00:00:00Z", "ccc"),
+		approvalActivity("2024-01-03T12:00:00Z", "{other}"),
+		changesActivity("2024-01-02T12:00:00Z", "{me}"),
+		updateActivity("2024-01-02T00:00:00Z", "bbb"),
+		approvalActivity("2024-01-01T12:00:00Z", "{me}"),
+		updateActivity("2024-01-01T00:00:00Z", "aaa"),
+	}
+
+	tests := []struct {
+		name string
+		user string
+		want string
+	}{
+		{name: "latest change request wins", user: "{me}", want: "bbb"},
+		{name: "approval of another user", user: "{other}", want: "bbb"},
+		{name: "never reviewed", user: "{nobody}", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := lastReviewedRevision(activities, tt.user); got != tt.want {
+				t.Errorf("lastReviewedRevision = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsSameCommit(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"abcdef123456", "abcdef1234567890", true},
+		{"abcdef1234567890", "abcdef123456", true},
+		{"abcdef", "abcdee", false},
+		{"", "abc", false},
+	}
+	for _, tt := range tests {
+		if got := isSameCommit(tt.a, tt.b); got != tt.want {
+			t.Errorf("isSameCommit(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,236 @@
+package pr
+
+import (
+	"fmt"
+	"log"
+	"simple-git-terminal/apis/bitbucket"
+	"simple-git-terminal/apis/provider"
+	"simple-git-terminal/config"
+	"simple-git-terminal/state"
+	"simple-git-terminal/support"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+const (
+	VIEW_COUNT_REFRESH = 60 * time.Second
+	SAVE_VIEW_TITLE    = "Save current filters as view [muted]enter save | esc cancel[-]"
+)
+
+var (
+	viewTabs        *tview.TextView
+	activeViewIndex = -1    // -1 when the list is not showing a saved view
+	viewModified    = false // Filters were changed by hand after selecting the view
+	viewCounts      = make(map[string]int)
+	viewCountsMutex sync.Mutex
+	// refreshMutex lets one refresh run at a time, so an older one cannot overwrite newer counts
+	refreshMutex sync.Mutex
+)
+
+// CreateSavedViewTabs creates the tab row shown above the PR list
+func CreateSavedViewTabs() *tview.TextView {
+	viewTabs = tview.NewTextView().
+		SetDynamicColors(true).
+		SetWrap(false)
+	viewTabs.SetBackgroundColor(tcell.ColorDefault)
+	renderViewTabs()
+	return viewTabs
+}
+
+// renderViewTabs draws "1 Needs my review (4)  2 My open PRs (2)" with the active view highlighted
+func renderViewTabs() {
+	if viewTabs == nil {
+		return
+	}
+
+	viewCountsMutex.Lock()
+	defer viewCountsMutex.Unlock()
+
+	var tabs []string
+	for i, view := range config.Current.Views {
+		count := "…"
+		if n, ok := viewCounts[view.Name]; ok {
+			count = fmt.Sprintf("%d", n)
+		}
+		label := fmt.Sprintf("%d %s (%s)", i+1, tview.Escape(view.Name), count)
+		if !hasViewCounts() {
+			label = fmt.Sprintf("%d %s", i+1, tview.Escape(view.Name))
+		}
+
+		if i == activeViewIndex {
+			modified := ""
+			if viewModified {
+				modified = "*"
+			}
+			tabs = append(tabs, fmt.Sprintf("[accenttext:accent] %s%s [-:-]", label, modified))
+		} else {
+			tabs = append(tabs, fmt.Sprintf("[muted] %s [-]", label))
+		}
+	}
+	if len(tabs) == 0 {
+		tabs = append(tabs, "[muted] No saved views, press S to save the current filters[-]")
+	}
+	viewTabs.SetText(strings.Join(tabs, "[muted]│[-]"))
+}
+
+// viewFilter turns a saved view into the filter the query is built from
+func viewFilter(view config.SavedView) bitbucket.PRFilter {
+	filter := bitbucket.PRFilter{
+		Author:      view.Role == config.RoleAuthor,
+		Reviewer:    view.Role == config.RoleReviewer,
+		Participant: view.Role == config.RoleParticipant,
+		OpenTasks:   view.OpenTasks,
+		Destination: view.Destination,
+		Search:      view.Query,
+	}
+	for _, viewState := range view.States {
+		filter.States = append(filter.States, strings.ToUpper(viewState))
+	}
+	return filter
+}
+
+// ApplySavedView switches the PR list to the saved view at index
+func ApplySavedView(index int) {
+	views := config.Current.Views
+	if index < 0 || index >= len(views) {
+		return
+	}
+	view := views[index]
+	log.Printf("[VIEWS] Switching to view %q", view.Name)
+
+	filter := viewFilter(view)
+	state.InitializePRStatusFilter(&state.PRStatusFilterType{
+		IAmAuthor:      filter.Author,
+		IAmReviewer:    filter.Reviewer,
+		IAmParticipant: filter.Participant,
+		MyOpenTasks:    filter.OpenTasks,
+	})
+	for _, viewState := range filter.States {
+		state.SetPRStatusFilter(strings.ToLower(viewState), true)
+	}
+	state.PRDestinationBranch = view.Destination
+	if view.Sort != "" {
+		SetPRSort(view.Sort)
+	} else {
+		SetPRSort(config.Current.PRList.Sort)
+	}
+	state.SetSearchTerm(view.Query)
+	state.GlobalState.PrListSearchBar.SetText(view.Query)
+	state.Pagination.Page = 1
+
+	activeViewIndex = index
+	viewModified = false
+	renderViewTabs()
+
+	UpdatePRStatusFilterView(CreatePRStatusFilterView())
+	ShowSpinnerFetchPRsByQueryAndUpdatePrList()
+}
+
+// markViewModified flags the active view as changed after filters were toggled or a search was made
+func markViewModified() {
+	if activeViewIndex >= 0 {
+		viewModified = true
+		renderViewTabs()
+	}
+}
+
+// hasViewCounts reports whether the tabs show counts. They are per repository, the dashboard spans several,
+// and they rely on BBQL which only Cloud supports.
+func hasViewCounts() bool {
+	return !state.DashboardMode && provider.IsCloud()
+}
+
+// StartViewCounts keeps the counts of all saved views up to date in the background
+func StartViewCounts() {
+	if !hasViewCounts() {
+		return
+	}
+	app := state.GlobalState.App
+	go func() {
+		for {
+			// The views belong to the UI goroutine, saving a view changes them
+			var views []config.SavedView
+			app.QueueUpdate(func() {
+				views = slices.Clone(config.Current.Views)
+			})
+			refreshViewCounts(views)
+			time.Sleep(VIEW_COUNT_REFRESH)
+		}
+	}()
+}
+
+// refreshViewCounts counts the PRs of each view, views is a copy taken on the UI goroutine
+func refreshViewCounts(views []config.SavedView) {
+	refreshMutex.Lock()
+	defer refreshMutex.Unlock()
+
+	for _, view := range views {
+		count, err := bitbucket.CountPRs(state.CurrentRepo(), bitbucket.BuildFilterQuery(viewFilter(view), state.CurrentUser))
+		if err != nil {
+			log.Printf("[VIEWS] Failed to count %q: %v", view.Name, err)
+			continue
+		}
+		viewCountsMutex.Lock()
+		viewCounts[view.Name] = count
+		viewCountsMutex.Unlock()
+	}
+	state.GlobalState.App.QueueUpdateDraw(renderViewTabs)
+}
+
+// SaveCurrentAsView asks for a name and stores the current filters as a saved view in the config.
+// A view with the same name is replaced.
+func SaveCurrentAsView() {
+	app := state.GlobalState.App
+	support.ShowInputModal(app, state.GlobalState.MainFlexWrapper, SAVE_VIEW_TITLE, "View name", "", func(text string) {
+		name := strings.TrimSpace(text)
+		if name == "" {
+			return
+		}
+
+		view := config.SavedView{
+			Name:        name,
+			Destination: state.PRDestinationBranch,
+			Query:       state.SearchTerm,
+			Sort:        currentSortKey(),
+			OpenTasks:   state.PRStatusFilter.MyOpenTasks,
+		}
+		for _, prState := range bitbucket.BuildFilterStates() {
+			view.States = append(view.States, strings.ToLower(prState))
+		}
+		switch {
+		case state.PRStatusFilter.IAmAuthor:
+			view.Role = config.RoleAuthor
+		case state.PRStatusFilter.IAmReviewer:
+			view.Role = config.RoleReviewer
+		case state.PRStatusFilter.IAmParticipant:
+			view.Role = config.RoleParticipant
+		}
+
+		index := len(config.Current.Views)
+		for i, existing := range config.Current.Views {
+			if existing.Name == name {
+				index = i
+			}
+		}
+		if index == len(config.Current.Views) {
+			config.Current.Views = append(config.Current.Views, view)
+		} else {
+			config.Current.Views[index] = view
+		}
+		if err := config.Save(); err != nil {
+			log.Printf("[VIEWS] Failed to save config: %v", err)
+		}
+
+		activeViewIndex = index
+		viewModified = false
+		renderViewTabs()
+		if hasViewCounts() {
+			go refreshViewCounts(slices.Clone(config.Current.Views))
+		}
+	})
+}
@@ -0,0 +1,80 @@
+package pr
+
+import (
+	"simple-git-terminal/apis/bitbucket"
+	"simple-git-terminal/state"
+	"simple-git-terminal/storage"
+	"strings"
+	"time"
+
+	"github.com/rivo/tview"
+)
+
+const (
+	SEARCH_TITLE       = "\uf002  Search PR [key]s"
+	SEARCH_PLACEHOLDER = ` author:me state:open branch:feature/* updated:<7d "text"`
+)
+
+var (
+	searchHistory      []string
+	searchHistoryIndex = -1 // -1 is the query being typed, 0 the most recent one
+)
+
+// SetupSearchBar validates the query while typing, so syntax errors show up before searching
+func SetupSearchBar(searchBar *tview.InputField) {
+	searchBar.SetPlaceholder(SEARCH_PLACEHOLDER)
+	searchBar.SetChangedFunc(func(text string) {
+		validateSearch(searchBar, text)
+	})
+}
+
+// validateSearch shows the first syntax error of the query in the search bar title
+func validateSearch(searchBar *tview.InputField, text string) bool {
+	if _, err := bitbucket.TranslatePRQuery(text, state.CurrentUser, time.Now()); err != nil {
+		searchBar.SetTitle(SEARCH_TITLE + "[-] [danger]" + tview.Escape(err.Error()) + "[-]")
+		return false
+	}
+	searchBar.SetTitle(SEARCH_TITLE)
+	return true
+}
+
+// SubmitSearch runs the query in the search bar unless it has syntax errors, and remembers it in the history
+func SubmitSearch() bool {
+	searchBar := state.GlobalState.PrListSearchBar
+	text := strings.TrimSpace(searchBar.GetText())
+	if !validateSearch(searchBar, text) {
+		return false
+	}
+
+	if text != "" {
+		searchHistory = storage.AddSearchHistory(text)
+	}
+	searchHistoryIndex = -1
+
+	state.SetSearchTerm(text)
+	markViewModified()
+	ShowSpinnerFetchPRsByQueryAndUpdatePrList()
+	return true
+}
+
+// RecallSearchHistory steps through previous queries, up for older and down for newer ones
+func RecallSearchHistory(older bool) {
+	if searchHistory == nil {
+		searchHistory = storage.LoadSearchHistory()
+	}
+	if len(searchHistory) == 0 {
+		return
+	}
+
+	if older {
+		searchHistoryIndex = min(searchHistoryIndex+1, len(searchHistory)-1)
+	} else {
+		searchHistoryIndex = max(searchHistoryIndex-1, -1)
+	}
+
+	text := ""
+	if searchHistoryIndex >= 0 {
+		text = searchHistory[searchHistoryIndex]
+	}
+	state.GlobalState.PrListSearchBar.SetText(text)
+}
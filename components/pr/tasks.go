@@ -0,0 +1,168 @@
+package pr
+
+import (
+	"fmt"
+	"log"
+	"simple-git-terminal/apis/provider"
+	"simple-git-terminal/state"
+	"simple-git-terminal/support"
+	"simple-git-terminal/types"
+	"strings"
+	"sync"
+
+	"github.com/rivo/tview"
+)
+
+const (
+	ICON_TASK_OPEN     = "☐ "
+	ICON_TASK_RESOLVED = "☑ "
+)
+
+// cachedTaskCount is the task count of a Cloud PR as of its last update
+type cachedTaskCount struct {
+	updatedOn string
+	count     types.TaskCount
+}
+
+var (
+	taskCounts   = make(map[string]cachedTaskCount) // By PR key
+	taskFetching = make(map[string]bool)
+	taskMutex    sync.Mutex
+)
+
+// knownTaskCount returns the open and resolved tasks of a PR. Data Center sends both with the PR, Cloud
+// only the open ones, so its resolved tasks are unknown until the tasks were listed.
+func knownTaskCount(pr types.PR) (types.TaskCount, bool) {
+	if !provider.IsCloud() {
+		return types.TaskCount{Open: pr.TaskCount, Resolved: pr.ResolvedTaskCount}, true
+	}
+	taskMutex.Lock()
+	defer taskMutex.Unlock()
+	cached, ok := taskCounts[pr.Key()]
+	return cached.count, ok && cached.updatedOn == pr.UpdatedOn
+}
+
+// storeTaskCount records the count of tasks on pr and reports whether it differs from what the PR list showed
+func storeTaskCount(pr *types.PR, tasks []types.Task) bool {
+	count := types.CountTasks(tasks)
+	previous, known := knownTaskCount(*pr)
+
+	taskMutex.Lock()
+	taskCounts[pr.Key()] = cachedTaskCount{updatedOn: pr.UpdatedOn, count: count}
+	taskMutex.Unlock()
+	pr.TaskCount = count.Open
+	pr.ResolvedTaskCount = count.Resolved
+	return !known || previous != count
+}
+
+// fetchTaskCounts lists the tasks of Cloud PRs whose count is unknown and re-renders the list
+func fetchTaskCounts(prs []types.PR) {
+	if !provider.IsCloud() {
+		return
+	}
+
+	var missing []types.PR
+	for _, pr := range prs {
+		if _, ok := knownTaskCount(pr); ok {
+			continue
+		}
+		taskMutex.Lock()
+		if !taskFetching[pr.Key()] {
+			taskFetching[pr.Key()] = true
+			missing = append(missing, pr)
+		}
+		taskMutex.Unlock()
+	}
+	if len(missing) == 0 {
+		return
+	}
+
+	go func() {
+		forEachPR(missing, func(pr types.PR) {
+			loadTaskCount(pr)
+			taskMutex.Lock()
+			delete(taskFetching, pr.Key())
+			taskMutex.Unlock()
+		})
+		state.GlobalState.App.QueueUpdateDraw(rerenderPRList)
+	}()
+}
+
+// loadTaskCount lists the tasks of a Cloud PR whose count is unknown, blocking until they are loaded
+func loadTaskCount(pr types.PR) {
+	if _, ok := knownTaskCount(pr); ok {
+		return
+	}
+	tasks, err := provider.ForPR(&pr).FetchTasks(pr.ID)
+	if err != nil {
+		log.Printf("[TASKS] %v", err)
+		return
+	}
+	taskMutex.Lock()
+	taskCounts[pr.Key()] = cachedTaskCount{updatedOn: pr.UpdatedOn, count: types.CountTasks(tasks)}
+	taskMutex.Unlock()
+}
+
+// formatTaskCount describes the tasks of a PR for the detail pane
+func formatTaskCount(pr types.PR) string {
+	count, ok := knownTaskCount(pr)
+	if !ok {
+		return fmt.Sprintf("[warning]%d open[-]", pr.TaskCount)
+	}
+	if count.Open+count.Resolved == 0 {
+		return "[muted]None[-]"
+	}
+	return fmt.Sprintf("[warning]%d open[-], [resolved]%d resolved[-]", count.Open, count.Resolved)
+}
+
+// formatTask renders a task as a checkbox line of the conversation, indented by depth
+func formatTask(task types.Task, depth int) string {
+	indent := strings.Repeat("  ", depth)
+	text := tview.Escape(strings.SplitN(strings.TrimSpace(task.Content.Raw), "\n", 2)[0])
+	creator := tview.Escape(task.Creator.DisplayName)
+	if task.IsResolved() {
+		return fmt.Sprintf("%s[resolved]%s[-][muted]%s (%s)[-]", indent, ICON_TASK_RESOLVED, text, creator)
+	}
+	return fmt.Sprintf("%s[warning]%s[-]%s [muted](%s)[-]", indent, ICON_TASK_OPEN, text, creator)
+}
+
+// createTask asks for the text of a new task on the comment, or on the PR itself when comment is nil,
+// then reloads the conversation at row
+func createTask(pr *types.PR, comment *types.Comment, row int) {
+	title := fmt.Sprintf("New task on #%d", pr.ID)
+	commentID := 0
+	if comment != nil {
+		title = fmt.Sprintf("New task on the comment of %s", tview.Escape(comment.User.DisplayName))
+		commentID = comment.ID
+	}
+
+	support.ShowTextAreaModal(state.GlobalState.App, state.GlobalState.MainFlexWrapper, title, "", func(text string) {
+		if strings.TrimSpace(text) == "" {
+			return
+		}
+		go func() {
+			if _, err := provider.ForPR(pr).CreateTask(pr.ID, commentID, strings.TrimSpace(text)); err != nil {
+				log.Printf("[TASKS] Failed to create task: %v", err)
+				showPRLookupMessage(fmt.Sprintf("[danger]Failed to create task[-]\n%v", err))
+				return
+			}
+			state.GlobalState.App.QueueUpdateDraw(func() {
+				loadConversation(pr, row)
+			})
+		}()
+	})
+}
+
+// toggleTask resolves an open task or reopens a resolved one, then reloads the conversation at row
+func toggleTask(pr *types.PR, task types.Task, row int) {
+	go func() {
+		if err := provider.ForPR(pr).SetTaskResolved(pr.ID, task.ID, !task.IsResolved()); err != nil {
+			log.Printf("[TASKS] Failed to update task %d: %v", task.ID, err)
+			showPRLookupMessage(fmt.Sprintf("[danger]Failed to update task[-]\n%v", err))
+			return
+		}
+		state.GlobalState.App.QueueUpdateDraw(func() {
+			loadConversation(pr, row)
+		})
+	}()
+}
@@ -1,7 +1,7 @@
 package pr
 
 import (
-	"simple-git-terminal/apis/bitbucket"
+	"log"
 	"simple-git-terminal/state"
 	"simple-git-terminal/support"
 	"simple-git-terminal/types"
@@ -30,7 +30,7 @@ func UpdatePRListView() {
 func UpdatePRListErrorView() {
 	if state.GlobalState != nil && state.GlobalState.PrList != nil && state.GlobalState.FilteredPRs != nil {
 		state.GlobalState.PrList.Clear()
-		support.UpdateView(state.GlobalState.PrList, "[red] Error rendering PR list")
+		support.UpdateView(state.GlobalState.PrList, "[danger] Error rendering PR list")
 		state.GlobalState.App.Draw()
 	}
 }
@@ -47,11 +47,15 @@ func UpdatePRStatusFilterView(content interface{}) {
 
 func UpdatePRListWithFilter(filter string, checked bool) {
 	state.SetPRStatusFilter(filter, checked)
+	markViewModified()
 	ShowSpinnerFetchPRsByQueryAndUpdatePrList()
 }
 
 func UpdateFilteredPRs() {
-	prs, pagination := bitbucket.FetchPRsByQuery(bitbucket.BuildQuery(""))
+	prs, pagination, err := fetchPRList("")
+	if err != nil {
+		log.Printf("[PR LIST] %v", err)
+	}
 	state.SetFilteredPRs(&prs)
 	state.SetPagination(&pagination)
 }
@@ -60,11 +64,11 @@ func ShowSpinnerFetchPRsByQueryAndUpdatePrList() {
 	if state.GlobalState != nil {
 		state.GlobalState.PrList.Clear()
 		support.ShowLoadingSpinner(state.GlobalState.PrList, func() (interface{}, error) {
-			prs, pagination := bitbucket.FetchPRsByQuery(bitbucket.BuildQuery(state.SearchTerm))
+			prs, pagination, err := fetchPRList(state.SearchTerm)
 			return struct {
 				PRs        []types.PR
 				Pagination types.Pagination
-			}{prs, pagination}, nil
+			}{prs, pagination}, err
 		}, func(result interface{}, err error) {
 			if err != nil {
 				UpdatePRListErrorView()
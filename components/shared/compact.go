@@ -0,0 +1,172 @@
+package shared
+
+import (
+	"fmt"
+	"log"
+	"simple-git-terminal/config"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+const (
+	// Terminals shorter than this use compact mode regardless of the width
+	COMPACT_MIN_HEIGHT = 30
+
+	BREADCRUMB_SEPARATOR = " [muted]›[-] "
+)
+
+// CompactPane is a pane shown on its own in compact mode
+type CompactPane struct {
+	Name  string          // Label in the pane switcher
+	Key   string          // Key switching to the pane, shown in the pane switcher
+	Level int             // Depth in the breadcrumbs, e.g. 0 for the list and 1 for a PR
+	View  tview.Primitive // Also part of the full layout
+	Crumb func() string   // Breadcrumb of the pane's level, empty to leave it out
+}
+
+// ResponsiveLayout shows the full layout on large terminals and one pane at a time with
+// breadcrumbs and a pane switcher below the compact width. It follows resizes while running.
+type ResponsiveLayout struct {
+	*tview.Flex
+	app         *tview.Application
+	full        tview.Primitive
+	panes       []CompactPane
+	breadcrumbs *tview.TextView
+	switcher    *tview.TextView
+	compact     bool
+	current     int
+}
+
+// NewResponsiveLayout wraps the full layout. It takes over the app's before draw function to
+// notice resizes.
+func NewResponsiveLayout(app *tview.Application, full tview.Primitive, panes []CompactPane) *ResponsiveLayout {
+	r := &ResponsiveLayout{
+		Flex:        tview.NewFlex().SetDirection(tview.FlexRow),
+		app:         app,
+		full:        full,
+		panes:       panes,
+		breadcrumbs: tview.NewTextView().SetDynamicColors(true),
+		switcher:    tview.NewTextView().SetDynamicColors(true),
+	}
+	r.Flex.SetBackgroundColor(tcell.ColorDefault)
+	r.breadcrumbs.SetBackgroundColor(tcell.ColorDefault)
+	r.switcher.SetBackgroundColor(tcell.ColorDefault)
+	r.rebuild()
+
+	app.SetBeforeDrawFunc(func(screen tcell.Screen) bool {
+		r.update(screen.Size())
+		return false
+	})
+	return r
+}
+
+// IsCompact reports whether one pane at a time is shown
+func (r *ResponsiveLayout) IsCompact() bool {
+	return r.compact
+}
+
+// update switches modes on resize and, in compact mode, to the pane holding the focus
+func (r *ResponsiveLayout) update(width, height int) {
+	threshold := config.Current.CompactWidth
+	compact := threshold > 0 && (width < threshold || height < COMPACT_MIN_HEIGHT)
+	changed := compact != r.compact
+	if changed {
+		log.Printf("[COMPACT] Terminal is %dx%d, compact mode %v", width, height, compact)
+		r.compact = compact
+	}
+
+	if r.compact {
+		if focused := r.focusedPane(); focused >= 0 && focused != r.current {
+			r.current = focused
+			changed = true
+		}
+		r.renderBars()
+	}
+	if changed {
+		r.rebuild()
+	}
+}
+
+func (r *ResponsiveLayout) focusedPane() int {
+	for i, pane := range r.panes {
+		if pane.View.HasFocus() {
+			return i
+		}
+	}
+	return -1
+}
+
+func (r *ResponsiveLayout) rebuild() {
+	r.Flex.Clear()
+	if !r.compact || len(r.panes) == 0 {
+		r.Flex.AddItem(r.full, 0, 1, true)
+		return
+	}
+	r.Flex.
+		AddItem(r.breadcrumbs, 1, 0, false).
+		AddItem(r.panes[r.current].View, 0, 1, true).
+		AddItem(r.switcher, 1, 0, false)
+}
+
+func (r *ResponsiveLayout) renderBars() {
+	current := r.panes[r.current]
+
+	// One crumb per level up to the current pane, taken from the first pane of each level
+	var crumbs []string
+	for level := 0; level <= current.Level; level++ {
+		for _, pane := range r.panes {
+			if pane.Level != level || pane.Crumb == nil {
+				continue
+			}
+			if crumb := pane.Crumb(); crumb != "" {
+				crumbs = append(crumbs, tview.Escape(crumb))
+			}
+			break
+		}
+	}
+	if len(crumbs) > 0 {
+		crumbs[len(crumbs)-1] = "[::b]" + crumbs[len(crumbs)-1] + "[::-]"
+	}
+	r.breadcrumbs.SetText(" " + strings.Join(crumbs, BREADCRUMB_SEPARATOR))
+
+	var tabs []string
+	for i, pane := range r.panes {
+		if i == r.current {
+			tabs = append(tabs, fmt.Sprintf("[accenttext:accent] %s %s [-:-]", pane.Key, pane.Name))
+		} else {
+			tabs = append(tabs, fmt.Sprintf(" [key]%s[-] %s ", pane.Key, pane.Name))
+		}
+	}
+	r.switcher.SetText(strings.Join(tabs, "[muted]│[-]"))
+}
+
+// Show switches to a pane and focuses it
+func (r *ResponsiveLayout) Show(index int) {
+	if index < 0 || index >= len(r.panes) {
+		return
+	}
+	r.current = index
+	r.rebuild()
+	r.app.SetFocus(r.panes[index].View)
+}
+
+// Cycle switches to the next pane, or the previous one with a negative step
+func (r *ResponsiveLayout) Cycle(step int) {
+	r.Show((r.current + step + len(r.panes)) % len(r.panes))
+}
+
+// Back switches to the first pane of the level above the current one. Reports whether there was one.
+func (r *ResponsiveLayout) Back() bool {
+	level := r.panes[r.current].Level
+	for target := level - 1; target >= 0; target-- {
+		for i, pane := range r.panes {
+			if pane.Level == target {
+				r.Show(i)
+				return true
+			}
+		}
+	}
+	return false
+}
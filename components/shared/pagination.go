@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"simple-git-terminal/state"
 	"simple-git-terminal/support"
+	"simple-git-terminal/theme"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
@@ -47,7 +48,7 @@ func NewPaginationComponent(currentPage int) *tview.Flex {
 	// Add "First" button to go to the first page
 	if currentPage > 1 {
 		firstButton := tview.NewButton("<<")
-		firstButton.SetLabelColor(tcell.ColorGrey).
+		firstButton.SetLabelColor(theme.Current.Muted).
 			SetStyle(tcell.StyleDefault.Background(tcell.ColorDefault)).
 			SetSelectedFunc(func() {
 				// Update the page in the global state
@@ -60,7 +61,7 @@ func NewPaginationComponent(currentPage int) *tview.Flex {
 
 	// Add "Previous" button to go to the previous page
 	if currentPage > 1 {
-		prevButton := tview.NewButton("<").SetLabelColor(tcell.ColorGrey).
+		prevButton := tview.NewButton("<").SetLabelColor(theme.Current.Muted).
 			SetStyle(tcell.StyleDefault.Background(tcell.ColorDefault)).
 			SetSelectedFunc(func() {
 				// Update the page in the global state
@@ -82,10 +83,10 @@ func NewPaginationComponent(currentPage int) *tview.Flex {
 
 		// Highlight the current page
 		if currentPage == displayPage {
-			button.SetLabelColor(tcell.ColorBlack).
-				SetStyle(tcell.StyleDefault.Background(tcell.ColorDarkGreen))
+			button.SetLabelColor(theme.Current.AccentText).
+				SetStyle(tcell.StyleDefault.Background(theme.Current.Success))
 		} else {
-			button.SetLabelColor(tcell.ColorGrey).
+			button.SetLabelColor(theme.Current.Muted).
 				SetStyle(tcell.StyleDefault.Background(tcell.ColorDefault))
 		}
 
@@ -95,7 +96,7 @@ func NewPaginationComponent(currentPage int) *tview.Flex {
 	// Add "Next" button to go to the next page
 	if currentPage < totalPages-1 {
 		nextButton := tview.NewButton(">").
-			SetLabelColor(tcell.ColorGrey).
+			SetLabelColor(theme.Current.Muted).
 			SetStyle(tcell.StyleDefault.Background(tcell.ColorDefault)).
 			SetSelectedFunc(func() {
 				// Update the page in the global state
@@ -109,7 +110,7 @@ func NewPaginationComponent(currentPage int) *tview.Flex {
 	// Add "Last" button to go to the last page
 	if currentPage < totalPages-1 {
 		lastButton := tview.NewButton(">>").
-			SetLabelColor(tcell.ColorGrey).
+			SetLabelColor(theme.Current.Muted).
 			SetStyle(tcell.StyleDefault.Background(tcell.ColorDefault)).
 			SetSelectedFunc(func() {
 				// Update the page in the global state
@@ -123,7 +124,7 @@ func NewPaginationComponent(currentPage int) *tview.Flex {
 	// Meta information display at the bottom of the Flex
 	metaText := fmt.Sprintf("Total Items: %d | Items Per Page: %d | Page %d/%d", totalItems, itemsPerPage, currentPage, totalPages) // 1-indexed
 	metaInfo := tview.NewTextView().
-		SetText("[grey]" + metaText + "[-]").
+		SetText("[muted]" + metaText + "[-]").
 		SetTextAlign(tview.AlignCenter).
 		SetDynamicColors(true).
 		SetWordWrap(true).
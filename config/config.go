@@ -0,0 +1,181 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+const (
+	AppDirName     = "bbpr"
+	ConfigFileName = "config.json"
+
+	DiffBackendAPI   = "api"   // Always use the Bitbucket diff endpoints
+	DiffBackendLocal = "local" // Use the local clone, fall back to the API when commits are missing
+
+	DesktopNotifyNone   = ""            // Only show notifications inside bbpr
+	DesktopNotifySend   = "notify-send" // libnotify, most Linux desktops
+	DesktopNotifyOSC9   = "osc9"        // iTerm2, WezTerm, Windows Terminal, kitty
+	DesktopNotifyOSC777 = "osc777"      // urxvt, foot, Ghostty and VTE based terminals
+
+	ProviderCloud      = "cloud"      // bitbucket.org
+	ProviderDataCenter = "datacenter" // Bitbucket Server and Data Center
+
+	CredentialStoreAuto      = ""          // Keyring when available, else the encrypted file with a passphrase, else a plain file
+	CredentialStoreKeyring   = "keyring"   // macOS Keychain or the Secret Service (secret-tool) on Linux
+	CredentialStoreEncrypted = "encrypted" // File encrypted with BBPR_CREDENTIALS_PASSPHRASE
+	CredentialStoreFile      = "file"      // Plain file only readable by the user
+
+	RoleAuthor      = "author"
+	RoleReviewer    = "reviewer"
+	RoleParticipant = "participant"
+)
+
+// Config is the user configuration stored as JSON in the user config directory
+type Config struct {
+	DiffBackend     string              `json:"diff_backend"`
+	Notifications   NotificationsConfig `json:"notifications"`
+	Views           []SavedView         `json:"views"`
+	PRList          PRListConfig        `json:"pr_list"`
+	Dashboard       DashboardConfig     `json:"dashboard"`
+	Provider        ProviderConfig      `json:"provider"`
+	CredentialStore string              `json:"credential_store"` // Where `bbpr auth login` keeps credentials, one of the CredentialStore* values
+	Theme           string              `json:"theme"`            // dark, light, high-contrast or a custom theme, empty to follow the terminal
+	CompactWidth    int                 `json:"compact_width"`    // Terminals narrower than this show one pane at a time, 0 never
+}
+
+// ProviderConfig selects the Bitbucket flavour. By default it follows the host of the origin remote.
+type ProviderConfig struct {
+	Type    string `json:"type"`     // One of the Provider* values, empty to detect from the remote
+	BaseURL string `json:"base_url"` // Data Center server URL, e.g. https://bitbucket.example.com
+}
+
+// DashboardConfig selects the repositories of dashboard mode (-mode dashboard)
+type DashboardConfig struct {
+	Repos              []string `json:"repos"`                // "workspace/repo" entries
+	Workspace          string   `json:"workspace"`            // Also include the recently updated repositories of this workspace
+	WorkspaceRepoLimit int      `json:"workspace_repo_limit"` // How many repositories of the workspace are queried, PRs I review beyond them are missed
+}
+
+// PRListConfig holds the columns of the PR list, in display order, and how it is sorted
+type PRListConfig struct {
+	Columns []string `json:"columns"` // Column IDs, e.g. id, title, author, branches, updated
+	Sort    string   `json:"sort"`    // Column ID or API sort field, "-" prefix for descending
+}
+
+// SavedView is a named PR list filter preset, shown as a tab above the PR list
+type SavedView struct {
+	Name        string   `json:"name"`
+	States      []string `json:"states,omitempty"`      // open, merged, declined
+	Role        string   `json:"role,omitempty"`        // One of the Role* values, empty for anyone
+	Destination string   `json:"destination,omitempty"` // Destination branch name
+	Query       string   `json:"query,omitempty"`       // Search bar query syntax
+	Sort        string   `json:"sort,omitempty"`        // API sort field or PR list column ID, e.g. -updated_on
+	OpenTasks   bool     `json:"open_tasks,omitempty"`  // Only my PRs with open tasks
+}
+
+// NotificationsConfig controls the background poller and which events it reports
+type NotificationsConfig struct {
+	Enabled         bool   `json:"enabled"`
+	IntervalSeconds int    `json:"interval_seconds"`
+	ReviewRequests  bool   `json:"review_requests"` // New PRs where I am a reviewer
+	Comments        bool   `json:"comments"`        // New comments on my PRs
+	Approvals       bool   `json:"approvals"`       // New approvals and change requests on my PRs
+	Pipelines       bool   `json:"pipelines"`       // Pipelines I triggered finishing
+	Desktop         string `json:"desktop"`         // One of the DesktopNotify* values
+}
+
+var Current = defaultConfig()
+
+func defaultConfig() *Config {
+	return &Config{
+		DiffBackend:  DiffBackendAPI,
+		CompactWidth: 120,
+		Notifications: NotificationsConfig{
+			Enabled:         true,
+			IntervalSeconds: 60,
+			ReviewRequests:  true,
+			Comments:        true,
+			Approvals:       true,
+			Pipelines:       true,
+			Desktop:         DesktopNotifyNone,
+		},
+		Views: defaultViews(),
+		Dashboard: DashboardConfig{
+			WorkspaceRepoLimit: 20,
+		},
+		PRList: PRListConfig{
+			Columns: []string{"id", "author", "state", "branches", "title", "updated", "approvals", "comments"},
+		},
+	}
+}
+
+// defaultViews is used until the config file has a "views" list of its own
+func defaultViews() []SavedView {
+	return []SavedView{
+		{Name: "Needs my review", States: []string{"open"}, Role: RoleReviewer, Sort: "-updated_on"},
+		{Name: "My open PRs", States: []string{"open"}, Role: RoleAuthor, Sort: "-updated_on"},
+	}
+}
+
+// Dir returns the bbpr directory inside the OS user config directory (e.g. ~/.config/bbpr)
+func Dir() string {
+	base, err := os.UserConfigDir()
+	if err != nil {
+		base = "."
+	}
+	return filepath.Join(base, AppDirName)
+}
+
+func Path() string {
+	return filepath.Join(Dir(), ConfigFileName)
+}
+
+// Load reads the config file over the defaults. A missing file is not an error.
+func Load() error {
+	cfg := defaultConfig()
+
+	data, err := os.ReadFile(Path())
+	if errors.Is(err, os.ErrNotExist) {
+		Current = cfg
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read config: %w", err)
+	}
+
+	// Decoding into the default views would merge their fields into the configured ones
+	cfg.Views = nil
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return fmt.Errorf("failed to parse config %s: %w", Path(), err)
+	}
+
+	if cfg.Views == nil {
+		cfg.Views = defaultViews()
+	}
+
+	Current = cfg
+	log.Printf("[CONFIG] Loaded config from %s: %+v", Path(), *Current)
+	return nil
+}
+
+// Save writes the current config back to disk
+func Save() error {
+	if err := os.MkdirAll(Dir(), 0o755); err != nil {
+		return fmt.Errorf("failed to create config dir: %w", err)
+	}
+
+	data, err := json.MarshalIndent(Current, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+
+	return os.WriteFile(Path(), data, 0o644)
+}
+
+func UseLocalDiff() bool {
+	return Current.DiffBackend == DiffBackendLocal
+}
@@ -1,17 +1,14 @@
 package constants
 
-import "github.com/gdamore/tcell/v2"
-
 const (
-	HIGH_CONTRAST_COLOR = tcell.ColorCadetBlue
-	LOW_CONTRAST_COLOR  = tcell.ColorYellow
-	ICON_ACTIVE         = "\uf00c"
-	ICON_SELECTED       = "\u25C8"
-	ICON_DOWN_ARROW     = "\u2193"
-	ICON_SIDE_ARROW     = "\u21AA"
-	ICON_WARNING        = "\u2260"
-	ICON_DECLINED       = "\u274C"
-	ICON_COMMIT         = ""
-	ICON_BRANCH         = ""
-	ICON_BUILD          = "\uf085"
+	ICON_ACTIVE     = "\uf00c"
+	ICON_SELECTED   = "\u25C8"
+	ICON_DOWN_ARROW = "\u2193"
+	ICON_UP_ARROW   = "\u2191"
+	ICON_SIDE_ARROW = "\u21AA"
+	ICON_WARNING    = "\u2260"
+	ICON_DECLINED   = "\u274C"
+	ICON_COMMIT     = ""
+	ICON_BRANCH     = ""
+	ICON_BUILD      = "\uf085"
 )
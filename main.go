@@ -5,7 +5,13 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"simple-git-terminal/apis/provider"
+	"simple-git-terminal/auth"
+	"simple-git-terminal/cli"
+	"simple-git-terminal/config"
 	"simple-git-terminal/state"
+	"simple-git-terminal/theme"
+	"simple-git-terminal/util"
 
 	"github.com/rivo/tview"
 )
@@ -15,10 +21,15 @@ var (
 	mocking   bool
 	workspace string
 	repoSlug  string
+	themeName string
 )
 
 func init() {
-	flag.StringVar(&mode, "mode", "pr", "Mode of the app: 'pipeline' or 'pr'")
+	flag.StringVar(&mode, "mode", "pr", "Mode of the app: 'pipeline', 'pr' or 'dashboard'")
+	flag.StringVar(&util.RemoteOverride.Workspace, "workspace", "", "Bitbucket workspace (or Data Center project key), instead of detecting it from the git remotes")
+	flag.StringVar(&util.RemoteOverride.Repo, "repo", "", "Repository slug, used together with -workspace")
+	flag.StringVar(&auth.SelectedAccount, "account", "", "Account stored by `bbpr auth login` to use instead of the default")
+	flag.StringVar(&themeName, "theme", "", "Color theme: dark, light, high-contrast or a custom theme, instead of the configured one")
 	// Internal
 	flag.BoolVar(&mocking, "mocking", false, "Use mock mode for network calls")
 }
@@ -39,15 +50,55 @@ func main() {
 	log.SetFlags(log.Ldate | log.Ltime | log.Lshortfile)
 	log.Printf("Application started in mode: %s", mode)
 
+	if (util.RemoteOverride.Workspace == "") != (util.RemoteOverride.Repo == "") {
+		fmt.Println("-workspace and -repo must be given together")
+		os.Exit(2)
+	}
+
+	if err := config.Load(); err != nil {
+		log.Printf("[CONFIG] %v, using defaults", err)
+	}
+	if err := provider.Setup(config.Current.Provider); err != nil {
+		fmt.Println(err)
+		log.Fatalf("[PROVIDER] %v", err)
+	}
+
+	if themeName == "" {
+		themeName = config.Current.Theme
+	}
+	if err := theme.Load(themeName); err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+
+	// Subcommands such as `bbpr pr list` run without the TUI
+	if flag.NArg() > 0 {
+		os.Exit(cli.Run(flag.Args()))
+	}
+
+	if mode != "pr" && !provider.IsCloud() {
+		fmt.Printf("%s mode is only available on Bitbucket Cloud\n", mode)
+		log.Fatalf("[PROVIDER] %s mode needs Bitbucket Cloud", mode)
+	}
+
+	if !auth.HasCredentials() {
+		fmt.Printf("Not logged in, run `bbpr auth login` or set %s\n", auth.EnvToken)
+		os.Exit(1)
+	}
+
 	var app *tview.Application
 
 	switch mode {
 	case "pr":
 		app = CreateMainApp()
+	case "dashboard":
+		// PRs across the configured repositories
+		state.DashboardMode = true
+		app = CreateMainApp()
 	case "pipeline":
 		app = CreateMainAppForBBPipeline()
 	default:
-		log.Fatalf("Unknown mode: %s. Use 'pipeline' or 'pr'", mode)
+		log.Fatalf("Unknown mode: %s. Use 'pipeline', 'pr' or 'dashboard'", mode)
 	}
 
 	if os.Getenv("BBPR_APP_ENV") == "development" {
@@ -3,8 +3,9 @@ package main
 import (
 	"fmt"
 	"log"
-	"simple-git-terminal/apis/bitbucket"
+	"simple-git-terminal/apis/provider"
 	"simple-git-terminal/components/pr"
+	"simple-git-terminal/config"
 	"simple-git-terminal/custom/borders"
 	"simple-git-terminal/state"
 	"simple-git-terminal/support"
@@ -17,15 +18,34 @@ import (
 func CreateMainApp() *tview.Application {
 	borders.CustomizeBorders()
 	app := tview.NewApplication()
-	workspace, repoSlug, _ = util.GetRepoAndWorkspace()
+	remote, err := util.GetRemote()
+	workspace, repoSlug = remote.Workspace, remote.Repo
 	log.Printf("Loading workspace - %s and repo - %s ....", workspace, repoSlug)
 	fmt.Printf("Loading workspace - %s and repo - %s ....", workspace, repoSlug)
 
 	if (workspace == "") || (repoSlug == "") {
-		log.Fatalf("Not a bitbucket Workspace")
-		fmt.Printf("Not a bitbucket Workspace")
+		// The dashboard lists configured repositories and works outside a clone
+		if !state.DashboardMode {
+			fmt.Printf("\nNot a bitbucket Workspace: %v\n", err)
+			log.Fatalf("Not a bitbucket Workspace: %v", err)
+		}
+	} else {
+		state.HomeRepo = state.RepoContext{Workspace: workspace, Repo: repoSlug}
+		// Remotes given by -workspace and -repo have no name
+		if remote.Name != "" {
+			state.HomeRemote = remote.Name
+		}
+	}
+	if state.DashboardMode {
+		if _, err := pr.DashboardSource(); err != nil {
+			fmt.Println(err)
+			log.Fatalf("[DASHBOARD] %v", err)
+		}
+	}
+	currentUser, err := provider.Current().FetchCurrentUser()
+	if err != nil {
+		log.Printf("[USER] %v", err)
 	}
-	currentUser := bitbucket.FetchCurrentUser()
 	state.SetCurrentUser(currentUser)
 
 	state.SetWorkspaceRepo(workspace, repoSlug)
@@ -38,7 +58,7 @@ func CreateMainApp() *tview.Application {
 	prStatusFilterFlex.AddItem(pr.CreatePRStatusFilterView(), 0, 1, false)
 
 	// PR LIST UI
-	prListFlex := support.CreateFlexComponent("Pull Requests   [green]p|P").
+	prListFlex := support.CreateFlexComponent("Pull Requests   [key]p|P").
 		SetDirection(tview.FlexRow)
 
 	prList := tview.NewTable().
@@ -49,9 +69,11 @@ func CreateMainApp() *tview.Application {
 
 	prList.SetBackgroundColor(tcell.ColorDefault)
 
-	prListSearchBar := support.CreateInputFieldComponent("  Search PR [green]s", " type something....")
+	prListSearchBar := support.CreateInputFieldComponent(pr.SEARCH_TITLE, "")
+	pr.SetupSearchBar(prListSearchBar)
 
 	prListFlex.
+		AddItem(pr.CreateSavedViewTabs(), 1, 0, false).
 		AddItem(prList, 0, 1, true)
 
 	leftFullFlex := tview.NewFlex().
@@ -65,44 +87,31 @@ func CreateMainApp() *tview.Application {
 
 		// Description and Activity
 
-	activityDetails := support.CreateFlexComponent("Activities [green]a|A")
+	activityDetails := support.CreateFlexComponent("Activities [key]a|A")
 
 	// MIDDLE
 	rightPanelHeader := support.CreateTextviewComponent("", true)
-	prDetails := support.CreateTextviewComponent("Description [green]d|D", true)
-
-	middleFullFlex := tview.NewFlex().
-		SetDirection(tview.FlexRow)
-	middleFullFlex.SetBackgroundColor(tcell.ColorDefault)
-
-	middleFullFlex.AddItem(rightPanelHeader, 0, 2, false).
-		AddItem(prDetails, 0, 4, false).
-		AddItem(activityDetails, 0, 14, false)
-
-		// RIGHT
-
-	diffStatDetails := support.CreateFlexComponent("Diff Tree [green]t|T")
-	diffDetails := support.CreateFlexComponent("Diff Content [green]c|C")
+	prDetails := support.CreateTextviewComponent("Description [key]d|D", true)
 
-	rightFullFlex := tview.NewFlex()
+	// RIGHT
 
-	rightFullFlex.SetBackgroundColor(tcell.ColorDefault)
-	rightFullFlex.AddItem(diffStatDetails, 0, 1, false).
-		AddItem(diffDetails, 0, 1, false)
+	diffStatDetails := support.CreateFlexComponent("Diff Tree [key]t|T")
+	diffDetails := support.CreateFlexComponent("Diff Content [key]c|C")
 
-	mainFlexWrapper := tview.NewFlex()
-	mainFlexWrapper.SetBackgroundColor(tcell.ColorDefault)
-	mainFlexWrapper.AddItem(leftFullFlex, 0, 1, true).
-		AddItem(middleFullFlex, 0, 1, false).
-		AddItem(rightFullFlex, 0, 2, false)
+	// The columns are sized by the saved layout, small terminals show one pane at a time
+	layoutFlex := pr.NewMainLayout(leftFullFlex, rightPanelHeader, prDetails, activityDetails, diffStatDetails, diffDetails)
+	mainFlexWrapper := pr.NewResponsiveLayout(app, layoutFlex, leftFullFlex, prDetails, activityDetails, diffStatDetails, diffDetails)
 
 	state.InitializeViews(app, mainFlexWrapper, prListFlex, prList, prDetails, activityDetails, diffDetails, diffStatDetails, prStatusFilterFlex, rightPanelHeader, prListSearchBar, paginationFlex)
+	pr.SetPRSort(config.Current.PRList.Sort)
 	pr.PopulatePRList(prList)
 
 	// Key Bindings
 	pr.SetupKeyBindings(func() {
 		updateFilter() // TODO: We can do this better in organizing
 	})
+	pr.StartNotifications()
+	pr.StartViewCounts()
 
 	app.SetRoot(mainFlexWrapper, true).EnableMouse(true)
 
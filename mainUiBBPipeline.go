@@ -19,15 +19,19 @@ import (
 func CreateMainAppForBBPipeline() *tview.Application {
 	borders.CustomizeBorders()
 	app := tview.NewApplication()
-	workspace, repoSlug, _ = util.GetRepoAndWorkspace()
+	var err error
+	workspace, repoSlug, err = util.GetRepoAndWorkspace()
 	log.Printf("Loading workspace - %s and repo - %s ....", workspace, repoSlug)
 	fmt.Printf("Loading workspace - %s and repo - %s ....", workspace, repoSlug)
 
 	if (workspace == "") || (repoSlug == "") {
-		log.Fatalf("Not a bitbucket Workspace")
-		fmt.Printf("Not a bitbucket Workspace")
+		fmt.Printf("\nNot a bitbucket Workspace: %v\n", err)
+		log.Fatalf("Not a bitbucket Workspace: %v", err)
+	}
+	currentUser, err := bitbucket.FetchCurrentUser()
+	if err != nil {
+		log.Printf("[USER] %v", err)
 	}
-	currentUser := bitbucket.FetchCurrentUser()
 	state.SetCurrentUser(currentUser)
 
 	state.SetWorkspaceRepo(workspace, repoSlug)
@@ -81,15 +85,19 @@ func CreateMainAppForBBPipeline() *tview.Application {
 	middleFullFlex.AddItem(stepsWrapper, 0, 2, false)
 	middleFullFlex.AddItem(stepWrapper, 0, 4, false)
 
-	mainFlexWrapper := tview.NewFlex()
-	mainFlexWrapper.SetBackgroundColor(tcell.ColorDefault)
-	mainFlexWrapper.AddItem(leftFullFlex, 0, 1, true).
+	fullFlex := tview.NewFlex()
+	fullFlex.SetBackgroundColor(tcell.ColorDefault)
+	fullFlex.AddItem(leftFullFlex, 0, 1, true).
 		AddItem(middleFullFlex, 0, 3, false)
 
+	// Small terminals show one pane at a time
+	mainFlexWrapper := pipeline.NewResponsiveLayout(app, fullFlex, leftFullFlex, debugView, steps, stepCommandsView, step, stepCommandLogView)
+
 	state.InitializePipelineViews(app, mainFlexWrapper, ppList, debugView, steps, step, stepCommandsView, stepCommandLogView, nil, nil, nil)
 	pipeline.PopulatePipelineList()
 
 	pipeline.SetupKeyBindings()
+	pipeline.StartNotifications()
 	app.SetRoot(mainFlexWrapper, true).EnableMouse(true)
 
 	return app
@@ -0,0 +1,53 @@
+package notifications
+
+import (
+	"fmt"
+	"log"
+	"os/exec"
+	"simple-git-terminal/config"
+	"simple-git-terminal/util"
+	"strings"
+)
+
+const appName = "bbpr"
+
+// sendDesktopNotification forwards an event outside of bbpr when configured. OSC escapes are written
+// straight to the terminal, which shows them as a system notification without touching the screen.
+func sendDesktopNotification(event Event) {
+	title := event.Title
+	body := firstLine(event.Detail)
+
+	switch config.Current.Notifications.Desktop {
+	case config.DesktopNotifySend:
+		if err := exec.Command("notify-send", "--app-name", appName, title, body).Run(); err != nil {
+			log.Printf("[NOTIFY] notify-send failed: %v", err)
+		}
+	case config.DesktopNotifyOSC9:
+		writeToTerminal(fmt.Sprintf("\x1b]9;%s: %s\x07", sanitize(title), sanitize(body)))
+	case config.DesktopNotifyOSC777:
+		writeToTerminal(fmt.Sprintf("\x1b]777;notify;%s;%s\x07", sanitize(title), sanitize(body)))
+	}
+}
+
+func writeToTerminal(sequence string) {
+	if err := util.WriteToTerminal(sequence); err != nil {
+		log.Printf("[NOTIFY] %v", err)
+	}
+}
+
+// sanitize strips control characters and the ";" separator used by OSC 777
+func sanitize(text string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f {
+			return ' '
+		}
+		if r == ';' {
+			return ','
+		}
+		return r
+	}, text)
+}
+
+func firstLine(text string) string {
+	return strings.SplitN(strings.TrimSpace(text), "\n", 2)[0]
+}
@@ -0,0 +1,324 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"simple-git-terminal/apis/bitbucket"
+	"simple-git-terminal/config"
+	"simple-git-terminal/state"
+	"simple-git-terminal/types"
+	"sync"
+	"time"
+)
+
+const (
+	KindReviewRequest = "review-request"
+	KindComment       = "comment"
+	KindApproval      = "approval"
+	KindPipeline      = "pipeline"
+
+	maxEvents   = 100 // Oldest events are dropped beyond this
+	minInterval = 15 * time.Second
+	prLimit     = 50 // Open PRs looked at per poll
+	buildLimit  = 30 // Recent pipelines looked at per poll
+)
+
+// Event is a single notification shown in the notifications panel
+type Event struct {
+	Kind    string
+	Title   string
+	Detail  string
+	Time    time.Time
+	PR      *types.PR // Set for PR related events
+	Success bool      // For pipelines, whether the run passed
+}
+
+// poller remembers what it has already seen so only changes since the previous poll are reported.
+// The first poll only fills the seen sets, nothing that existed before bbpr started is reported.
+// The seen sets only keep what the last poll returned, so they don't grow while bbpr runs.
+type poller struct {
+	repo           state.RepoContext
+	seenPRs        map[int]bool
+	seenActivities map[int]map[string]bool // Activity keys per open PR
+	runningBuilds  map[string]bool
+	seenBuilds     map[string]bool
+	initialized    bool
+}
+
+var (
+	events   []Event
+	unread   int
+	mutex    sync.Mutex
+	onChange func()
+)
+
+// Start polls repo in the background until ctx is cancelled. onEvents is called after new events were recorded.
+func Start(ctx context.Context, repo state.RepoContext, onEvents func()) {
+	cfg := config.Current.Notifications
+	if !cfg.Enabled {
+		log.Printf("[NOTIFY] Notifications disabled in config")
+		return
+	}
+
+	mutex.Lock()
+	onChange = onEvents
+	mutex.Unlock()
+
+	interval := max(time.Duration(cfg.IntervalSeconds)*time.Second, minInterval)
+	p := &poller{
+		repo:           repo,
+		seenPRs:        make(map[int]bool),
+		seenActivities: make(map[int]map[string]bool),
+		runningBuilds:  make(map[string]bool),
+		seenBuilds:     make(map[string]bool),
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		p.poll(cfg)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				p.poll(cfg)
+			}
+		}
+	}()
+}
+
+// Events returns the recorded events, newest first
+func Events() []Event {
+	mutex.Lock()
+	defer mutex.Unlock()
+	return append([]Event{}, events...)
+}
+
+func UnreadCount() int {
+	mutex.Lock()
+	defer mutex.Unlock()
+	return unread
+}
+
+func MarkAllRead() {
+	mutex.Lock()
+	defer mutex.Unlock()
+	unread = 0
+}
+
+func record(newEvents []Event) {
+	if len(newEvents) == 0 {
+		return
+	}
+
+	mutex.Lock()
+	events = append(newEvents, events...)
+	if len(events) > maxEvents {
+		events = events[:maxEvents]
+	}
+	unread += len(newEvents)
+	callback := onChange
+	mutex.Unlock()
+
+	for _, event := range newEvents {
+		log.Printf("[NOTIFY] %s: %s", event.Title, event.Detail)
+		sendDesktopNotification(event)
+	}
+	if callback != nil {
+		callback()
+	}
+}
+
+func (p *poller) poll(cfg config.NotificationsConfig) {
+	me := state.CurrentUser
+	if me == nil || me.UUID == "" {
+		return
+	}
+
+	var found []Event
+	if cfg.ReviewRequests {
+		found = append(found, p.pollReviewRequests(me)...)
+	}
+	if cfg.Comments || cfg.Approvals {
+		found = append(found, p.pollMyPRs(me, cfg)...)
+	}
+	if cfg.Pipelines {
+		found = append(found, p.pollPipelines(me)...)
+	}
+
+	if p.initialized {
+		record(found)
+	}
+	p.initialized = true
+}
+
+func (p *poller) pollReviewRequests(me *types.User) []Event {
+	prs, err := bitbucket.SearchPRs(p.repo, fmt.Sprintf("reviewers.uuid=\"%s\" AND state=\"OPEN\"", me.UUID), prLimit)
+	if err != nil {
+		log.Printf("[NOTIFY] %v", err)
+		return nil
+	}
+
+	var found []Event
+	open := make(map[int]bool)
+	for i := range prs {
+		pr := prs[i]
+		open[pr.ID] = true
+		if p.seenPRs[pr.ID] {
+			continue
+		}
+		p.seenPRs[pr.ID] = true
+		found = append(found, Event{
+			Kind:   KindReviewRequest,
+			Title:  fmt.Sprintf("Review requested on #%d", pr.ID),
+			Detail: fmt.Sprintf("%s by %s", pr.Title, pr.Author.DisplayName),
+			Time:   time.Now(),
+			PR:     &pr,
+		})
+	}
+	if len(prs) < prLimit {
+		forgetClosed(p.seenPRs, open)
+	}
+	return found
+}
+
+// pollMyPRs reports comments, approvals and change requests by others on my open PRs
+func (p *poller) pollMyPRs(me *types.User, cfg config.NotificationsConfig) []Event {
+	prs, err := bitbucket.SearchPRs(p.repo, fmt.Sprintf("author.uuid=\"%s\" AND state=\"OPEN\"", me.UUID), prLimit)
+	if err != nil {
+		log.Printf("[NOTIFY] %v", err)
+		return nil
+	}
+
+	var found []Event
+	open := make(map[int]bool)
+	for i := range prs {
+		pr := prs[i]
+		open[pr.ID] = true
+		activities, err := bitbucket.FetchRecentActivities(p.repo, pr.ID)
+		if err != nil {
+			log.Printf("[NOTIFY] %v", err)
+			continue
+		}
+
+		// Activities that dropped out of the recent ones don't come back, only the current keys are kept
+		seen := p.seenActivities[pr.ID]
+		current := make(map[string]bool)
+		p.seenActivities[pr.ID] = current
+
+		for _, activity := range activities {
+			var event *Event
+			var key string
+
+			switch {
+			case activity.Comment.ID != 0:
+				key = fmt.Sprintf("comment-%d", activity.Comment.ID)
+				if cfg.Comments && activity.Comment.User.UUID != me.UUID {
+					event = &Event{
+						Kind:   KindComment,
+						Title:  fmt.Sprintf("%s commented on #%d", activity.Comment.User.DisplayName, pr.ID),
+						Detail: activity.Comment.Content.Raw,
+					}
+				}
+			case activity.Approval.User.UUID != "":
+				key = fmt.Sprintf("approval-%s-%s", activity.Approval.User.UUID, activity.Approval.Date)
+				if cfg.Approvals && activity.Approval.User.UUID != me.UUID {
+					event = &Event{
+						Kind:   KindApproval,
+						Title:  fmt.Sprintf("%s approved #%d", activity.Approval.User.DisplayName, pr.ID),
+						Detail: pr.Title,
+					}
+				}
+			case activity.ChangesRequested.User.UUID != "":
+				key = fmt.Sprintf("changes-%s-%s", activity.ChangesRequested.User.UUID, activity.ChangesRequested.Date)
+				if cfg.Approvals && activity.ChangesRequested.User.UUID != me.UUID {
+					event = &Event{
+						Kind:   KindApproval,
+						Title:  fmt.Sprintf("%s requested changes on #%d", activity.ChangesRequested.User.DisplayName, pr.ID),
+						Detail: pr.Title,
+					}
+				}
+			default:
+				continue
+			}
+
+			current[key] = true
+			if seen[key] {
+				continue
+			}
+			if event != nil {
+				event.Time = time.Now()
+				event.PR = &pr
+				found = append(found, *event)
+			}
+		}
+	}
+	if len(prs) < prLimit {
+		forgetClosed(p.seenActivities, open)
+	}
+	return found
+}
+
+// forgetClosed drops the entries of PRs that are no longer open
+func forgetClosed[V any](seen map[int]V, open map[int]bool) {
+	for id := range seen {
+		if !open[id] {
+			delete(seen, id)
+		}
+	}
+}
+
+// pollPipelines reports pipelines I triggered that finished since they were last seen running
+func (p *poller) pollPipelines(me *types.User) []Event {
+	pipelines, err := bitbucket.FetchRecentPipelines(p.repo, buildLimit)
+	if err != nil {
+		log.Printf("[NOTIFY] %v", err)
+		return nil
+	}
+
+	var found []Event
+	// Older pipelines don't return to the recent ones, only the current ones are kept
+	seenBuilds := p.seenBuilds
+	p.seenBuilds = make(map[string]bool)
+	for _, pipeline := range pipelines {
+		if pipeline.Creator.UUID != me.UUID {
+			continue
+		}
+
+		running := pipeline.State.Name.NeedsTracking()
+		wasRunning := p.runningBuilds[pipeline.UUID]
+		// Pipelines started and finished between two polls were never seen running
+		firstSeen := !seenBuilds[pipeline.UUID] && p.initialized
+		p.seenBuilds[pipeline.UUID] = true
+
+		if running {
+			p.runningBuilds[pipeline.UUID] = true
+			continue
+		}
+		if !wasRunning && !firstSeen {
+			continue
+		}
+		delete(p.runningBuilds, pipeline.UUID)
+
+		result := pipeline.State.Result.Name
+		if result == "" {
+			result = pipeline.State.Name
+		}
+		found = append(found, Event{
+			Kind:    KindPipeline,
+			Title:   fmt.Sprintf("Pipeline #%d %s", pipeline.BuildNumber, result),
+			Detail:  pipeline.Target.RefName,
+			Time:    time.Now(),
+			Success: result.Successful() || result.Passed(),
+		})
+	}
+	for uuid := range p.runningBuilds {
+		if !p.seenBuilds[uuid] {
+			delete(p.runningBuilds, uuid)
+		}
+	}
+	return found
+}
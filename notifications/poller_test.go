@@ -0,0 +1,20 @@
+package notifications
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestForgetClosed(t *testing.T) {
+	seen := map[int]map[string]bool{
+		1: {"comment-1": true},
+		2: {"comment-2": true},
+		3: {},
+	}
+	forgetClosed(seen, map[int]bool{1: true, 3: true, 4: true})
+
+	want := map[int]map[string]bool{1: {"comment-1": true}, 3: {}}
+	if !reflect.DeepEqual(seen, want) {
+		t.Errorf("seen = %v, want %v", seen, want)
+	}
+}
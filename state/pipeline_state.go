@@ -136,3 +136,8 @@ func SetPipelineStatusFilter(filterKey string, isChecked bool) {
 	}
 	log.Printf("Pipeline filter updated: %+v", PipelineStatusFilter)
 }
+
+// IsNetworkMockMode is safe to call before the pipeline views exist, e.g. in PR mode or from the CLI
+func IsNetworkMockMode() bool {
+	return PipelineUIState != nil && PipelineUIState.IsNetworkMockMode
+}
@@ -0,0 +1,54 @@
+package state
+
+import (
+	"simple-git-terminal/types"
+	"strings"
+)
+
+// RepoContext identifies a Bitbucket repository
+type RepoContext struct {
+	Workspace string
+	Repo      string
+}
+
+func (r RepoContext) FullName() string {
+	return r.Workspace + "/" + r.Repo
+}
+
+// ParseRepoContext parses "workspace/repo"
+func ParseRepoContext(fullName string) (RepoContext, bool) {
+	workspace, repo, ok := strings.Cut(fullName, "/")
+	if !ok || workspace == "" || repo == "" {
+		return RepoContext{}, false
+	}
+	return RepoContext{Workspace: workspace, Repo: repo}, true
+}
+
+// HomeRepo is the repository of the local clone bbpr was started in, empty outside a clone
+var HomeRepo RepoContext
+
+// HomeRemote is the git remote HomeRepo was resolved from, which local git operations fetch from
+var HomeRemote = "origin"
+
+// DashboardMode is set when the PR list spans several repositories. API calls about a PR then go to
+// RepoOfPR instead of CurrentRepo.
+var DashboardMode bool
+
+// CurrentRepo is the repository bbpr was started for, from the git remotes or -workspace and -repo
+func CurrentRepo() RepoContext {
+	return RepoContext{Workspace: Workspace, Repo: Repo}
+}
+
+// RepoOfPR is the repository a PR belongs to, CurrentRepo when the PR does not say
+func RepoOfPR(pr *types.PR) RepoContext {
+	if repo, ok := ParseRepoContext(pr.Destination.Repository.FullName); ok {
+		return repo
+	}
+	return CurrentRepo()
+}
+
+// IsHomeRepo reports whether repo is the repository of the local clone, so local git operations apply
+// to its PRs
+func IsHomeRepo(repo RepoContext) bool {
+	return HomeRepo.Workspace != "" && strings.EqualFold(HomeRepo.Workspace, repo.Workspace) && strings.EqualFold(HomeRepo.Repo, repo.Repo)
+}
@@ -0,0 +1,35 @@
+package state
+
+import (
+	"simple-git-terminal/types"
+	"testing"
+)
+
+func TestRepoOfPR(t *testing.T) {
+	Workspace, Repo = "home", "repo"
+	defer func() { Workspace, Repo = "", "" }()
+
+	var pr types.PR
+	if got := RepoOfPR(&pr); got != (RepoContext{Workspace: "home", Repo: "repo"}) {
+		t.Errorf("RepoOfPR without repository = %+v, want the current repository", got)
+	}
+	pr.Destination.Repository.FullName = "other/service"
+	if got := RepoOfPR(&pr); got != (RepoContext{Workspace: "other", Repo: "service"}) {
+		t.Errorf("RepoOfPR = %+v, want other/service", got)
+	}
+}
+
+func TestIsHomeRepo(t *testing.T) {
+	defer func() { HomeRepo = RepoContext{} }()
+
+	if IsHomeRepo(RepoContext{}) {
+		t.Error("an empty repository is the home repository outside a clone")
+	}
+	HomeRepo = RepoContext{Workspace: "Home", Repo: "Repo"}
+	if !IsHomeRepo(RepoContext{Workspace: "home", Repo: "repo"}) {
+		t.Error("repository slugs should compare case-insensitively")
+	}
+	if IsHomeRepo(RepoContext{Workspace: "home", Repo: "other"}) {
+		t.Error("home/other is not the home repository")
+	}
+}
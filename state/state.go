@@ -30,7 +30,11 @@ type State struct {
 var GlobalState *State
 var Workspace, Repo string
 var IsSearchMode bool
+var IsModalOpen bool
 var SearchTerm string
+var PRDestinationBranch string // Set by saved views, empty means any branch
+var PRSort string              // API sort field of the PR list, e.g. -updated_on
+var PRLocalSort string         // PR list column sorted in the client when the API cannot sort by it, e.g. -approvals
 var CurrentUser *types.User
 var Pagination *types.Pagination = &types.Pagination{
 	Next:    "",
@@ -86,6 +90,10 @@ func SetIsSearchMode(mode bool) {
 	IsSearchMode = mode
 }
 
+func SetIsModalOpen(open bool) {
+	IsModalOpen = open
+}
+
 func SetSearchTerm(term string) {
 	SearchTerm = term
 }
@@ -95,11 +103,13 @@ func SetPagination(pagination *types.Pagination) {
 }
 
 type PRStatusFilterType struct {
-	Open        bool
-	Merged      bool
-	Declined    bool
-	IAmAuthor   bool
-	IAmReviewer bool
+	Open           bool
+	Merged         bool
+	Declined       bool
+	IAmAuthor      bool
+	IAmReviewer    bool
+	IAmParticipant bool
+	MyOpenTasks    bool // My PRs with open tasks
 }
 
 var PRStatusFilter *PRStatusFilterType
@@ -125,6 +135,10 @@ func SetPRStatusFilter(filterKey string, isChecked bool) {
 		PRStatusFilter.IAmAuthor = isChecked
 	case "iamreviewer":
 		PRStatusFilter.IAmReviewer = isChecked
+	case "iamparticipant":
+		PRStatusFilter.IAmParticipant = isChecked
+	case "myopentasks":
+		PRStatusFilter.MyOpenTasks = isChecked
 	case "all":
 		PRStatusFilter.Open = isChecked
 		PRStatusFilter.Merged = isChecked
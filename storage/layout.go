@@ -0,0 +1,31 @@
+package storage
+
+import (
+	"log"
+	"path/filepath"
+)
+
+// Layout is the arrangement of the PR mode panes, restored on the next start
+type Layout struct {
+	Preset  string         `json:"preset"`
+	Weights map[string]int `json:"weights"` // Flex proportion by pane
+	Hidden  []string       `json:"hidden"`  // Panes collapsed out of the layout
+}
+
+func layoutPath() string {
+	return filepath.Join(Dir(), "layout.json")
+}
+
+// LoadLayout returns the saved layout, nil when there is none
+func LoadLayout() *Layout {
+	var layout *Layout
+	if err := readJSON(layoutPath(), &layout); err != nil {
+		log.Printf("[STORAGE] %v", err)
+		return nil
+	}
+	return layout
+}
+
+func SaveLayout(layout *Layout) error {
+	return writeJSON(layoutPath(), layout)
+}
@@ -0,0 +1,36 @@
+package storage
+
+import (
+	"log"
+	"path/filepath"
+)
+
+// MaxSearchHistory is how many recent search queries are kept
+const MaxSearchHistory = 50
+
+func searchHistoryPath() string {
+	return filepath.Join(Dir(), "search_history.json")
+}
+
+// LoadSearchHistory returns the recent PR search queries, newest first
+func LoadSearchHistory() []string {
+	var history []string
+	if err := readJSON(searchHistoryPath(), &history); err != nil {
+		log.Printf("[STORAGE] %v", err)
+	}
+	return history
+}
+
+// AddSearchHistory moves the query to the front of the history and persists it
+func AddSearchHistory(query string) []string {
+	history := []string{query}
+	for _, previous := range LoadSearchHistory() {
+		if previous != query && len(history) < MaxSearchHistory {
+			history = append(history, previous)
+		}
+	}
+	if err := writeJSON(searchHistoryPath(), history); err != nil {
+		log.Printf("[STORAGE] Failed to save search history: %v", err)
+	}
+	return history
+}
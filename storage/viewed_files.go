@@ -0,0 +1,57 @@
+package storage
+
+import (
+	"log"
+	"path/filepath"
+	"sync"
+)
+
+// ViewedFiles is the review progress of one PR: which files were marked as viewed at which source commit
+type ViewedFiles struct {
+	SourceCommit string          `json:"source_commit"`
+	Files        map[string]bool `json:"files"`
+}
+
+var viewedMutex sync.Mutex
+
+func viewedFilesPath(workspace, repo string) string {
+	return filepath.Join(Dir(), "viewed", workspace, repo+".json")
+}
+
+// loadAllViewedFiles reads the review progress of every PR in the repo, keyed by PR id
+func loadAllViewedFiles(workspace, repo string) map[int]*ViewedFiles {
+	all := make(map[int]*ViewedFiles)
+	if err := readJSON(viewedFilesPath(workspace, repo), &all); err != nil {
+		log.Printf("[STORAGE] %v", err)
+	}
+	return all
+}
+
+// LoadViewedFiles returns the stored review progress of a PR, empty when nothing was marked yet
+func LoadViewedFiles(workspace, repo string, prID int) *ViewedFiles {
+	viewedMutex.Lock()
+	defer viewedMutex.Unlock()
+
+	viewed, ok := loadAllViewedFiles(workspace, repo)[prID]
+	if !ok || viewed == nil {
+		return &ViewedFiles{Files: make(map[string]bool)}
+	}
+	if viewed.Files == nil {
+		viewed.Files = make(map[string]bool)
+	}
+	return viewed
+}
+
+// SaveViewedFiles stores the review progress of a PR next to the other PRs of the repo
+func SaveViewedFiles(workspace, repo string, prID int, viewed *ViewedFiles) error {
+	viewedMutex.Lock()
+	defer viewedMutex.Unlock()
+
+	all := loadAllViewedFiles(workspace, repo)
+	if len(viewed.Files) == 0 {
+		delete(all, prID)
+	} else {
+		all[prID] = viewed
+	}
+	return writeJSON(viewedFilesPath(workspace, repo), all)
+}
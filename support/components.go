@@ -3,25 +3,26 @@ package support
 import (
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
+	"simple-git-terminal/theme"
 )
 
 func CreateCheckBoxComponent(label string, onChange func(bool)) *tview.Checkbox {
 	checkedStyle := tcell.StyleDefault.
 		Background(tcell.ColorDefault).
-		Foreground(tcell.ColorGreen)
+		Foreground(theme.Current.Success)
 
 	uncheckedStyle := tcell.StyleDefault.
 		Background(tcell.ColorDefault)
 
 	activatedStyle := tcell.StyleDefault.
 		Background(tcell.ColorDefault).
-		Foreground(tcell.ColorGreen)
+		Foreground(theme.Current.Success)
 
 	checkbox := tview.NewCheckbox().
 		SetLabel(label).
 		SetLabelColor(tcell.ColorDefault).
-		SetCheckedString("✓[orange] /[-] ").
-		SetUncheckedString("[orange] /[-] ").
+		SetCheckedString("✓[accent] /[-] ").
+		SetUncheckedString("[accent] /[-] ").
 		SetCheckedStyle(checkedStyle).
 		SetUncheckedStyle(uncheckedStyle).
 		SetActivatedStyle(activatedStyle)
@@ -29,7 +30,7 @@ func CreateCheckBoxComponent(label string, onChange func(bool)) *tview.Checkbox
 	checkbox.SetBackgroundColor(tcell.ColorDefault)
 	checkbox.SetChangedFunc(func(checked bool) {
 		if checked {
-			checkbox.SetLabelColor(tcell.ColorGreen)
+			checkbox.SetLabelColor(theme.Current.Success)
 		} else {
 			checkbox.SetLabelColor(tcell.ColorDefault)
 		}
@@ -48,7 +49,7 @@ func CreateFlexComponent(title string) *tview.Flex {
 	flex.SetBorder(true).
 		SetTitleAlign(tview.AlignLeft).
 		SetTitle(title).
-		SetBorderColor(tcell.ColorGrey).
+		SetBorderColor(theme.Current.Border).
 		SetBackgroundColor(tcell.ColorDefault)
 
 	return flex
@@ -62,7 +63,7 @@ func CreateTextviewComponent(title string, border bool) *tview.TextView {
 
 	textView.
 		SetBorder(border).
-		SetBorderColor(tcell.ColorGrey).
+		SetBorderColor(theme.Current.Border).
 		SetTitle(title).
 		SetTitleAlign(tview.AlignLeft).
 		SetBackgroundColor(tcell.ColorDefault).
@@ -79,11 +80,11 @@ func CreateTextAreaComponent(title string, placeholder string) *tview.TextArea {
 
 	textArea.SetTitle(title).SetTitleAlign(tview.AlignLeft)
 
-	textArea.SetPlaceholderStyle(tcell.StyleDefault.Foreground(tcell.ColorGrey).Background(tcell.ColorDefault))
+	textArea.SetPlaceholderStyle(tcell.StyleDefault.Foreground(theme.Current.Muted).Background(tcell.ColorDefault))
 	textArea.SetTextStyle(tcell.StyleDefault.Foreground(tcell.ColorDefault).Background(tcell.ColorDefault))
 
 	textArea.SetBorder(true).
-		SetBorderColor(tcell.ColorGrey).
+		SetBorderColor(theme.Current.Border).
 		SetBackgroundColor(tcell.ColorDefault)
 
 	return textArea
@@ -95,12 +96,12 @@ func CreateInputFieldComponent(title string, placeholder string) *tview.InputFie
 
 	inputField.SetTitle(title).SetTitleAlign(tview.AlignLeft)
 
-	inputField.SetPlaceholderStyle(tcell.StyleDefault.Foreground(tcell.ColorGrey).Background(tcell.ColorDefault))
+	inputField.SetPlaceholderStyle(tcell.StyleDefault.Foreground(theme.Current.Muted).Background(tcell.ColorDefault))
 
 	inputField.SetFieldStyle(tcell.StyleDefault.Foreground(tcell.ColorDefault).Background(tcell.ColorDefault))
 
 	inputField.SetBorder(true).
-		SetBorderColor(tcell.ColorGrey).
+		SetBorderColor(theme.Current.Border).
 		SetBackgroundColor(tcell.ColorDefault)
 
 	return inputField
@@ -122,9 +123,9 @@ func CreateDropDownComponent(label string, options []string) *tview.DropDown {
 	dropdown.SetBackgroundColor(tcell.ColorDefault)
 
 	unselectedStyle := tcell.StyleDefault.Background(tcell.ColorDefault).Foreground(tcell.ColorDefault)
-	selectedStyle := tcell.StyleDefault.Background(tcell.ColorDefault).Foreground(tcell.ColorOrange)
+	selectedStyle := tcell.StyleDefault.Background(tcell.ColorDefault).Foreground(theme.Current.Accent)
 
-	dropdown.SetFieldBackgroundColor(tcell.ColorGrey)
+	dropdown.SetFieldBackgroundColor(theme.Current.Muted)
 	dropdown.SetListStyles(unselectedStyle, selectedStyle)
 
 	return dropdown
@@ -0,0 +1,132 @@
+package support
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"simple-git-terminal/theme"
+	"simple-git-terminal/util"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+const (
+	EXPORT_MENU_TITLE = "Yank [muted]enter copy | s save to file | esc cancel[-]"
+	EXPORT_SAVE_TITLE = "Save to [muted]enter save | esc cancel[-]"
+)
+
+// Export is a piece of text the export menu copies to the clipboard or saves to a file
+type Export struct {
+	Label    string
+	FileName string                 // Suggested when saving
+	Text     func() (string, error) // Runs in the background once picked
+}
+
+// ShowExportMenu lets the user pick one of the exports and copy it with OSC 52, or save it to a file
+func ShowExportMenu(app *tview.Application, background tview.Primitive, exports []Export) {
+	if len(exports) == 0 {
+		return
+	}
+
+	table := tview.NewTable().
+		SetSelectable(true, false)
+	table.SetBackgroundColor(tcell.ColorDefault)
+	table.SetSelectedStyle(tcell.StyleDefault.Foreground(theme.Current.Selected))
+	table.SetBorder(true).
+		SetTitle(EXPORT_MENU_TITLE).
+		SetTitleAlign(tview.AlignLeft).
+		SetBorderColor(theme.Current.Accent)
+
+	for i, export := range exports {
+		table.SetCell(i, 0, CreateTableCell(export.Label, theme.Current.Text).SetExpansion(1))
+		table.SetCell(i, 1, CreateTableCell(export.FileName, theme.Current.Muted))
+	}
+
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		row, _ := table.GetSelection()
+		switch {
+		case event.Key() == tcell.KeyEsc:
+			CloseModal(app, background)
+			return nil
+		case event.Key() == tcell.KeyEnter:
+			CloseModal(app, background)
+			runExport(app, background, exports[row], "")
+			return nil
+		case event.Key() == tcell.KeyRune && event.Rune() == 's':
+			CloseModal(app, background)
+			askExportPath(app, background, exports[row])
+			return nil
+		}
+		return event
+	})
+
+	ShowOverlay(app, background, table, 70, len(exports)+2)
+}
+
+func askExportPath(app *tview.Application, background tview.Primitive, export Export) {
+	ShowInputModal(app, background, EXPORT_SAVE_TITLE, "File name", export.FileName, func(text string) {
+		if path := strings.TrimSpace(text); path != "" {
+			runExport(app, background, export, path)
+		}
+	})
+}
+
+// runExport builds the text in the background, then copies it, or saves it when path is set
+func runExport(app *tview.Application, background tview.Primitive, export Export, path string) {
+	modal := ShowModal(app, background, fmt.Sprintf("Preparing %s ...", strings.ToLower(export.Label)), nil, nil)
+
+	go func() {
+		text, err := export.Text()
+		if err == nil && path != "" {
+			path, err = saveExport(path, text)
+		}
+
+		// The clipboard sequence is written on the UI goroutine, so it cannot interleave with drawing
+		app.QueueUpdateDraw(func() {
+			if err == nil && path == "" {
+				err = util.CopyToClipboard(text)
+			}
+
+			var message string
+			switch {
+			case err != nil:
+				log.Printf("[EXPORT] %s: %v", export.Label, err)
+				message = fmt.Sprintf("[danger]Could not export %s[-]\n%v", strings.ToLower(export.Label), err)
+			case path != "":
+				message = fmt.Sprintf("Saved %s to\n[link]%s[-]", strings.ToLower(export.Label), tview.Escape(path))
+			default:
+				message = fmt.Sprintf("Copied %s to the clipboard [muted](%d lines)[-]", strings.ToLower(export.Label), strings.Count(text, "\n")+1)
+			}
+			modal.SetText(message).
+				ClearButtons().
+				AddButtons([]string{"OK"})
+			SetModalDoneFunc(app, background, modal, nil)
+		})
+	}()
+}
+
+// saveExport writes text to path, relative to the working directory or the home directory for ~/, and
+// returns the absolute path
+func saveExport(path string, text string) (string, error) {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		path = filepath.Join(home, rest)
+	}
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	if !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+	if err := os.WriteFile(path, []byte(text), 0o644); err != nil {
+		return "", fmt.Errorf("could not write %s: %w", path, err)
+	}
+	return path, nil
+}
@@ -0,0 +1,50 @@
+package support
+
+import (
+	"simple-git-terminal/state"
+	"simple-git-terminal/theme"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// ShowModal overlays a modal dialog on top of the background view. onDone receives the pressed
+// button label, or "" when the dialog is dismissed with Esc. The modal is closed before onDone runs.
+func ShowModal(app *tview.Application, background tview.Primitive, text string, buttons []string, onDone func(label string)) *tview.Modal {
+	modal := tview.NewModal().
+		SetText(text).
+		AddButtons(buttons).
+		SetBackgroundColor(tcell.ColorDefault).
+		SetTextColor(tcell.ColorDefault).
+		SetButtonStyle(tcell.StyleDefault.Background(tcell.ColorDefault).Foreground(theme.Current.Muted)).
+		SetButtonActivatedStyle(tcell.StyleDefault.Background(theme.Current.Accent).Foreground(theme.Current.AccentText))
+
+	modal.SetBorderColor(theme.Current.Accent)
+
+	SetModalDoneFunc(app, background, modal, onDone)
+
+	pages := tview.NewPages().
+		AddPage("background", background, true, true).
+		AddPage("modal", modal, true, true)
+
+	state.SetIsModalOpen(true)
+	app.SetRoot(pages, true).SetFocus(modal)
+
+	return modal
+}
+
+// SetModalDoneFunc replaces the handler of a modal shown with ShowModal, e.g. after its buttons changed
+func SetModalDoneFunc(app *tview.Application, background tview.Primitive, modal *tview.Modal, onDone func(label string)) {
+	modal.SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+		CloseModal(app, background)
+		if onDone != nil {
+			onDone(buttonLabel)
+		}
+	})
+}
+
+// CloseModal removes the modal overlay and restores the background as root
+func CloseModal(app *tview.Application, background tview.Primitive) {
+	state.SetIsModalOpen(false)
+	app.SetRoot(background, true)
+}
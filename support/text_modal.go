@@ -0,0 +1,70 @@
+package support
+
+import (
+	"simple-git-terminal/state"
+	"simple-git-terminal/theme"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// ShowTextAreaModal overlays a multi-line editor on top of the background view. Ctrl-S submits the text
+// to onSubmit, Esc discards it. The overlay is closed before onSubmit runs.
+func ShowTextAreaModal(app *tview.Application, background tview.Primitive, title string, text string, onSubmit func(text string)) *tview.TextArea {
+	textArea := CreateTextAreaComponent(title+" [muted]ctrl-s save | esc cancel[-]", "Write here...")
+	textArea.SetText(text, true)
+	textArea.SetBorderColor(theme.Current.Accent)
+
+	textArea.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyCtrlS:
+			CloseModal(app, background)
+			if onSubmit != nil {
+				onSubmit(textArea.GetText())
+			}
+			return nil
+		case tcell.KeyEsc:
+			CloseModal(app, background)
+			return nil
+		}
+		return event
+	})
+
+	ShowOverlay(app, background, textArea, 80, 14)
+	return textArea
+}
+
+// ShowInputModal overlays a single-line input on top of the background view. Enter submits the text to
+// onSubmit, Esc discards it. The overlay is closed before onSubmit runs.
+func ShowInputModal(app *tview.Application, background tview.Primitive, title string, placeholder string, text string, onSubmit func(text string)) *tview.InputField {
+	input := CreateInputFieldComponent(title, placeholder)
+	input.SetText(text)
+	input.SetBorderColor(theme.Current.Accent)
+	input.SetDoneFunc(func(key tcell.Key) {
+		CloseModal(app, background)
+		if key == tcell.KeyEnter && onSubmit != nil {
+			onSubmit(input.GetText())
+		}
+	})
+
+	ShowOverlay(app, background, input, 70, 3)
+	return input
+}
+
+// ShowOverlay centers content of the given size on top of the background view. Close it with CloseModal.
+func ShowOverlay(app *tview.Application, background tview.Primitive, content tview.Primitive, width int, height int) {
+	centered := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(content, height, 1, true).
+			AddItem(nil, 0, 1, false), width, 1, true).
+		AddItem(nil, 0, 1, false)
+
+	pages := tview.NewPages().
+		AddPage("background", background, true, true).
+		AddPage("overlay", centered, true, true)
+
+	state.SetIsModalOpen(true)
+	app.SetRoot(pages, true).SetFocus(content)
+}
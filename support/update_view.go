@@ -2,6 +2,7 @@ package support
 
 import (
 	"log"
+	"simple-git-terminal/theme"
 	widgets "simple-git-terminal/widgets/table"
 	"strings"
 
@@ -22,7 +23,7 @@ func UpdateFocusBorders(focusOrder []tview.Primitive, currentFocusIndex int, act
 					SetBorderColor(activeBorderColor)
 			} else {
 				bordered.SetBorder(true).
-					SetBorderColor(tcell.ColorGrey)
+					SetBorderColor(theme.Current.Border)
 			}
 		}
 	}
@@ -47,7 +48,7 @@ func UpdateView(targetView interface{}, content interface{}) {
 				v.AddItem(c, 0, 1, true)
 			default:
 				// Handle unsupported content types
-				errorView := CreateTextviewComponent("", false).SetText("[red]Unsupported content type[-]")
+				errorView := CreateTextviewComponent("", false).SetText("[danger]Unsupported content type[-]")
 				v.AddItem(errorView, 0, 1, true)
 			}
 
@@ -62,13 +63,13 @@ func UpdateView(targetView interface{}, content interface{}) {
 				log.Println("Unsupported Primitive content for TextView")
 			default:
 				// Handle unsupported content types
-				v.SetText("[red]Unsupported content type[-]")
+				v.SetText("[danger]Unsupported content type[-]")
 			}
 
 		case widgets.TableView:
 			switch c := content.(type) {
 			case string:
-				tcell := CreateTableCell(c, tcell.ColorDarkGray)
+				tcell := CreateTableCell(c, theme.Current.Faint)
 				// NOTE: By default show spinner at last new cell. Maybe would be wiser to make this configurable in future
 				if strings.Contains(c, "Loading...") {
 					v.SetLoadingCell(tcell)
@@ -79,12 +80,12 @@ func UpdateView(targetView interface{}, content interface{}) {
 				// Handle case if content is another Primitive (optional)
 				log.Println("Unsupported Primitive content for TextView")
 			default:
-				tcell := CreateTableCell("[red]Unsupported content type[-]", tcell.ColorDefault)
+				tcell := CreateTableCell("[danger]Unsupported content type[-]", tcell.ColorDefault)
 				v.SetCell(0, 0, tcell)
 			}
 		default:
 			// If it's neither Flex nor TextView, print an error
-			log.Println("[red]Unsupported target view type[-]")
+			log.Println("[danger]Unsupported target view type[-]")
 		}
 	}
 }
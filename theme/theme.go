@@ -0,0 +1,287 @@
+package theme
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"simple-git-terminal/config"
+	"sort"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+const (
+	Dark         = "dark"
+	Light        = "light"
+	HighContrast = "high-contrast"
+
+	// Custom themes are JSON files in this directory of the config dir, selected by file name
+	themesDirName = "themes"
+)
+
+// Palette maps the roles of the UI to colors. Every role is also a tview color tag, e.g. "[muted]".
+type Palette struct {
+	Background tcell.Color
+	Text       tcell.Color // Primary text
+	Muted      tcell.Color // Secondary text such as dates, branches and hints
+	Faint      tcell.Color // Least important text
+	Border     tcell.Color // Unfocused borders
+	Accent     tcell.Color // Focused borders, markers and the active tab
+	AccentText tcell.Color // Text on an Accent background
+	Selected   tcell.Color // Selected table rows
+	Success    tcell.Color
+	Danger     tcell.Color
+	Warning    tcell.Color
+	Info       tcell.Color // People, repositories and commits
+	Link       tcell.Color // Directories, merged PRs and pending pipelines
+	Open       tcell.Color // Open PRs
+	Approved   tcell.Color
+	Resolved   tcell.Color
+	Reviewer   tcell.Color
+	Comment    tcell.Color
+	Critical   tcell.Color // Pipeline errors
+	Field      tcell.Color // Initials and edited fields
+	Key        tcell.Color // Keyboard shortcuts in titles
+	Added      tcell.Color // Added diff lines
+	Removed    tcell.Color // Removed diff lines
+
+	Markdown string // glamour style: dark, light, notty, dracula, ... or the path of a glamour JSON style
+}
+
+// roles returns the colors of the palette by their tag name
+func (p *Palette) roles() map[string]*tcell.Color {
+	return map[string]*tcell.Color{
+		"background": &p.Background,
+		"text":       &p.Text,
+		"muted":      &p.Muted,
+		"faint":      &p.Faint,
+		"border":     &p.Border,
+		"accent":     &p.Accent,
+		"accenttext": &p.AccentText,
+		"selected":   &p.Selected,
+		"success":    &p.Success,
+		"danger":     &p.Danger,
+		"warning":    &p.Warning,
+		"info":       &p.Info,
+		"link":       &p.Link,
+		"open":       &p.Open,
+		"approved":   &p.Approved,
+		"resolved":   &p.Resolved,
+		"reviewer":   &p.Reviewer,
+		"comment":    &p.Comment,
+		"critical":   &p.Critical,
+		"field":      &p.Field,
+		"key":        &p.Key,
+		"added":      &p.Added,
+		"removed":    &p.Removed,
+	}
+}
+
+// Roles returns the tag names of all palette roles, sorted
+func Roles() []string {
+	var names []string
+	for name := range (&Palette{}).roles() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+var builtins = map[string]Palette{
+	Dark: {
+		Background: tcell.ColorBlack,
+		Text:       tcell.ColorWhite,
+		Muted:      tcell.ColorGrey,
+		Faint:      tcell.ColorDarkGray,
+		Border:     tcell.ColorGrey,
+		Accent:     tcell.ColorOrange,
+		AccentText: tcell.ColorBlack,
+		Selected:   tcell.ColorDarkOrange,
+		Success:    tcell.ColorGreen,
+		Danger:     tcell.ColorRed,
+		Warning:    tcell.ColorYellow,
+		Info:       tcell.ColorSteelBlue,
+		Link:       tcell.ColorBlue,
+		Open:       tcell.ColorLawnGreen,
+		Approved:   tcell.ColorLimeGreen,
+		Resolved:   tcell.ColorAquaMarine,
+		Reviewer:   tcell.ColorPurple,
+		Comment:    tcell.ColorYellowGreen,
+		Critical:   tcell.ColorDarkRed,
+		Field:      tcell.ColorCadetBlue,
+		Key:        tcell.ColorGreen,
+		Added:      tcell.ColorGreen,
+		Removed:    tcell.ColorRed,
+		Markdown:   "dark",
+	},
+	Light: {
+		Background: tcell.ColorDefault,
+		Text:       tcell.ColorBlack,
+		Muted:      tcell.NewHexColor(0x5f5f5f),
+		Faint:      tcell.NewHexColor(0x808080),
+		Border:     tcell.NewHexColor(0x8a8a8a),
+		Accent:     tcell.NewHexColor(0xd75f00),
+		AccentText: tcell.ColorWhite,
+		Selected:   tcell.NewHexColor(0xaf5f00),
+		Success:    tcell.NewHexColor(0x008700),
+		Danger:     tcell.NewHexColor(0xd70000),
+		Warning:    tcell.NewHexColor(0x875f00),
+		Info:       tcell.NewHexColor(0x005f87),
+		Link:       tcell.NewHexColor(0x0000d7),
+		Open:       tcell.NewHexColor(0x008700),
+		Approved:   tcell.NewHexColor(0x5f8700),
+		Resolved:   tcell.NewHexColor(0x008787),
+		Reviewer:   tcell.NewHexColor(0x870087),
+		Comment:    tcell.NewHexColor(0x5f8700),
+		Critical:   tcell.NewHexColor(0x870000),
+		Field:      tcell.NewHexColor(0x005f5f),
+		Key:        tcell.NewHexColor(0x005f00),
+		Added:      tcell.NewHexColor(0x008700),
+		Removed:    tcell.NewHexColor(0xd70000),
+		Markdown:   "light",
+	},
+	HighContrast: {
+		Background: tcell.ColorBlack,
+		Text:       tcell.ColorWhite,
+		Muted:      tcell.ColorSilver,
+		Faint:      tcell.ColorSilver,
+		Border:     tcell.ColorWhite,
+		Accent:     tcell.ColorYellow,
+		AccentText: tcell.ColorBlack,
+		Selected:   tcell.ColorYellow,
+		Success:    tcell.ColorLime,
+		Danger:     tcell.NewHexColor(0xff5f5f),
+		Warning:    tcell.ColorYellow,
+		Info:       tcell.ColorAqua,
+		Link:       tcell.NewHexColor(0x5fafff),
+		Open:       tcell.ColorLime,
+		Approved:   tcell.ColorLime,
+		Resolved:   tcell.ColorAqua,
+		Reviewer:   tcell.ColorFuchsia,
+		Comment:    tcell.NewHexColor(0xafff00),
+		Critical:   tcell.NewHexColor(0xff0000),
+		Field:      tcell.ColorAqua,
+		Key:        tcell.ColorLime,
+		Added:      tcell.ColorLime,
+		Removed:    tcell.NewHexColor(0xff5f5f),
+		Markdown:   "dark",
+	},
+}
+
+// Current is the palette in use, dark until Load runs
+var Current = builtinPalette(Dark)
+
+func builtinPalette(name string) *Palette {
+	palette := builtins[name]
+	return &palette
+}
+
+// customTheme is the format of a theme file. Colors are W3C names or #rrggbb, keyed by role.
+type customTheme struct {
+	Base     string            `json:"base"` // Built-in theme the colors are applied over, dark by default
+	Markdown string            `json:"markdown"`
+	Colors   map[string]string `json:"colors"`
+}
+
+// Load selects the theme by name: a built-in theme, a file in the themes directory of the config dir,
+// or the path of a JSON file. An empty name picks light or dark from the terminal.
+func Load(name string) error {
+	if name == "" {
+		name = detect()
+	}
+
+	palette, err := resolve(name)
+	if err != nil {
+		return err
+	}
+	Current = palette
+	apply(Current)
+	log.Printf("[THEME] Using theme %s", name)
+	return nil
+}
+
+func resolve(name string) (*Palette, error) {
+	if _, ok := builtins[name]; ok {
+		return builtinPalette(name), nil
+	}
+
+	path := name
+	if !strings.ContainsRune(name, os.PathSeparator) && !strings.HasSuffix(name, ".json") {
+		path = filepath.Join(config.Dir(), themesDirName, name+".json")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unknown theme %q, use dark, light, high-contrast or a theme file: %w", name, err)
+	}
+
+	var custom customTheme
+	if err := json.Unmarshal(data, &custom); err != nil {
+		return nil, fmt.Errorf("failed to parse theme %s: %w", path, err)
+	}
+	if custom.Base == "" {
+		custom.Base = Dark
+	}
+	if _, ok := builtins[custom.Base]; !ok {
+		return nil, fmt.Errorf("theme %s: unknown base %q", path, custom.Base)
+	}
+
+	palette := builtinPalette(custom.Base)
+	roles := palette.roles()
+	for role, value := range custom.Colors {
+		target, ok := roles[strings.ToLower(role)]
+		if !ok {
+			return nil, fmt.Errorf("theme %s: unknown role %q, expected one of %s", path, role, strings.Join(Roles(), ", "))
+		}
+		color := tcell.GetColor(value)
+		if color == tcell.ColorDefault && value != "default" {
+			return nil, fmt.Errorf("theme %s: invalid color %q for %s", path, value, role)
+		}
+		*target = color
+	}
+	if custom.Markdown != "" {
+		palette.Markdown = custom.Markdown
+	}
+	return palette, nil
+}
+
+// detect guesses the terminal background from COLORFGBG ("fg;bg"), which many terminals set
+func detect() string {
+	fields := strings.Split(os.Getenv("COLORFGBG"), ";")
+	switch fields[len(fields)-1] {
+	case "7", "15":
+		return Light
+	}
+	return Dark
+}
+
+// registerTags makes the roles usable as tview color tags
+func registerTags(p *Palette) {
+	for name, color := range p.roles() {
+		tcell.ColorNames[name] = *color
+	}
+}
+
+// apply registers the tags and styles new primitives with the palette
+func apply(p *Palette) {
+	registerTags(p)
+	tview.Styles.PrimitiveBackgroundColor = p.Background
+	tview.Styles.ContrastBackgroundColor = p.Link
+	tview.Styles.MoreContrastBackgroundColor = p.Success
+	tview.Styles.BorderColor = p.Border
+	tview.Styles.TitleColor = p.Text
+	tview.Styles.GraphicsColor = p.Text
+	tview.Styles.PrimaryTextColor = p.Text
+	tview.Styles.SecondaryTextColor = p.Warning
+	tview.Styles.TertiaryTextColor = p.Success
+	tview.Styles.InverseTextColor = p.Link
+	tview.Styles.ContrastSecondaryTextColor = p.AccentText
+}
+
+func init() {
+	// Tags also work when Load never runs, e.g. in the CLI
+	registerTags(Current)
+}
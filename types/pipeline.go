@@ -56,7 +56,7 @@ type State struct {
 	Name   PipelineStatus `json:"name"`
 	Type   string         `json:"type"`
 	Result Result         `json:"result,omitempty"`
-	State  Result         `json:"state,omitempty`
+	State  Result         `json:"state,omitempty"`
 	// it is either result or state
 }
 
@@ -1,5 +1,7 @@
 package types
 
+import "strconv"
+
 // Common structs for Links and Avatar
 type Links struct {
 	Self   Self   `json:"self"`
@@ -40,22 +42,58 @@ type PR struct {
 		Branch struct {
 			Name string `json:"name"`
 		} `json:"branch"`
-		Commit Commit `json:"commit"`
+		Commit     Commit     `json:"commit"`
+		Repository Repository `json:"repository"`
 	} `json:"source"`
 	Destination struct {
 		Branch struct {
 			Name string `json:"name"`
 		} `json:"branch"`
+		Commit     Commit     `json:"commit"`
+		Repository Repository `json:"repository"`
 	} `json:"destination"`
 	Reviewers    []Reviewer    `json:"reviewers"`
 	Participants []Participant `json:"participants"`
+	CommentCount int           `json:"comment_count"`
+	TaskCount    int           `json:"task_count"` // Open tasks
+	Draft        bool          `json:"draft"`
+	// Data Center reports the resolved tasks along with the PR, Cloud only by listing the tasks
+	ResolvedTaskCount int `json:"-"`
+}
+
+// Key identifies a PR across repositories, as PR IDs are only unique within one
+func (pr PR) Key() string {
+	return pr.Destination.Repository.FullName + "#" + strconv.Itoa(pr.ID)
+}
+
+// PRUpdate holds the editable fields of a PR
+type PRUpdate struct {
+	Title       string
+	Description string
+	Destination string // Branch name
+	Draft       bool
+}
+
+type Repository struct {
+	Type     string `json:"type"`
+	FullName string `json:"full_name"` // "workspace/repo_slug"
+	Links    Links  `json:"links"`
+	Name     string `json:"name"`
+	UUID     string `json:"uuid"`
 }
 
 type Commit struct {
-	Type    string      `json:"type"`
-	Hash    string      `json:"hash"`
-	Message string      `json:"message"`
-	Links   CommitLinks `json:"links"`
+	Type    string       `json:"type"`
+	Hash    string       `json:"hash"`
+	Message string       `json:"message"`
+	Date    string       `json:"date"`
+	Author  CommitAuthor `json:"author"`
+	Links   CommitLinks  `json:"links"`
+}
+
+type CommitAuthor struct {
+	Raw  string `json:"raw"` // "Name <email>", always present even when not linked to a Bitbucket user
+	User User   `json:"user"`
 }
 
 type Reviewer struct {
@@ -87,6 +125,7 @@ type Activity struct {
 type Author struct {
 	DisplayName string `json:"display_name"`
 	Username    string `json:"username"`
+	UUID        string `json:"uuid"`
 }
 
 type UpdateDetail struct {
@@ -190,6 +229,114 @@ type Approval struct {
 	PullRequest PR     `json:"pullrequest"`
 }
 
+const (
+	TaskResolved   = "RESOLVED"
+	TaskUnresolved = "UNRESOLVED"
+)
+
+// Task is a to-do on a PR, either on its own or attached to a comment
+type Task struct {
+	ID         int            `json:"id"`
+	State      string         `json:"state"` // TaskResolved or TaskUnresolved
+	Content    Content        `json:"content"`
+	Creator    User           `json:"creator"`
+	CreatedOn  string         `json:"created_on"`
+	ResolvedOn string         `json:"resolved_on"`
+	ResolvedBy *User          `json:"resolved_by"`
+	Pending    bool           `json:"pending"`
+	Comment    *CommentParent `json:"comment"` // nil for tasks on the PR itself
+}
+
+func (t Task) IsResolved() bool { return t.State == TaskResolved }
+
+// TaskCount is the number of open and resolved tasks of a PR
+type TaskCount struct {
+	Open     int
+	Resolved int
+}
+
+// CountTasks counts open and resolved tasks
+func CountTasks(tasks []Task) TaskCount {
+	var count TaskCount
+	for _, task := range tasks {
+		if task.IsResolved() {
+			count.Resolved++
+		} else {
+			count.Open++
+		}
+	}
+	return count
+}
+
+// CommitStatus is a build result reported on the source commit of a PR
+type CommitStatus struct {
+	Key       string `json:"key"`
+	Name      string `json:"name"`
+	State     string `json:"state"` // SUCCESSFUL, FAILED, INPROGRESS or STOPPED
+	URL       string `json:"url"`
+	UpdatedOn string `json:"updated_on"`
+}
+
+// Member is an entry of a workspace's member or a repository's default reviewer list
+type Member struct {
+	User         User   `json:"user"`
+	ReviewerType string `json:"reviewer_type"` // Default reviewers only: "repository" or "project"
+}
+
+type BitbucketMemberResponse struct {
+	Values []Member `json:"values"`
+	Pagination
+}
+
+// BranchRestriction is a rule on the branches matching a glob pattern or a branching model type
+type BranchRestriction struct {
+	Kind            string `json:"kind"` // e.g. require_approvals_to_merge, require_default_reviewer_approvals_to_merge
+	BranchMatchKind string `json:"branch_match_kind"`
+	BranchType      string `json:"branch_type"`
+	Pattern         string `json:"pattern"`
+	Value           int    `json:"value"` // Number of approvals for the require_* kinds
+	Users           []User `json:"users"`
+}
+
+// BranchingModel tells which branches the branching_model restrictions apply to
+type BranchingModel struct {
+	Development BranchingModelBranch `json:"development"`
+	Production  BranchingModelBranch `json:"production"`
+	BranchTypes []struct {
+		Kind   string `json:"kind"`
+		Prefix string `json:"prefix"`
+	} `json:"branch_types"`
+}
+
+type BranchingModelBranch struct {
+	Name   string `json:"name"`
+	Branch struct {
+		Name string `json:"name"`
+	} `json:"branch"`
+}
+
+type BitbucketBranchRestrictionResponse struct {
+	Values []BranchRestriction `json:"values"`
+	Pagination
+}
+
+type BitbucketBranchResponse struct {
+	Values []struct {
+		Name string `json:"name"`
+	} `json:"values"`
+	Pagination
+}
+
+type BitbucketCommitStatusResponse struct {
+	Values []CommitStatus `json:"values"`
+	Pagination
+}
+
+type BitbucketRepositoryResponse struct {
+	Values []Repository `json:"values"`
+	Pagination
+}
+
 type BitbucketPRResponse struct {
 	Values []PR `json:"values"`
 	Pagination
@@ -197,10 +344,22 @@ type BitbucketPRResponse struct {
 
 type BitbucketActivityResponse struct {
 	Values []Activity `json:"values"`
+	Pagination
+}
+
+type BitbucketTaskResponse struct {
+	Values []Task `json:"values"`
+	Pagination
 }
 
 type BitbucketCommentsResponse struct {
 	Values []Comment `json:"values"`
+	Pagination
+}
+
+type BitbucketCommitsResponse struct {
+	Values []Commit `json:"values"`
+	Pagination
 }
 
 type DiffstatResponse struct {
@@ -1,49 +1,313 @@
 package ui
 
 import (
+	"fmt"
 	"simple-git-terminal/constants"
+	"simple-git-terminal/theme"
 	"simple-git-terminal/types"
 	"simple-git-terminal/util"
+	"sort"
+	"strings"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 )
 
-func PopulatePRList(prList *tview.Table, prs []types.PR) {
+// PR_LIST_HEADER_ROWS is the number of rows above the first PR, PR i is on row i+PR_LIST_HEADER_ROWS
+const PR_LIST_HEADER_ROWS = 1
+
+// PRListContext holds what the cells need besides the PR itself
+type PRListContext struct {
+	Me          *types.User
+	BuildStates map[string]string          // Combined build state per PR key, missing while loading
+	TaskCounts  map[string]types.TaskCount // Open and resolved tasks per PR key, missing while loading
+	Sort        string                     // Sorted column ID, "-" prefix for descending
+	Width       int                        // Inner width of the table, 0 when not drawn yet
+}
+
+// PRListColumn is a column of the PR list that can be picked in the config
+type PRListColumn struct {
+	ID        string
+	Header    string
+	Width     int    // Width of the content, the title column takes the remaining space
+	Priority  int    // Columns with the lowest priority are hidden first on narrow terminals
+	SortField string // API field the server sorts by, empty when only sorted locally
+	Cell      func(pr types.PR, ctx PRListContext) *tview.TableCell
+	Less      func(a, b types.PR, ctx PRListContext) bool
+}
+
+var PRListColumns = []PRListColumn{
+	{
+		ID: "id", Header: "#", Width: 5, Priority: 90, SortField: "id",
+		Cell: func(pr types.PR, ctx PRListContext) *tview.TableCell {
+			return util.CellFormat(fmt.Sprintf("%d", pr.ID), theme.Current.Muted)
+		},
+		Less: func(a, b types.PR, ctx PRListContext) bool { return a.ID < b.ID },
+	},
+	{
+		ID: "repo", Header: "Repo", Width: 16, Priority: 85,
+		Cell: func(pr types.PR, ctx PRListContext) *tview.TableCell {
+			return util.CellFormat(tview.Escape(pr.Destination.Repository.Name), theme.Current.Info)
+		},
+		Less: func(a, b types.PR, ctx PRListContext) bool {
+			return a.Destination.Repository.FullName < b.Destination.Repository.FullName
+		},
+	},
+	{
+		ID: "title", Header: "Title", Width: 30, Priority: 100, SortField: "title",
+		Cell: func(pr types.PR, ctx PRListContext) *tview.TableCell {
+			return util.CellFormat(tview.Escape(pr.Title), theme.Current.Text)
+		},
+		Less: func(a, b types.PR, ctx PRListContext) bool {
+			return strings.ToLower(a.Title) < strings.ToLower(b.Title)
+		},
+	},
+	{
+		ID: "author", Header: "By", Width: 3, Priority: 70, SortField: "author.display_name",
+		Cell: func(pr types.PR, ctx PRListContext) *tview.TableCell {
+			return util.CellFormat(util.FormatInitials(pr.Author.DisplayName), theme.Current.Field)
+		},
+		Less: func(a, b types.PR, ctx PRListContext) bool { return a.Author.DisplayName < b.Author.DisplayName },
+	},
+	{
+		ID: "state", Header: "State", Width: 8, Priority: 80, SortField: "state",
+		Cell: func(pr types.PR, ctx PRListContext) *tview.TableCell {
+			return util.CreateStateCell(pr.State)
+		},
+		Less: func(a, b types.PR, ctx PRListContext) bool { return a.State < b.State },
+	},
+	{
+		ID: "branches", Header: "Branches", Width: 30, Priority: 40,
+		Cell: func(pr types.PR, ctx PRListContext) *tview.TableCell {
+			return util.CellFormat(fmt.Sprintf("%s %s %s",
+				tview.Escape(util.EllipsizeText(pr.Source.Branch.Name, 12)), constants.ICON_SIDE_ARROW,
+				tview.Escape(util.EllipsizeText(pr.Destination.Branch.Name, 12))), theme.Current.Muted)
+		},
+		Less: func(a, b types.PR, ctx PRListContext) bool { return a.Source.Branch.Name < b.Source.Branch.Name },
+	},
+	{
+		ID: "age", Header: "Age", Width: 4, Priority: 20, SortField: "created_on",
+		Cell: func(pr types.PR, ctx PRListContext) *tview.TableCell {
+			return util.CellFormat(util.FormatShortAge(pr.CreatedOn), theme.Current.Muted)
+		},
+		Less: func(a, b types.PR, ctx PRListContext) bool { return a.CreatedOn < b.CreatedOn },
+	},
+	{
+		ID: "updated", Header: "Upd", Width: 4, Priority: 50, SortField: "updated_on",
+		Cell: func(pr types.PR, ctx PRListContext) *tview.TableCell {
+			return util.CellFormat(util.FormatShortAge(pr.UpdatedOn), theme.Current.Muted)
+		},
+		Less: func(a, b types.PR, ctx PRListContext) bool { return a.UpdatedOn < b.UpdatedOn },
+	},
+	{
+		ID: "comments", Header: "Cmt", Width: 4, Priority: 30, SortField: "comment_count",
+		Cell: func(pr types.PR, ctx PRListContext) *tview.TableCell {
+			return countCell(pr.CommentCount)
+		},
+		Less: func(a, b types.PR, ctx PRListContext) bool { return a.CommentCount < b.CommentCount },
+	},
+	{
+		ID: "approvals", Header: "Appr", Width: 5, Priority: 60,
+		Cell: func(pr types.PR, ctx PRListContext) *tview.TableCell {
+			approvals, myState := approvalSummary(pr, ctx.Me)
+			text := fmt.Sprintf("%d", approvals)
+			if approvals == 0 {
+				text = "[muted]-[-]"
+			}
+			return util.CellFormat(text+" "+util.GetPRReviewStateIcon(myState), theme.Current.Success)
+		},
+		Less: func(a, b types.PR, ctx PRListContext) bool {
+			approvalsA, _ := approvalSummary(a, ctx.Me)
+			approvalsB, _ := approvalSummary(b, ctx.Me)
+			return approvalsA < approvalsB
+		},
+	},
+	{
+		ID: "build", Header: "CI", Width: 2, Priority: 45,
+		Cell: func(pr types.PR, ctx PRListContext) *tview.TableCell {
+			return util.CellFormat(buildStateIcon(ctx.BuildStates[pr.Key()]), tcell.ColorDefault)
+		},
+		Less: func(a, b types.PR, ctx PRListContext) bool {
+			return ctx.BuildStates[a.Key()] < ctx.BuildStates[b.Key()]
+		},
+	},
+	{
+		ID: "tasks", Header: "Tsk", Width: 5, Priority: 25, SortField: "task_count",
+		Cell: func(pr types.PR, ctx PRListContext) *tview.TableCell {
+			count, ok := ctx.TaskCounts[pr.Key()]
+			if !ok {
+				return countCell(pr.TaskCount)
+			}
+			return taskCountCell(count)
+		},
+		Less: func(a, b types.PR, ctx PRListContext) bool { return a.TaskCount < b.TaskCount },
+	},
+}
+
+// FindPRListColumn looks up a column by ID
+func FindPRListColumn(id string) (PRListColumn, bool) {
+	for _, column := range PRListColumns {
+		if column.ID == id {
+			return column, true
+		}
+	}
+	return PRListColumn{}, false
+}
+
+// VisiblePRListColumns resolves the configured column IDs, dropping the lowest priority columns
+// until they fit into width. Unknown IDs are ignored.
+func VisiblePRListColumns(ids []string, width int) []PRListColumn {
+	var columns []PRListColumn
+	for _, id := range ids {
+		if column, ok := FindPRListColumn(id); ok {
+			columns = append(columns, column)
+		}
+	}
+
+	// The selection marker takes the first column, every column is followed by a space
+	for width > 0 && len(columns) > 1 {
+		used := 2
+		for _, column := range columns {
+			used += column.Width + 1
+		}
+		if used <= width {
+			break
+		}
+
+		lowest := 0
+		for i, column := range columns {
+			if column.Priority < columns[lowest].Priority {
+				lowest = i
+			}
+		}
+		columns = append(columns[:lowest], columns[lowest+1:]...)
+	}
+	return columns
+}
+
+// SortPRs sorts prs in place by a column ID, "-" prefix for descending
+func SortPRs(prs []types.PR, sortKey string, ctx PRListContext) {
+	id, descending := strings.CutPrefix(sortKey, "-")
+	column, ok := FindPRListColumn(id)
+	if !ok {
+		return
+	}
+	sort.SliceStable(prs, func(i, j int) bool {
+		if descending {
+			return column.Less(prs[j], prs[i], ctx)
+		}
+		return column.Less(prs[i], prs[j], ctx)
+	})
+}
+
+func PopulatePRList(prList *tview.Table, prs []types.PR, columnIDs []string, ctx PRListContext) {
 	// If there are no PRs, display a "No PRs" message
 	if len(prs) == 0 {
 		// Display a message in the first row
-		noPRsCell := util.CellFormat("  No PRs available, try changing filters/search term", tcell.ColorWhite)
+		noPRsCell := util.CellFormat("  No PRs available, try changing filters/search term", theme.Current.Text)
 		prList.SetCell(0, 0, noPRsCell)
 		return
 	}
 
-	for i, pr := range prs {
-		titleCell := util.CellFormat(util.EllipsizeText(pr.Title, 18), tcell.ColorWhite)
-		stateCell := util.CreateStateCell(pr.State)
+	columns := VisiblePRListColumns(columnIDs, ctx.Width)
+	sortID, descending := strings.CutPrefix(ctx.Sort, "-")
 
-		initialsCell := util.CellFormat(util.FormatInitials(pr.Author.DisplayName), constants.HIGH_CONTRAST_COLOR)
+	// The title column takes whatever the other columns leave
+	titleWidth := 30
+	if ctx.Width > 0 {
+		titleWidth = ctx.Width - 2
+		for _, column := range columns {
+			if column.ID != "title" {
+				titleWidth -= column.Width + 1
+			}
+		}
+		titleWidth = max(titleWidth-1, 10)
+	}
 
-		sourceBranch := util.CellFormat(util.EllipsizeText(pr.Source.Branch.Name, 18), tcell.ColorGrey)
-		arrow := util.CellFormat(constants.ICON_SIDE_ARROW, tcell.ColorDefault)
-		destinationBranch := util.CellFormat(util.EllipsizeText(pr.Destination.Branch.Name, 18), tcell.ColorGrey)
+	prList.SetCell(0, 0, tview.NewTableCell("").SetSelectable(false))
+	for c, column := range columns {
+		header := column.Header
+		if column.ID == sortID {
+			if descending {
+				header += constants.ICON_DOWN_ARROW
+			} else {
+				header += constants.ICON_UP_ARROW
+			}
+		}
+		prList.SetCell(0, c+1, tview.NewTableCell(header).
+			SetTextColor(theme.Current.Muted).
+			SetAttributes(tcell.AttrBold).
+			SetSelectable(false))
+	}
 
-		selectedCell := util.CellFormat(constants.ICON_SELECTED, tcell.ColorOrange)
+	for i, pr := range prs {
+		row := i + PR_LIST_HEADER_ROWS
 
 		// no need to check what is selcted at this point, as this is very first time, select first row already
+		marker := ""
 		if i == 0 {
-			prList.SetCell(i, 0, selectedCell)
+			marker = constants.ICON_SELECTED
+		}
+		prList.SetCell(row, 0, util.CellFormat(marker, theme.Current.Accent))
+
+		for c, column := range columns {
+			cell := column.Cell(pr, ctx)
+			if column.ID == "title" {
+				cell.SetMaxWidth(titleWidth).SetExpansion(1)
+			} else {
+				cell.SetMaxWidth(column.Width)
+			}
+			prList.SetCell(row, c+1, cell)
 		}
+	}
 
-		prList.SetCell(i, 1, initialsCell)
-		prList.SetCell(i, 2, stateCell)
+	prList.SetSelectedStyle(tcell.StyleDefault.Foreground(theme.Current.Selected))
+}
 
-		prList.SetCell(i, 3, sourceBranch)
-		prList.SetCell(i, 4, arrow)
-		prList.SetCell(i, 5, destinationBranch)
-		prList.SetCell(i, 6, titleCell)
+// approvalSummary counts the approvals of a PR and returns the review state of the current user
+func approvalSummary(pr types.PR, me *types.User) (int, types.ApprovedState) {
+	approvals := 0
+	var myState types.ApprovedState
+	for _, participant := range pr.Participants {
+		if participant.Approved {
+			approvals++
+		}
+		if me != nil && participant.User != nil && participant.User.UUID == me.UUID {
+			myState = participant.State
+		}
+	}
+	return approvals, myState
+}
 
+func countCell(count int) *tview.TableCell {
+	if count == 0 {
+		return util.CellFormat("-", theme.Current.Muted)
 	}
+	return util.CellFormat(fmt.Sprintf("%d", count), theme.Current.Text)
+}
 
-	prList.SetSelectedStyle(tcell.StyleDefault.Foreground(tcell.ColorDarkOrange))
+// taskCountCell shows open tasks out of all tasks, e.g. 2/5
+func taskCountCell(count types.TaskCount) *tview.TableCell {
+	switch {
+	case count.Open+count.Resolved == 0:
+		return util.CellFormat("-", theme.Current.Muted)
+	case count.Open == 0:
+		return util.CellFormat(fmt.Sprintf("[success]0[-][muted]/%d[-]", count.Resolved), theme.Current.Text)
+	}
+	return util.CellFormat(fmt.Sprintf("[warning]%d[-][muted]/%d[-]", count.Open, count.Open+count.Resolved), theme.Current.Text)
+}
+
+func buildStateIcon(buildState string) string {
+	switch buildState {
+	case "SUCCESSFUL":
+		return "[success]" + constants.ICON_ACTIVE + "[-]"
+	case "FAILED", "STOPPED":
+		return "[danger]" + constants.ICON_DECLINED + "[-]"
+	case "INPROGRESS":
+		return "[warning]" + constants.ICON_BUILD + "[-]"
+	case "NONE":
+		return "[muted]-[-]"
+	default:
+		return "[muted]…[-]"
+	}
 }
@@ -0,0 +1,64 @@
+package ui
+
+import (
+	"reflect"
+	"simple-git-terminal/types"
+	"testing"
+)
+
+func testPR(repo string, id int) types.PR {
+	var pr types.PR
+	pr.ID = id
+	pr.Destination.Repository.FullName = repo
+	return pr
+}
+
+func TestSortPRsByBuild(t *testing.T) {
+	// The same ID in two repositories of the dashboard
+	prs := []types.PR{testPR("ws/a", 1), testPR("ws/b", 1), testPR("ws/a", 2)}
+	ctx := PRListContext{BuildStates: map[string]string{
+		"ws/a#1": "SUCCESSFUL",
+		"ws/b#1": "FAILED",
+		"ws/a#2": "INPROGRESS",
+	}}
+
+	SortPRs(prs, "build", ctx)
+	var got []string
+	for _, pr := range prs {
+		got = append(got, pr.Key())
+	}
+	if want := []string{"ws/b#1", "ws/a#2", "ws/a#1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("sorted = %v, want %v", got, want)
+	}
+
+	SortPRs(prs, "-build", ctx)
+	if prs[0].Key() != "ws/a#1" {
+		t.Errorf("descending sort starts with %s, want ws/a#1", prs[0].Key())
+	}
+}
+
+func TestVisiblePRListColumns(t *testing.T) {
+	tests := []struct {
+		name  string
+		ids   []string
+		width int
+		want  []string
+	}{
+		{name: "not drawn yet", ids: []string{"id", "title", "age"}, width: 0, want: []string{"id", "title", "age"}},
+		{name: "everything fits", ids: []string{"id", "title", "age"}, width: 44, want: []string{"id", "title", "age"}},
+		{name: "lowest priority dropped", ids: []string{"id", "title", "age"}, width: 43, want: []string{"id", "title"}},
+		{name: "unknown ignored", ids: []string{"id", "nope"}, width: 0, want: []string{"id"}},
+		{name: "last column kept", ids: []string{"title", "id"}, width: 10, want: []string{"title"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, column := range VisiblePRListColumns(tt.ids, tt.width) {
+				got = append(got, column.ID)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("VisiblePRListColumns(%v, %d) = %v, want %v", tt.ids, tt.width, got, tt.want)
+			}
+		})
+	}
+}
@@ -51,6 +51,14 @@ func getInitials(displayName string) string {
 	return strings.ToUpper(displayName)
 }
 
+// ShortHash trims a commit hash to the 7 characters git shows by default
+func ShortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
+
 // Helper function to calculate time ago
 func FormatTimeAgo(date string) string {
 	parsedTime, err := time.Parse(time.RFC3339, date)
@@ -60,6 +68,27 @@ func FormatTimeAgo(date string) string {
 	return humanize.Time(parsedTime)
 }
 
+// FormatShortAge formats the time since date as a compact age such as 45m, 5h, 3d or 2w
+func FormatShortAge(date string) string {
+	parsedTime, err := time.Parse(time.RFC3339, date)
+	if err != nil {
+		return "?"
+	}
+	age := time.Since(parsedTime)
+	switch {
+	case age < time.Hour:
+		return fmt.Sprintf("%dm", int(age.Minutes()))
+	case age < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(age.Hours()))
+	case age < 14*24*time.Hour:
+		return fmt.Sprintf("%dd", int(age.Hours()/24))
+	case age < 365*24*time.Hour:
+		return fmt.Sprintf("%dw", int(age.Hours()/24/7))
+	default:
+		return fmt.Sprintf("%dy", int(age.Hours()/24/365))
+	}
+}
+
 func FormatCombinedTimeAgo(date string) string {
 	parsedTime, err := time.Parse(time.RFC3339, date)
 	if err != nil {
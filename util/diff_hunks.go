@@ -0,0 +1,436 @@
+package util
+
+import (
+	"fmt"
+	"regexp"
+	"simple-git-terminal/types"
+	"strconv"
+	"strings"
+)
+
+var hunkHeaderRegex = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@(.*)$`)
+
+// DiffHunk is a single "@@ -a,b +c,d @@" section of a unified diff
+type DiffHunk struct {
+	OldStart int
+	OldCount int
+	NewStart int
+	NewCount int
+	Section  string   // Trailing text after the header, usually the enclosing function
+	Lines    []string // Raw diff lines including their " ", "+", "-" or "\" marker
+}
+
+// ParseDiffHunks splits a single file diff into its header (everything before the first hunk) and hunks
+func ParseDiffHunks(diffText string) (string, []DiffHunk) {
+	var headerLines []string
+	var hunks []DiffHunk
+
+	for _, line := range strings.Split(strings.TrimRight(diffText, "\n"), "\n") {
+		if matches := hunkHeaderRegex.FindStringSubmatch(line); matches != nil {
+			hunks = append(hunks, DiffHunk{
+				OldStart: atoiOr(matches[1], 0),
+				OldCount: atoiOr(matches[2], 1),
+				NewStart: atoiOr(matches[3], 0),
+				NewCount: atoiOr(matches[4], 1),
+				Section:  matches[5],
+			})
+			continue
+		}
+		if len(hunks) == 0 {
+			headerLines = append(headerLines, line)
+			continue
+		}
+		hunks[len(hunks)-1].Lines = append(hunks[len(hunks)-1].Lines, line)
+	}
+
+	return strings.Join(headerLines, "\n"), hunks
+}
+
+func atoiOr(value string, fallback int) int {
+	if value == "" {
+		return fallback
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return fallback
+	}
+	return n
+}
+
+// FileDiff is the part of a multi-file diff that belongs to a single file
+type FileDiff struct {
+	Path string
+	Text string
+}
+
+// SplitDiffFiles splits a multi-file unified diff on its "diff --git" headers
+func SplitDiffFiles(diffText string) []FileDiff {
+	var files []FileDiff
+	var current []string
+
+	flush := func() {
+		if len(current) > 0 {
+			files = append(files, FileDiff{Path: diffFilePath(current), Text: strings.Join(current, "\n")})
+		}
+		current = nil
+	}
+
+	for _, line := range strings.Split(strings.TrimRight(diffText, "\n"), "\n") {
+		if strings.HasPrefix(line, "diff --git ") {
+			flush()
+		}
+		current = append(current, line)
+	}
+	flush()
+
+	return files
+}
+
+// diffFilePath prefers the new path, falling back to the old one for deleted files
+func diffFilePath(lines []string) string {
+	var oldPath string
+	for _, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "+++ ") && line != "+++ /dev/null":
+			return strings.TrimPrefix(strings.TrimPrefix(line, "+++ "), "b/")
+		case strings.HasPrefix(line, "--- ") && line != "--- /dev/null":
+			oldPath = strings.TrimPrefix(strings.TrimPrefix(line, "--- "), "a/")
+		case strings.HasPrefix(line, "@@"):
+			if oldPath != "" {
+				return oldPath
+			}
+		}
+	}
+	if oldPath != "" {
+		return oldPath
+	}
+	if len(lines) > 0 {
+		// Binary or mode-only changes have no ---/+++ lines: "diff --git a/x b/x"
+		if parts := strings.SplitN(lines[0], " b/", 2); len(parts) == 2 {
+			return parts[1]
+		}
+	}
+	return ""
+}
+
+// BuildDiffText is the inverse of ParseDiffHunks
+func BuildDiffText(header string, hunks []DiffHunk) string {
+	var sb strings.Builder
+	if header != "" {
+		sb.WriteString(header + "\n")
+	}
+	for _, hunk := range hunks {
+		sb.WriteString(fmt.Sprintf("@@ -%d,%d +%d,%d @@%s\n", hunk.OldStart, hunk.OldCount, hunk.NewStart, hunk.NewCount, hunk.Section))
+		for _, line := range hunk.Lines {
+			sb.WriteString(line + "\n")
+		}
+	}
+	return sb.String()
+}
+
+// firstNew and firstOld return the 1-based first line of the hunk. Git reports the line *before*
+// the hunk when a side is empty, so those are shifted by one.
+func (h DiffHunk) firstNew() int {
+	if h.NewCount == 0 {
+		return h.NewStart + 1
+	}
+	return h.NewStart
+}
+
+func (h DiffHunk) firstOld() int {
+	if h.OldCount == 0 {
+		return h.OldStart + 1
+	}
+	return h.OldStart
+}
+
+func (h DiffHunk) lastNew() int {
+	return h.firstNew() + h.NewCount - 1
+}
+
+// HunkAtDisplayLine maps a line index of the rendered diff (which drops the first hunk header) to its hunk
+func HunkAtDisplayLine(hunks []DiffHunk, lineIndex int) int {
+	offset := 0
+	for i, hunk := range hunks {
+		size := len(hunk.Lines)
+		if i > 0 {
+			size++ // header line of every hunk except the first is rendered
+		}
+		if lineIndex < offset+size {
+			return i
+		}
+		offset += size
+	}
+	return len(hunks) - 1
+}
+
+// HunkDisplayRange returns the first and last rendered line of a hunk, leaving out its header
+func HunkDisplayRange(hunks []DiffHunk, index int) (int, int) {
+	offset := 0
+	for i, hunk := range hunks {
+		if i > 0 {
+			offset++
+		}
+		if i == index {
+			return offset, offset + len(hunk.Lines) - 1
+		}
+		offset += len(hunk.Lines)
+	}
+	return 0, -1
+}
+
+// SliceHunks keeps the rendered diff lines from..to (inclusive) of the hunks. Hunks are cut down to the
+// range with recounted headers, so the result still applies as a patch.
+func SliceHunks(hunks []DiffHunk, from int, to int) []DiffHunk {
+	if from > to {
+		from, to = to, from
+	}
+
+	var sliced []DiffHunk
+	lineIndex := 0
+	for i, hunk := range hunks {
+		if i > 0 {
+			lineIndex++ // Rendered hunk header
+		}
+		oldLine, newLine := hunk.firstOld(), hunk.firstNew()
+		var cut *DiffHunk
+		for _, line := range hunk.Lines {
+			if lineIndex >= from && lineIndex <= to {
+				if cut == nil {
+					cut = &DiffHunk{OldStart: oldLine, NewStart: newLine, Section: hunk.Section}
+				}
+				cut.Lines = append(cut.Lines, line)
+				if !strings.HasPrefix(line, "+") && !strings.HasPrefix(line, "\\") {
+					cut.OldCount++
+				}
+				if !strings.HasPrefix(line, "-") && !strings.HasPrefix(line, "\\") {
+					cut.NewCount++
+				}
+			}
+			switch {
+			case strings.HasPrefix(line, "+"):
+				newLine++
+			case strings.HasPrefix(line, "-"):
+				oldLine++
+			case strings.HasPrefix(line, "\\"):
+			default:
+				oldLine++
+				newLine++
+			}
+			lineIndex++
+		}
+
+		if cut != nil {
+			// Like git, an empty side points at the line before the hunk
+			if cut.OldCount == 0 {
+				cut.OldStart--
+			}
+			if cut.NewCount == 0 {
+				cut.NewStart--
+			}
+			sliced = append(sliced, *cut)
+		}
+	}
+	return sliced
+}
+
+// ExpandHunk adds up to n lines of context above or below the given hunk, taken from the new side
+// of the file. Hunks that end up touching are merged. Returns the updated hunks and lines added.
+func ExpandHunk(hunks []DiffHunk, index int, above bool, n int, newFileLines []string) ([]DiffHunk, int) {
+	if index < 0 || index >= len(hunks) || n <= 0 {
+		return hunks, 0
+	}
+	hunk := hunks[index]
+
+	if above {
+		lowerBound := 0
+		if index > 0 {
+			lowerBound = hunks[index-1].lastNew()
+		}
+		added := min(n, hunk.firstNew()-1-lowerBound)
+		if added <= 0 || hunk.firstNew()-1 > len(newFileLines) {
+			return hunks, 0
+		}
+
+		start := hunk.firstNew() - 1 - added
+		var context []string
+		for _, line := range newFileLines[start : hunk.firstNew()-1] {
+			context = append(context, " "+line)
+		}
+
+		hunk.NewStart = hunk.firstNew() - added
+		hunk.OldStart = hunk.firstOld() - added
+		hunk.NewCount += added
+		hunk.OldCount += added
+		hunk.Lines = append(context, hunk.Lines...)
+		hunks[index] = hunk
+
+		if index > 0 && hunks[index-1].lastNew()+1 == hunk.firstNew() {
+			hunks = mergeHunks(hunks, index-1)
+		}
+		return hunks, added
+	}
+
+	upperBound := len(newFileLines)
+	if index < len(hunks)-1 {
+		upperBound = hunks[index+1].firstNew() - 1
+	}
+	added := min(n, upperBound-hunk.lastNew())
+	if added <= 0 {
+		return hunks, 0
+	}
+
+	for _, line := range newFileLines[hunk.lastNew() : hunk.lastNew()+added] {
+		hunk.Lines = append(hunk.Lines, " "+line)
+	}
+	hunk.NewStart = hunk.firstNew()
+	hunk.OldStart = hunk.firstOld()
+	hunk.NewCount += added
+	hunk.OldCount += added
+	hunks[index] = hunk
+
+	if index < len(hunks)-1 && hunk.lastNew()+1 == hunks[index+1].firstNew() {
+		hunks = mergeHunks(hunks, index)
+	}
+	return hunks, added
+}
+
+// mergeHunks joins hunks[index] and hunks[index+1], which must be adjacent
+func mergeHunks(hunks []DiffHunk, index int) []DiffHunk {
+	first, second := hunks[index], hunks[index+1]
+	merged := DiffHunk{
+		OldStart: first.firstOld(),
+		OldCount: first.OldCount + second.OldCount,
+		NewStart: first.firstNew(),
+		NewCount: first.NewCount + second.NewCount,
+		Section:  first.Section,
+		Lines:    append(append([]string{}, first.Lines...), second.Lines...),
+	}
+
+	result := append([]DiffHunk{}, hunks[:index]...)
+	result = append(result, merged)
+	return append(result, hunks[index+2:]...)
+}
+
+// ChangedLineNumbers returns the line numbers touched by the hunks, either on the new side (added lines)
+// or on the old side (removed lines)
+func ChangedLineNumbers(hunks []DiffHunk, newSide bool) map[int]bool {
+	changed := make(map[int]bool)
+	for _, hunk := range hunks {
+		oldLine, newLine := hunk.firstOld(), hunk.firstNew()
+		for _, line := range hunk.Lines {
+			switch {
+			case strings.HasPrefix(line, "+"):
+				if newSide {
+					changed[newLine] = true
+				}
+				newLine++
+			case strings.HasPrefix(line, "-"):
+				if !newSide {
+					changed[oldLine] = true
+				}
+				oldLine++
+			case strings.HasPrefix(line, "\\"):
+				// "\ No newline at end of file"
+			default:
+				oldLine++
+				newLine++
+			}
+		}
+	}
+	return changed
+}
+
+// LinePosition is the line number of a rendered diff line in the old and new file. Removed lines only have an
+// old number, added lines only a new one and hunk headers have neither.
+type LinePosition struct {
+	Old int
+	New int
+}
+
+// DisplayLinePositions returns the position of every line of the rendered diff, which drops the first hunk header
+func DisplayLinePositions(hunks []DiffHunk) []LinePosition {
+	var positions []LinePosition
+	for i, hunk := range hunks {
+		if i > 0 {
+			positions = append(positions, LinePosition{})
+		}
+		oldLine, newLine := hunk.firstOld(), hunk.firstNew()
+		for _, line := range hunk.Lines {
+			switch {
+			case strings.HasPrefix(line, "+"):
+				positions = append(positions, LinePosition{New: newLine})
+				newLine++
+			case strings.HasPrefix(line, "-"):
+				positions = append(positions, LinePosition{Old: oldLine})
+				oldLine++
+			case strings.HasPrefix(line, "\\"):
+				positions = append(positions, LinePosition{})
+			default:
+				positions = append(positions, LinePosition{Old: oldLine, New: newLine})
+				oldLine++
+				newLine++
+			}
+		}
+	}
+	return positions
+}
+
+// DisplayLineOf finds the rendered diff line a comment anchor points at: newLine in the new file when set,
+// otherwise oldLine in the old file. Returns -1 when the line is not part of the diff.
+func DisplayLineOf(positions []LinePosition, oldLine int, newLine int) int {
+	for i, position := range positions {
+		if newLine > 0 && position.New == newLine {
+			return i
+		}
+		if newLine == 0 && oldLine > 0 && position.Old == oldLine && position.New == 0 {
+			return i
+		}
+	}
+	if newLine == 0 && oldLine > 0 {
+		// The old line may also be unchanged context
+		for i, position := range positions {
+			if position.Old == oldLine {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// DiffstatFromDiff computes the diffstat of a multi-file unified diff, for APIs without a diffstat endpoint
+func DiffstatFromDiff(diffText string) []types.DiffstatEntry {
+	var entries []types.DiffstatEntry
+	for _, file := range SplitDiffFiles(diffText) {
+		entry := types.DiffstatEntry{Type: "diffstat", Status: "modified"}
+		oldPath, newPath := file.Path, file.Path
+		inHunk := false
+		for _, line := range strings.Split(file.Text, "\n") {
+			switch {
+			case strings.HasPrefix(line, "@@"):
+				inHunk = true
+			case !inHunk && strings.HasPrefix(line, "new file mode"):
+				entry.Status = "added"
+			case !inHunk && strings.HasPrefix(line, "deleted file mode"):
+				entry.Status = "removed"
+			case !inHunk && strings.HasPrefix(line, "rename from "):
+				entry.Status = "renamed"
+				oldPath = strings.TrimPrefix(line, "rename from ")
+			case !inHunk && strings.HasPrefix(line, "--- ") && line != "--- /dev/null":
+				oldPath = strings.TrimPrefix(strings.TrimPrefix(line, "--- "), "a/")
+			case inHunk && strings.HasPrefix(line, "+"):
+				entry.LinesAdded++
+			case inHunk && strings.HasPrefix(line, "-"):
+				entry.LinesRemoved++
+			}
+		}
+		if entry.Status != "added" {
+			entry.Old = &types.DiffFile{Path: oldPath, Type: "commit_file"}
+		}
+		if entry.Status != "removed" {
+			entry.New = &types.DiffFile{Path: newPath, Type: "commit_file"}
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
@@ -0,0 +1,141 @@
+package util
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+const testFileDiff = `diff --git a/f b/f
+--- a/f
++++ b/f
+@@ -1,3 +1,3 @@ func a
+ one
+-two
++TWO
+ three
+@@ -10,2 +10,3 @@
+ ten
++new
+ eleven
+`
+
+func TestParseDiffHunks(t *testing.T) {
+	header, hunks := ParseDiffHunks(testFileDiff)
+	if header != "diff --git a/f b/f\n--- a/f\n+++ b/f" {
+		t.Errorf("header = %q", header)
+	}
+	want := []DiffHunk{
+		{OldStart: 1, OldCount: 3, NewStart: 1, NewCount: 3, Section: " func a", Lines: []string{" one", "-two", "+TWO", " three"}},
+		{OldStart: 10, OldCount: 2, NewStart: 10, NewCount: 3, Lines: []string{" ten", "+new", " eleven"}},
+	}
+	if !reflect.DeepEqual(hunks, want) {
+		t.Errorf("hunks = %+v, want %+v", hunks, want)
+	}
+
+	// A missing count means a single line
+	_, hunks = ParseDiffHunks("@@ -5 +5 @@\n-a\n+b")
+	if hunks[0].OldCount != 1 || hunks[0].NewCount != 1 {
+		t.Errorf("counts = %d/%d, want 1/1", hunks[0].OldCount, hunks[0].NewCount)
+	}
+}
+
+func TestChangedLineNumbers(t *testing.T) {
+	_, hunks := ParseDiffHunks(testFileDiff)
+	if got := ChangedLineNumbers(hunks, true); !reflect.DeepEqual(got, map[int]bool{2: true, 11: true}) {
+		t.Errorf("new side = %v", got)
+	}
+	if got := ChangedLineNumbers(hunks, false); !reflect.DeepEqual(got, map[int]bool{2: true}) {
+		t.Errorf("old side = %v", got)
+	}
+}
+
+func TestExpandHunk(t *testing.T) {
+	var fileLines []string
+	for i := 1; i <= 14; i++ {
+		fileLines = append(fileLines, fmt.Sprintf("l%d", i))
+	}
+	_, hunks := ParseDiffHunks(testFileDiff)
+
+	hunks, added := ExpandHunk(hunks, 1, true, 3, fileLines)
+	if added != 3 || len(hunks) != 2 {
+		t.Fatalf("added %d, %d hunks; want 3 lines and 2 hunks", added, len(hunks))
+	}
+	if h := hunks[1]; h.OldStart != 7 || h.OldCount != 5 || h.NewStart != 7 || h.NewCount != 6 || h.Lines[0] != " l7" {
+		t.Errorf("expanded hunk = %+v", h)
+	}
+
+	// Only the lines up to the previous hunk are added, then both are merged
+	hunks, added = ExpandHunk(hunks, 1, true, 10, fileLines)
+	if added != 3 || len(hunks) != 1 {
+		t.Fatalf("added %d, %d hunks; want 3 lines and 1 hunk", added, len(hunks))
+	}
+	if h := hunks[0]; h.OldStart != 1 || h.OldCount != 11 || h.NewStart != 1 || h.NewCount != 12 || len(h.Lines) != 13 {
+		t.Errorf("merged hunk = %+v", h)
+	}
+
+	// Below the last hunk up to the end of the file
+	hunks, added = ExpandHunk(hunks, 0, false, 5, fileLines)
+	if added != 2 || hunks[0].NewCount != 14 {
+		t.Errorf("added %d, new count %d; want 2 and 14", added, hunks[0].NewCount)
+	}
+	if _, added = ExpandHunk(hunks, 0, false, 5, fileLines); added != 0 {
+		t.Errorf("added %d past the end of the file", added)
+	}
+}
+
+func TestHunkDisplayRange(t *testing.T) {
+	_, hunks := ParseDiffHunks(testFileDiff)
+	tests := []struct {
+		index    int
+		from, to int
+	}{
+		{0, 0, 3},
+		{1, 5, 7}, // After the rendered header of the second hunk
+		{2, 0, -1},
+	}
+	for _, tt := range tests {
+		if from, to := HunkDisplayRange(hunks, tt.index); from != tt.from || to != tt.to {
+			t.Errorf("HunkDisplayRange(%d) = %d, %d, want %d, %d", tt.index, from, to, tt.from, tt.to)
+		}
+	}
+}
+
+func TestSliceHunks(t *testing.T) {
+	_, hunks := ParseDiffHunks(testFileDiff)
+	tests := []struct {
+		name     string
+		from, to int
+		want     []DiffHunk
+	}{
+		{
+			name: "changed line",
+			from: 1, to: 2,
+			want: []DiffHunk{{OldStart: 2, OldCount: 1, NewStart: 2, NewCount: 1, Section: " func a", Lines: []string{"-two", "+TWO"}}},
+		},
+		{
+			name: "across hunks, reversed",
+			from: 6, to: 3,
+			want: []DiffHunk{
+				{OldStart: 3, OldCount: 1, NewStart: 3, NewCount: 1, Section: " func a", Lines: []string{" three"}},
+				{OldStart: 10, OldCount: 1, NewStart: 10, NewCount: 2, Lines: []string{" ten", "+new"}},
+			},
+		},
+		{
+			name: "added line only",
+			from: 6, to: 6,
+			want: []DiffHunk{{OldStart: 10, OldCount: 0, NewStart: 11, NewCount: 1, Lines: []string{"+new"}}},
+		},
+		{
+			name: "hunk header only",
+			from: 4, to: 4,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SliceHunks(hunks, tt.from, tt.to); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SliceHunks(%d, %d) = %+v, want %+v", tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+}
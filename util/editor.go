@@ -0,0 +1,42 @@
+package util
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// EditorCommand builds the command opening path at line in $VISUAL or $EDITOR, vi when neither is set.
+// Editors differ in how they take the line: vi style +line, code --goto and path:line for the rest.
+func EditorCommand(path string, line int) *exec.Cmd {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+	fields := strings.Fields(editor)
+
+	args := fields[1:]
+	if line > 0 {
+		switch filepath.Base(fields[0]) {
+		case "code", "codium", "cursor":
+			args = append(args, "--goto", fmt.Sprintf("%s:%d", path, line))
+		case "hx", "helix", "subl", "zed":
+			args = append(args, fmt.Sprintf("%s:%d", path, line))
+		default:
+			args = append(args, fmt.Sprintf("+%d", line), path)
+		}
+	} else {
+		args = append(args, path)
+	}
+
+	cmd := exec.Command(fields[0], args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd
+}
@@ -0,0 +1,28 @@
+package util
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEditorCommand(t *testing.T) {
+	tests := []struct {
+		visual string
+		editor string
+		line   int
+		want   []string
+	}{
+		{editor: "", line: 3, want: []string{"vi", "+3", "main.go"}},
+		{editor: "nvim", line: 0, want: []string{"nvim", "main.go"}},
+		{visual: "hx", editor: "vim", line: 3, want: []string{"hx", "main.go:3"}},
+		{editor: "/usr/bin/code --wait", line: 3, want: []string{"/usr/bin/code", "--wait", "--goto", "main.go:3"}},
+		{editor: "emacs -nw", line: 7, want: []string{"emacs", "-nw", "+7", "main.go"}},
+	}
+	for _, tt := range tests {
+		t.Setenv("VISUAL", tt.visual)
+		t.Setenv("EDITOR", tt.editor)
+		if got := EditorCommand("main.go", tt.line).Args; !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("EditorCommand with VISUAL=%q EDITOR=%q line %d = %q, want %q", tt.visual, tt.editor, tt.line, got, tt.want)
+		}
+	}
+}
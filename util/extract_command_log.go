@@ -42,3 +42,14 @@ func ExtractCommandLog(fullLog, commandName string) (string, error) {
 
 	return builder.String(), nil
 }
+
+// FailingCommandLog extracts the log segment of the last of the commands that ran. A step stops at its
+// first failing command, so in a failed step that is the command that failed.
+func FailingCommandLog(fullLog string, commands []string) (string, error) {
+	for i := len(commands) - 1; i >= 0; i-- {
+		if segment, err := ExtractCommandLog(fullLog, commands[i]); err == nil {
+			return segment, nil
+		}
+	}
+	return "", fmt.Errorf("none of the step's commands appear in its log")
+}
@@ -0,0 +1,48 @@
+package util
+
+import "testing"
+
+const testStepLog = `+ npm ci
+added 10 packages
++ npm test
+FAIL src/app.test.js
+npm ERR! Test failed.
+`
+
+func TestExtractCommandLog(t *testing.T) {
+	got, err := ExtractCommandLog(testStepLog, "npm ci")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "+ npm ci\nadded 10 packages\n"; got != want {
+		t.Errorf("ExtractCommandLog = %q, want %q", got, want)
+	}
+	if _, err := ExtractCommandLog(testStepLog, "npm run lint"); err == nil {
+		t.Error("expected an error for a command that did not run")
+	}
+}
+
+func TestFailingCommandLog(t *testing.T) {
+	tests := []struct {
+		name     string
+		commands []string
+		want     string
+		wantErr  bool
+	}{
+		{name: "last command that ran", commands: []string{"npm ci", "npm test", "npm run build"},
+			want: "+ npm test\nFAIL src/app.test.js\nnpm ERR! Test failed.\n"},
+		{name: "first command failed", commands: []string{"npm ci"}, want: "+ npm ci\nadded 10 packages\n"},
+		{name: "no command ran", commands: []string{"make"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FailingCommandLog(testStepLog, tt.commands)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("FailingCommandLog = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -4,10 +4,11 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
-	"regexp"
+	"path/filepath"
 	"simple-git-terminal/constants"
 	"simple-git-terminal/types"
 	"strings"
+	"sync"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
@@ -15,44 +16,81 @@ import (
 
 var (
 	ICON_COMMENT  = "\uf27b "
-	ICON_MARKED   = "[yellow]★[-]"
+	ICON_MARKED   = "[warning]★[-]"
 	ICON_UNMARKED = " "
 )
 
 // Get the name of the current Git repository
 // Fetches the Bitbucket workspace and repo slug based on the current git repo.
 func GetRepoAndWorkspace() (string, string, error) {
-	// Run git remote to get the remote URL
-	cmd := exec.Command("git", "remote", "get-url", "origin")
+	remote, err := GetRemote()
+	if err != nil {
+		return "", "", err
+	}
+	return remote.Workspace, remote.Repo, nil
+}
+
+// RunGit runs a git command inside the current repository and returns its stdout
+func RunGit(args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
 	cmd.Dir = getCurrentDir()
 	out, err := cmd.Output()
 	if err != nil {
-		return "", "", fmt.Errorf("failed to get git remote URL: %v", err)
+		if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+			return "", fmt.Errorf("git %s: %s", args[0], strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", fmt.Errorf("git %s: %v", args[0], err)
 	}
+	return string(out), nil
+}
 
-	// The output will look like https://bitbucket.org/workspace/repo
-	repoURL := strings.TrimSpace(string(out))
-
-	re := regexp.MustCompile(`bitbucket\.org[:/](?P<workspace>[\w-]+)/(?P<repo>[\w-]+)`)
-	matches := re.FindStringSubmatch(repoURL)
-
-	if len(matches) < 3 {
-		return "", "", fmt.Errorf("failed to parse workspace and repo from URL: %s", repoURL)
+// HasCommit reports whether the commit exists in the local object store
+func HasCommit(commitHash string) bool {
+	if commitHash == "" {
+		return false
 	}
+	_, err := RunGit("cat-file", "-e", commitHash+"^{commit}")
+	return err == nil
+}
 
-	workspace := matches[1]
-	repoSlug := matches[2]
+// ReadFileAtCommit reads a file straight from the local git object store.
+// Fails when the commit has not been fetched into the local clone.
+func ReadFileAtCommit(commitHash, path string) (string, error) {
+	if !HasCommit(commitHash) {
+		return "", fmt.Errorf("commit %s is not available locally", commitHash)
+	}
 
-	return workspace, repoSlug, nil
+	out, err := RunGit("show", commitHash+":"+path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s at %s: %v", path, commitHash, err)
+	}
+	return out, nil
 }
 
+var (
+	repoRootOnce sync.Once
+	repoRoot     string
+)
+
+// getCurrentDir returns the root of the repository bbpr was started in, from any subdirectory. Git commands
+// run there because pathspecs are relative to the working directory, while the API reports paths from the root.
 func getCurrentDir() string {
 	// For testing during local development override
 	if os.Getenv("BBPR_APP_ENV") == "development" {
 		return "/Users/srijanpersonal/personal_workspace/raw/test_repo"
-	} else {
-		return "."
 	}
+	repoRootOnce.Do(func() {
+		repoRoot = "."
+		if out, err := exec.Command("git", "rev-parse", "--show-toplevel").Output(); err == nil {
+			repoRoot = strings.TrimSpace(string(out))
+		}
+	})
+	return repoRoot
+}
+
+// RepoPath resolves a path reported by the API, relative to the repository root, in the local clone
+func RepoPath(path string) string {
+	return filepath.Join(getCurrentDir(), path)
 }
 
 // Remove diff hunks as they are unnecessary
@@ -76,16 +114,18 @@ func formatCommentWithBox(comment types.Comment) string {
 
 	commentLine := "╭" + strings.Repeat("-", borderLen) + "╮\n"
 
-	if comment.Parent.ID > 0 {
-		commentLine = commentLine + fmt.Sprintf("[steelblue] %s %s %s %s[-]", constants.ICON_SIDE_ARROW, ICON_COMMENT, comment.User.DisplayName, markdownContent)
+	if comment.Pending {
+		commentLine = commentLine + fmt.Sprintf("[warning]%s %s (Draft) %s[-]", ICON_COMMENT, comment.User.DisplayName, markdownContent)
+	} else if comment.Parent.ID > 0 {
+		commentLine = commentLine + fmt.Sprintf("[info] %s %s %s %s[-]", constants.ICON_SIDE_ARROW, ICON_COMMENT, comment.User.DisplayName, markdownContent)
 	} else {
 		// Need to check if the comment was resolved
 		if comment.Resolution != nil {
 			// Comment is resolved, show a "resolved" marker
-			commentLine = commentLine + fmt.Sprintf("[aquamarine]✔ %s %s (Resolved) %s[-]", ICON_COMMENT, comment.User.DisplayName, markdownContent)
+			commentLine = commentLine + fmt.Sprintf("[resolved]✔ %s %s (Resolved) %s[-]", ICON_COMMENT, comment.User.DisplayName, markdownContent)
 		} else {
 			// If not resolved, display it as normal
-			commentLine = commentLine + fmt.Sprintf("[steelblue]%s %s → %s[-]", ICON_COMMENT, comment.User.DisplayName, markdownContent)
+			commentLine = commentLine + fmt.Sprintf("[info]%s %s → %s[-]", ICON_COMMENT, comment.User.DisplayName, markdownContent)
 		}
 	}
 	// Add the comment line to the result
@@ -103,23 +143,20 @@ func GenerateColorizedDiffView(diffText string, comments []types.Comment) *tview
 
 	table.SetBackgroundColor(tcell.ColorDefault)
 
+	fullDiffText := diffText
 	diffText = removeBeforeAndIncludingHunk(diffText)
 	lines := strings.Split(diffText, "\n")
 	commentMap := make(map[int][]types.Comment)
 	markedLines := make(map[int]bool)
+	intraLineRendered := pairChangedLines(lines)
 
+	// Anchor every comment on the rendered line it was written on. Bitbucket's "to" is the line in the
+	// new file and "from" the line in the old file; comments on lines no longer in the diff are skipped.
+	_, hunks := ParseDiffHunks(fullDiffText)
+	positions := DisplayLinePositions(hunks)
 	for _, comment := range comments {
-		startLine := comment.Inline.From
-		endLine := comment.Inline.To
-		if startLine == 0 || endLine == 0 {
-			if startLine != 0 {
-				endLine = startLine
-			} else if endLine != 0 {
-				startLine = endLine
-			}
-		}
-		for line := startLine; line <= endLine; line++ {
-			commentMap[line] = append(commentMap[line], comment)
+		if lineIndex := DisplayLineOf(positions, comment.Inline.From, comment.Inline.To); lineIndex >= 0 {
+			commentMap[lineIndex] = append(commentMap[lineIndex], comment)
 		}
 	}
 
@@ -131,16 +168,19 @@ func GenerateColorizedDiffView(diffText string, comments []types.Comment) *tview
 		// Diff line
 		color := ""
 		if strings.HasPrefix(line, "+") {
-			color = "[green]"
+			color = "[added]"
 		} else if strings.HasPrefix(line, "-") {
-			color = "[red]"
+			color = "[removed]"
 		}
 		marked := markedLines[relativeLineNumber]
 		markIcon := ICON_UNMARKED
 		if marked {
 			markIcon = ICON_MARKED
 		}
-		lineText := fmt.Sprintf("%s[grey]%d[-] %s%s[-]", markIcon, lineNumber, color, line)
+		lineText := fmt.Sprintf("%s[muted]%d[-] %s%s[-]", markIcon, lineNumber, color, line)
+		if rendered, ok := intraLineRendered[i]; ok {
+			lineText = fmt.Sprintf("%s[muted]%d[-] %s", markIcon, lineNumber, rendered)
+		}
 		table.SetCell(row, 0, tview.NewTableCell(lineText).
 			SetExpansion(1).
 			SetReference(relativeLineNumber))
@@ -164,3 +204,79 @@ func GenerateColorizedDiffView(diffText string, comments []types.Comment) *tview
 
 	return table
 }
+
+// GenerateFullFileView renders a whole file with line numbers, marking the changed lines with a colored gutter
+func GenerateFullFileView(content string, changedLines map[int]bool, markColor string) *tview.Table {
+	table := tview.NewTable().
+		SetBorders(false).
+		SetSelectable(true, false)
+
+	table.SetBackgroundColor(tcell.ColorDefault)
+
+	lines := strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+	for i, line := range lines {
+		lineNumber := i + 1
+		gutter := " "
+		text := tview.Escape(line)
+		if changedLines[lineNumber] {
+			gutter = fmt.Sprintf("[%s]▎[-]", markColor)
+			text = fmt.Sprintf("[%s]%s[-]", markColor, text)
+		}
+		table.SetCell(i, 0, tview.NewTableCell(fmt.Sprintf("%s[muted]%d[-] %s", gutter, lineNumber, text)).
+			SetExpansion(1).
+			SetReference(lineNumber))
+	}
+
+	return table
+}
+
+// GenerateMultiFileDiffView renders a diff spanning several files (e.g. a commit or an interdiff) with a header per file
+func GenerateMultiFileDiffView(diffText string) *tview.Table {
+	table := tview.NewTable().
+		SetBorders(false).
+		SetSelectable(true, false)
+
+	table.SetBackgroundColor(tcell.ColorDefault)
+
+	files := SplitDiffFiles(diffText)
+	if len(files) == 0 {
+		table.SetCell(0, 0, tview.NewTableCell("[muted]No changes[-]"))
+		return table
+	}
+
+	row := 0
+	for _, file := range files {
+		table.SetCell(row, 0, tview.NewTableCell(fmt.Sprintf("[info::b]%s[-::-]", tview.Escape(file.Path))).
+			SetExpansion(1).
+			SetReference(file.Path))
+		row++
+
+		_, hunks := ParseDiffHunks(file.Text)
+		for _, hunk := range hunks {
+			table.SetCell(row, 0, tview.NewTableCell(fmt.Sprintf("[muted]@@ -%d,%d +%d,%d @@%s[-]", hunk.OldStart, hunk.OldCount, hunk.NewStart, hunk.NewCount, tview.Escape(hunk.Section))).
+				SetReference(file.Path))
+			row++
+
+			intraLineRendered := pairChangedLines(hunk.Lines)
+			for i, line := range hunk.Lines {
+				text, ok := intraLineRendered[i]
+				if !ok {
+					color := "-"
+					if strings.HasPrefix(line, "+") {
+						color = "added"
+					} else if strings.HasPrefix(line, "-") {
+						color = "removed"
+					}
+					text = fmt.Sprintf("[%s]%s[-]", color, tview.Escape(line))
+				}
+				table.SetCell(row, 0, tview.NewTableCell(text).
+					SetExpansion(1).
+					SetReference(file.Path))
+				row++
+			}
+		}
+		row++ // blank line between files
+	}
+
+	return table
+}
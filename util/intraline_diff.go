@@ -0,0 +1,204 @@
+package util
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/rivo/tview"
+)
+
+const (
+	// Lines sharing less than this ratio of characters are treated as rewrites, not edits
+	intraLineMinSimilarity = 0.4
+	// Guard against quadratic blowup on very long (minified) lines
+	intraLineMaxTokens = 400
+)
+
+type diffSegment struct {
+	text    string
+	changed bool
+}
+
+// tokenizeLine splits a line into words, whitespace runs and single punctuation characters
+func tokenizeLine(line string) []string {
+	var tokens []string
+	var current strings.Builder
+	kind := 0 // 0 = none, 1 = word, 2 = space
+
+	flush := func() {
+		if current.Len() > 0 {
+			tokens = append(tokens, current.String())
+			current.Reset()
+		}
+		kind = 0
+	}
+
+	for _, r := range line {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_':
+			if kind != 1 {
+				flush()
+				kind = 1
+			}
+			current.WriteRune(r)
+		case unicode.IsSpace(r):
+			if kind != 2 {
+				flush()
+				kind = 2
+			}
+			current.WriteRune(r)
+		default:
+			flush()
+			tokens = append(tokens, string(r))
+		}
+	}
+	flush()
+
+	return tokens
+}
+
+// diffTokens runs an LCS over both token lists and marks tokens that are not part of it as changed.
+// Returns the number of characters both sides have in common.
+func diffTokens(a, b []string) ([]diffSegment, []diffSegment, int) {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var aSegs, bSegs []diffSegment
+	common := 0
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			aSegs = appendSegment(aSegs, a[i], false)
+			bSegs = appendSegment(bSegs, b[j], false)
+			common += len(a[i])
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			aSegs = appendSegment(aSegs, a[i], true)
+			i++
+		default:
+			bSegs = appendSegment(bSegs, b[j], true)
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		aSegs = appendSegment(aSegs, a[i], true)
+	}
+	for ; j < len(b); j++ {
+		bSegs = appendSegment(bSegs, b[j], true)
+	}
+
+	return aSegs, bSegs, common
+}
+
+// appendSegment merges consecutive tokens of the same kind so the rendered tags stay small
+func appendSegment(segments []diffSegment, text string, changed bool) []diffSegment {
+	if n := len(segments); n > 0 && segments[n-1].changed == changed {
+		segments[n-1].text += text
+		return segments
+	}
+	return append(segments, diffSegment{text: text, changed: changed})
+}
+
+// renderSegments renders a diff line in the given color, with changed segments shown in reverse video
+func renderSegments(prefix string, segments []diffSegment, color string) string {
+	var sb strings.Builder
+	sb.WriteString("[" + color + "]" + prefix)
+	for _, segment := range segments {
+		if segment.changed {
+			sb.WriteString("[" + color + "::r]" + tview.Escape(segment.text) + "[" + color + "::-]")
+		} else {
+			sb.WriteString(tview.Escape(segment.text))
+		}
+	}
+	sb.WriteString("[-]")
+	return sb.String()
+}
+
+// HighlightLinePair renders a removed and an added line with only the changed words highlighted.
+// Both lines are expected with their leading "-"/"+" marker. Returns false when the lines are
+// too different for word-level highlighting to be useful.
+func HighlightLinePair(removed, added string) (string, string, bool) {
+	oldText := strings.TrimPrefix(removed, "-")
+	newText := strings.TrimPrefix(added, "+")
+
+	oldTokens := tokenizeLine(oldText)
+	newTokens := tokenizeLine(newText)
+	if len(oldTokens) > intraLineMaxTokens || len(newTokens) > intraLineMaxTokens {
+		return "", "", false
+	}
+
+	oldSegs, newSegs, common := diffTokens(oldTokens, newTokens)
+
+	longest := max(len(oldText), len(newText))
+	if longest == 0 || float64(common)/float64(longest) < intraLineMinSimilarity {
+		return "", "", false
+	}
+
+	return renderSegments("-", oldSegs, "removed"), renderSegments("+", newSegs, "added"), true
+}
+
+// pairChangedLines walks the diff lines and pairs each run of removed lines with the run of
+// added lines following it, returning the pre-rendered text keyed by line index.
+func pairChangedLines(lines []string) map[int]string {
+	rendered := make(map[int]string)
+
+	// lines are hunk bodies. File headers, whose ---/+++ lines look like changes, only follow a
+	// "diff " line up to the next hunk; a removed "-- comment" line inside a hunk is a change.
+	inHunk := make([]bool, len(lines))
+	hunk := true
+	for i, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "diff "):
+			hunk = false
+		case strings.HasPrefix(line, "@@"):
+			hunk = true
+		}
+		inHunk[i] = hunk
+	}
+	isRemoved := func(i int) bool { return inHunk[i] && strings.HasPrefix(lines[i], "-") }
+	isAdded := func(i int) bool { return inHunk[i] && strings.HasPrefix(lines[i], "+") }
+
+	for i := 0; i < len(lines); {
+		if !isRemoved(i) {
+			i++
+			continue
+		}
+
+		removedStart := i
+		for i < len(lines) && isRemoved(i) {
+			i++
+		}
+		addedStart := i
+		for i < len(lines) && isAdded(i) {
+			i++
+		}
+
+		removedCount := addedStart - removedStart
+		addedCount := i - addedStart
+		for k := 0; k < min(removedCount, addedCount); k++ {
+			oldLine, newLine, ok := HighlightLinePair(lines[removedStart+k], lines[addedStart+k])
+			if !ok {
+				continue
+			}
+			rendered[removedStart+k] = oldLine
+			rendered[addedStart+k] = newLine
+		}
+	}
+
+	return rendered
+}
@@ -0,0 +1,112 @@
+package util
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTokenizeLine(t *testing.T) {
+	tests := []struct {
+		line string
+		want []string
+	}{
+		{"", nil},
+		{"foo_bar1", []string{"foo_bar1"}},
+		{"a  b", []string{"a", "  ", "b"}},
+		{"x := f(y)", []string{"x", " ", ":", "=", " ", "f", "(", "y", ")"}},
+	}
+	for _, tt := range tests {
+		if got := tokenizeLine(tt.line); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("tokenizeLine(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestHighlightLinePair(t *testing.T) {
+	tests := []struct {
+		name        string
+		removed     string
+		added       string
+		ok          bool
+		wantRemoved string
+		wantAdded   string
+	}{
+		{
+			name:        "changed word",
+			removed:     "-return a + b",
+			added:       "+return a - b",
+			ok:          true,
+			wantRemoved: "[removed]-return a [removed::r]+[removed::-] b[-]",
+			wantAdded:   "[added]+return a [added::r]-[added::-] b[-]",
+		},
+		{
+			name:    "rewritten line",
+			removed: "-completely different",
+			added:   "+x",
+		},
+		{
+			name:    "empty lines",
+			removed: "-",
+			added:   "+",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotRemoved, gotAdded, ok := HighlightLinePair(tt.removed, tt.added)
+			if ok != tt.ok {
+				t.Fatalf("ok = %v, want %v", ok, tt.ok)
+			}
+			if gotRemoved != tt.wantRemoved || gotAdded != tt.wantAdded {
+				t.Errorf("got %q / %q, want %q / %q", gotRemoved, gotAdded, tt.wantRemoved, tt.wantAdded)
+			}
+		})
+	}
+}
+
+func TestPairChangedLines(t *testing.T) {
+	tests := []struct {
+		name   string
+		lines  []string
+		paired []int
+	}{
+		{
+			name:   "removed run followed by added run",
+			lines:  []string{" ctx", "-foo(1)", "-bar(1)", "+foo(2)", "+bar(2)", " ctx"},
+			paired: []int{1, 2, 3, 4},
+		},
+		{
+			name:   "unpaired added lines",
+			lines:  []string{"-foo(1)", "+foo(2)", "+extra line"},
+			paired: []int{0, 1},
+		},
+		{
+			name:   "removed SQL comment inside a hunk",
+			lines:  []string{"--- old comment here", "+-- new comment here"},
+			paired: []int{0, 1},
+		},
+		{
+			name: "file header of a following file",
+			lines: []string{
+				"-foo(1)", "+foo(2)",
+				"diff --git a/x b/x", "--- a/x value", "+++ b/x value",
+				"@@ -1 +1 @@", "-bar(1)", "+bar(2)",
+			},
+			paired: []int{0, 1, 6, 7},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rendered := pairChangedLines(tt.lines)
+			var got []int
+			for i := range tt.lines {
+				if _, ok := rendered[i]; ok {
+					got = append(got, i)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.paired) {
+				t.Errorf("paired lines %v, want %v (%s)", got, tt.paired, strings.Join(tt.lines, " | "))
+			}
+		})
+	}
+}
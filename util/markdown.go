@@ -5,17 +5,18 @@ import (
 	"github.com/charmbracelet/glamour"
 	"github.com/rivo/tview"
 	"log"
+	"simple-git-terminal/theme"
 	"strings"
 )
 
 // Global variable to store the renderer instance
 var renderer *glamour.TermRenderer
 
-// Initialize the renderer once and reuse it
+// Initialize the renderer once and reuse it. The style follows the theme.
 func InitMdRenderer() {
 	var err error
 	renderer, err = glamour.NewTermRenderer(
-		glamour.WithAutoStyle(),
+		glamour.WithStylePath(theme.Current.Markdown),
 		glamour.WithWordWrap(0),
 	)
 	if err != nil {
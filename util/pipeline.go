@@ -2,6 +2,7 @@ package util
 
 import (
 	"fmt"
+	"simple-git-terminal/theme"
 	"simple-git-terminal/types"
 
 	"github.com/gdamore/tcell/v2"
@@ -11,23 +12,23 @@ import (
 func GetColorForStatus(status types.PipelineStatus) tcell.Color {
 	switch {
 	case status.Failed():
-		return tcell.ColorRed
+		return theme.Current.Danger
 	case status.Passed():
-		return tcell.ColorGreen
+		return theme.Current.Success
 	case status.Running():
-		return tcell.ColorYellow
+		return theme.Current.Warning
 	case status.Successful():
-		return tcell.ColorGreen
+		return theme.Current.Success
 	case status.Pending():
-		return tcell.ColorBlue
+		return theme.Current.Link
 	case status.Error():
-		return tcell.ColorDarkRed
+		return theme.Current.Critical
 	case status.InProgress():
-		return tcell.ColorOrange
+		return theme.Current.Accent
 	case status.NotRun():
-		return tcell.ColorGray
+		return theme.Current.Muted
 	default:
-		return tcell.ColorGray
+		return theme.Current.Muted
 	}
 }
 
@@ -54,27 +55,6 @@ func GetIconForStatus(status types.PipelineStatus) string {
 	}
 }
 
-func tcellColorToDynamicColor(c tcell.Color) string {
-	switch c {
-	case tcell.ColorGreen:
-		return "green"
-	case tcell.ColorRed:
-		return "red"
-	case tcell.ColorYellow:
-		return "yellow"
-	case tcell.ColorGray:
-		return "gray"
-	case tcell.ColorDarkRed:
-		return "maroon"
-	case tcell.ColorOrange:
-		return "orange"
-	case tcell.ColorBlue:
-		return "blue"
-	default:
-		return "white"
-	}
-}
-
 func GetIconForStatusWithColorAnimated(status types.PipelineStatus, frame int) string {
 	var icon string
 	//
@@ -99,12 +79,12 @@ func GetIconForStatusWithColorAnimated(status types.PipelineStatus, frame int) s
 	}
 
 	color := GetColorForStatus(status)
-	return fmt.Sprintf("[%s]%s[-]", tcellColorToDynamicColor(color), icon)
+	return fmt.Sprintf("[%s]%s[-]", color, icon)
 }
 
 func GetIconForStatusWithColor(status types.PipelineStatus) string {
 	icon := GetIconForStatus(status)
 	color := GetColorForStatus(status)
 
-	return fmt.Sprintf("[%s]%s[-]", tcellColorToDynamicColor(color), icon)
+	return fmt.Sprintf("[%s]%s[-]", color, icon)
 }
@@ -0,0 +1,303 @@
+package util
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/term"
+)
+
+const BitbucketCloudHost = "bitbucket.org"
+
+// Data Center serves git over ssh on this port by default
+const dataCenterSSHPort = "7999"
+
+// DataCenterHost is the host of the configured Data Center server. Remotes on other hosts are only
+// recognised as Data Center by the /scm/ path of http remotes or the 7999 ssh port.
+var DataCenterHost string
+
+// RemoteOverride is set from the -workspace and -repo flags and skips remote detection
+var RemoteOverride Remote
+
+// Remote is a parsed git remote of a Bitbucket repository
+type Remote struct {
+	Name      string // Name of the git remote, e.g. origin
+	Host      string
+	Workspace string // Cloud workspace, or Data Center project key
+	Repo      string
+	BaseURL   string // Data Center server URL including any context path, empty for Cloud
+}
+
+func (r Remote) IsCloud() bool {
+	return r.Host == BitbucketCloudHost
+}
+
+func (r Remote) sameRepo(other Remote) bool {
+	return r.Host == other.Host && strings.EqualFold(r.Workspace, other.Workspace) && strings.EqualFold(r.Repo, other.Repo)
+}
+
+var (
+	remoteOnce     sync.Once
+	resolvedRemote Remote
+	remoteErr      error
+)
+
+// GetRemote returns the Bitbucket repository of the current git repository. All remotes are considered;
+// when they point at different repositories the choice is asked once and kept in the bbpr.remote git config.
+func GetRemote() (Remote, error) {
+	if RemoteOverride.Workspace != "" && RemoteOverride.Repo != "" {
+		return RemoteOverride, nil
+	}
+	remoteOnce.Do(func() {
+		resolvedRemote, remoteErr = resolveRemote()
+		if remoteErr == nil {
+			log.Printf("[REMOTE] Using remote %s: %s/%s on %s", resolvedRemote.Name, resolvedRemote.Workspace, resolvedRemote.Repo, resolvedRemote.Host)
+		}
+	})
+	return resolvedRemote, remoteErr
+}
+
+func resolveRemote() (Remote, error) {
+	out, err := RunGit("remote")
+	if err != nil {
+		return Remote{}, fmt.Errorf("not inside a git repository, pass -workspace and -repo: %v", err)
+	}
+	names := strings.Fields(out)
+
+	var candidates []Remote
+	for _, name := range names {
+		remoteURL, err := RunGit("remote", "get-url", name)
+		if err != nil {
+			continue
+		}
+		remote, err := ParseRemoteURL(strings.TrimSpace(remoteURL))
+		if err != nil {
+			log.Printf("[REMOTE] Skipping remote %s: %v", name, err)
+			continue
+		}
+		remote.Name = name
+		candidates = append(candidates, remote)
+	}
+
+	candidates = distinctRemotes(candidates)
+	switch len(candidates) {
+	case 0:
+		return Remote{}, fmt.Errorf("none of the remotes %v is a Bitbucket repository, pass -workspace and -repo", names)
+	case 1:
+		return candidates[0], nil
+	}
+
+	if saved, err := RunGit("config", "--local", "bbpr.remote"); err == nil {
+		for _, candidate := range candidates {
+			if candidate.Name == strings.TrimSpace(saved) {
+				return candidate, nil
+			}
+		}
+	}
+
+	chosen, asked := chooseRemote(candidates, os.Stdin, os.Stderr)
+	if asked {
+		if _, err := RunGit("config", "--local", "bbpr.remote", chosen.Name); err != nil {
+			log.Printf("[REMOTE] Could not remember the remote: %v", err)
+		}
+	}
+	return chosen, nil
+}
+
+// distinctRemotes drops remotes pointing at a repository an earlier one already points at,
+// keeping origin over the others
+func distinctRemotes(remotes []Remote) []Remote {
+	var distinct []Remote
+	for _, remote := range remotes {
+		duplicate := false
+		for i, kept := range distinct {
+			if kept.sameRepo(remote) {
+				duplicate = true
+				if remote.Name == "origin" {
+					distinct[i] = remote
+				}
+			}
+		}
+		if !duplicate {
+			distinct = append(distinct, remote)
+		}
+	}
+	return distinct
+}
+
+// chooseRemote asks which remote to use on a terminal. Otherwise origin, or else the first remote, is used.
+// Reports whether the user was asked.
+func chooseRemote(remotes []Remote, in *os.File, out io.Writer) (Remote, bool) {
+	fallback := remotes[0]
+	for _, remote := range remotes {
+		if remote.Name == "origin" {
+			fallback = remote
+		}
+	}
+	if !term.IsTerminal(int(in.Fd())) {
+		log.Printf("[REMOTE] Several remotes match, using %s", fallback.Name)
+		return fallback, false
+	}
+
+	fmt.Fprintln(out, "Several remotes point at Bitbucket repositories:")
+	for i, remote := range remotes {
+		fmt.Fprintf(out, "  %d) %s\t%s/%s on %s\n", i+1, remote.Name, remote.Workspace, remote.Repo, remote.Host)
+	}
+	fmt.Fprintf(out, "Use which one? [1-%d]: ", len(remotes))
+
+	line, _ := bufio.NewReader(in).ReadString('\n')
+	choice, err := strconv.Atoi(strings.TrimSpace(line))
+	if err != nil || choice < 1 || choice > len(remotes) {
+		fmt.Fprintf(out, "Using %s\n", fallback.Name)
+		return fallback, true
+	}
+	return remotes[choice-1], true
+}
+
+// ParseRemoteURL understands Cloud remotes (https://bitbucket.org/ws/repo, git@bitbucket.org:ws/repo.git) and
+// Data Center remotes (https://host/context/scm/PROJECT/repo.git, ssh://git@host:7999/project/repo.git).
+// SSH host aliases are resolved through ~/.ssh/config.
+func ParseRemoteURL(remoteURL string) (Remote, error) {
+	var scheme, hostname, port, repoPath string
+	if strings.Contains(remoteURL, "://") {
+		parsed, err := url.Parse(remoteURL)
+		if err != nil {
+			return Remote{}, fmt.Errorf("failed to parse remote URL %s: %v", remoteURL, err)
+		}
+		scheme, hostname, port, repoPath = parsed.Scheme, parsed.Hostname(), parsed.Port(), parsed.Path
+	} else if at, rest, ok := strings.Cut(remoteURL, ":"); ok && !strings.Contains(at, "/") {
+		// scp-like syntax, user@host:path
+		scheme, repoPath = "ssh", rest
+		hostname = at[strings.LastIndex(at, "@")+1:]
+	} else {
+		return Remote{}, fmt.Errorf("failed to parse workspace and repo from URL: %s", remoteURL)
+	}
+
+	if scheme == "ssh" || scheme == "git+ssh" {
+		aliasHost, aliasPort := resolveSSHHost(hostname)
+		hostname = aliasHost
+		if port == "" {
+			port = aliasPort
+		}
+	}
+
+	parts := strings.Split(strings.Trim(strings.TrimSuffix(repoPath, ".git"), "/"), "/")
+	if len(parts) < 2 || parts[len(parts)-2] == "" || parts[len(parts)-1] == "" {
+		return Remote{}, fmt.Errorf("failed to parse workspace and repo from URL: %s", remoteURL)
+	}
+	remote := Remote{
+		Host:      hostname,
+		Workspace: parts[len(parts)-2],
+		Repo:      parts[len(parts)-1],
+	}
+	if remote.IsCloud() {
+		return remote, nil
+	}
+
+	isHTTP := scheme == "http" || scheme == "https"
+	if hostname != DataCenterHost && !(isHTTP && strings.Contains(repoPath, "/scm/")) && port != dataCenterSSHPort {
+		return Remote{}, fmt.Errorf("%s is not a Bitbucket remote, set provider.base_url for a Data Center server", remoteURL)
+	}
+
+	// Data Center serves git over http(s) below /scm, behind an optional context path, and the
+	// REST API next to it. Over ssh only the host is known, the API is assumed on https.
+	remote.Workspace = strings.ToUpper(remote.Workspace)
+	if isHTTP {
+		context, _, _ := strings.Cut(repoPath, "/scm/")
+		host := hostname
+		if port != "" {
+			host += ":" + port
+		}
+		remote.BaseURL = fmt.Sprintf("%s://%s%s", scheme, host, strings.TrimSuffix(context, "/"))
+	} else {
+		remote.BaseURL = "https://" + hostname
+	}
+	return remote, nil
+}
+
+// sshConfigPath is a variable so tests can point it at their own SSH config
+var sshConfigPath = func() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".ssh", "config")
+}
+
+// resolveSSHHost maps an SSH host alias to its HostName and Port from ~/.ssh/config. Aliases such as
+// bitbucket.org-work without a config entry are taken as bitbucket.org, a common multi account convention.
+func resolveSSHHost(alias string) (string, string) {
+	hostname, port := alias, ""
+	if file, err := os.Open(sshConfigPath()); err == nil {
+		hostname, port = parseSSHConfig(file, alias)
+		file.Close()
+	}
+	if hostname == alias && (strings.HasPrefix(alias, BitbucketCloudHost+"-") || strings.HasPrefix(alias, BitbucketCloudHost+"_")) {
+		hostname = BitbucketCloudHost
+	}
+	return hostname, port
+}
+
+// parseSSHConfig returns the HostName and Port of alias. Like ssh, the first value found for each wins.
+// Include and Match directives are not followed.
+func parseSSHConfig(r io.Reader, alias string) (string, string) {
+	hostname, port := "", ""
+	matching := true // Options before the first Host apply to every host
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		// Keywords are separated from their value by whitespace, tabs included, and/or a single =
+		end := strings.IndexAny(line, " \t=")
+		if end < 0 {
+			continue
+		}
+		key, value := line[:end], line[end:]
+		value = strings.Trim(strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(value), "=")), `"`)
+
+		switch strings.ToLower(key) {
+		case "host":
+			matching = hostPatternMatches(strings.Fields(value), alias)
+		case "match":
+			matching = false
+		case "hostname":
+			if matching && hostname == "" {
+				hostname = strings.ReplaceAll(value, "%h", alias)
+			}
+		case "port":
+			if matching && port == "" {
+				port = value
+			}
+		}
+	}
+	if hostname == "" {
+		hostname = alias
+	}
+	return hostname, port
+}
+
+// hostPatternMatches applies ssh's Host patterns, where a leading ! negates
+func hostPatternMatches(patterns []string, alias string) bool {
+	matched := false
+	for _, pattern := range patterns {
+		negated := strings.HasPrefix(pattern, "!")
+		if ok, _ := path.Match(strings.TrimPrefix(pattern, "!"), alias); ok {
+			if negated {
+				return false
+			}
+			matched = true
+		}
+	}
+	return matched
+}
@@ -0,0 +1,166 @@
+package util
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testSSHConfig = `# Options before the first Host apply to every host
+Host bb
+	HostName	bitbucket.org
+Host work
+  HostName=bitbucket.org
+  Port 2222
+Match host matched
+  HostName github.com
+Host matched
+  HostName bitbucket.org
+Host bitbucket-*
+  HostName bitbucket.org
+Host dc
+  HostName "git.example.com"
+  Port 7999
+`
+
+// useSSHConfig points sshConfigPath at a file with content for the duration of the test
+func useSSHConfig(t *testing.T, content string) {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(file, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	previous := sshConfigPath
+	sshConfigPath = func() string { return file }
+	t.Cleanup(func() { sshConfigPath = previous })
+}
+
+func TestParseRemoteURL(t *testing.T) {
+	useSSHConfig(t, testSSHConfig)
+	previousHost := DataCenterHost
+	DataCenterHost = "dc.example.com"
+	defer func() { DataCenterHost = previousHost }()
+
+	tests := []struct {
+		name    string
+		url     string
+		want    Remote
+		wantErr bool
+	}{
+		{name: "cloud https", url: "https://bitbucket.org/ws/repo.git",
+			want: Remote{Host: "bitbucket.org", Workspace: "ws", Repo: "repo"}},
+		{name: "cloud https with user", url: "https://me@bitbucket.org/ws/repo",
+			want: Remote{Host: "bitbucket.org", Workspace: "ws", Repo: "repo"}},
+		{name: "cloud scp", url: "git@bitbucket.org:ws/repo.git",
+			want: Remote{Host: "bitbucket.org", Workspace: "ws", Repo: "repo"}},
+		{name: "cloud ssh", url: "ssh://git@bitbucket.org/ws/repo.git",
+			want: Remote{Host: "bitbucket.org", Workspace: "ws", Repo: "repo"}},
+		{name: "alias without config entry", url: "git@bitbucket.org-work:ws/repo.git",
+			want: Remote{Host: "bitbucket.org", Workspace: "ws", Repo: "repo"}},
+		{name: "alias with tab separated HostName", url: "git@bb:ws/repo.git",
+			want: Remote{Host: "bitbucket.org", Workspace: "ws", Repo: "repo"}},
+		{name: "alias with = separated HostName", url: "ssh://git@work/ws/repo.git",
+			want: Remote{Host: "bitbucket.org", Workspace: "ws", Repo: "repo"}},
+		{name: "Match block is not followed", url: "git@matched:ws/repo.git",
+			want: Remote{Host: "bitbucket.org", Workspace: "ws", Repo: "repo"}},
+		{name: "wildcard Host", url: "git@bitbucket-personal:ws/repo.git",
+			want: Remote{Host: "bitbucket.org", Workspace: "ws", Repo: "repo"}},
+		{name: "data center https below /scm/", url: "https://git.example.com/bitbucket/scm/proj/repo.git",
+			want: Remote{Host: "git.example.com", Workspace: "PROJ", Repo: "repo", BaseURL: "https://git.example.com/bitbucket"}},
+		{name: "data center https with port", url: "http://git.example.com:7990/scm/proj/repo.git",
+			want: Remote{Host: "git.example.com", Workspace: "PROJ", Repo: "repo", BaseURL: "http://git.example.com:7990"}},
+		{name: "data center ssh port", url: "ssh://git@git.example.com:7999/proj/repo.git",
+			want: Remote{Host: "git.example.com", Workspace: "PROJ", Repo: "repo", BaseURL: "https://git.example.com"}},
+		{name: "data center ssh alias with port", url: "git@dc:proj/repo.git",
+			want: Remote{Host: "git.example.com", Workspace: "PROJ", Repo: "repo", BaseURL: "https://git.example.com"}},
+		{name: "configured data center host", url: "git@dc.example.com:proj/repo.git",
+			want: Remote{Host: "dc.example.com", Workspace: "PROJ", Repo: "repo", BaseURL: "https://dc.example.com"}},
+		{name: "other host", url: "https://github.com/ws/repo.git", wantErr: true},
+		{name: "no repository", url: "https://bitbucket.org/ws", wantErr: true},
+		{name: "local path", url: "/srv/git/repo.git", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRemoteURL(tt.url)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRemoteURL(%q) err = %v, wantErr %v", tt.url, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseRemoteURL(%q) = %+v, want %+v", tt.url, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSSHConfig(t *testing.T) {
+	tests := []struct {
+		alias        string
+		wantHostname string
+		wantPort     string
+	}{
+		{"bb", "bitbucket.org", ""},
+		{"work", "bitbucket.org", "2222"},
+		{"matched", "bitbucket.org", ""},
+		{"bitbucket-anything", "bitbucket.org", ""},
+		{"dc", "git.example.com", "7999"},
+		{"unknown", "unknown", ""},
+	}
+	for _, tt := range tests {
+		hostname, port := parseSSHConfig(strings.NewReader(testSSHConfig), tt.alias)
+		if hostname != tt.wantHostname || port != tt.wantPort {
+			t.Errorf("parseSSHConfig(%q) = %q, %q, want %q, %q", tt.alias, hostname, port, tt.wantHostname, tt.wantPort)
+		}
+	}
+}
+
+func TestHostPatternMatches(t *testing.T) {
+	tests := []struct {
+		patterns string
+		alias    string
+		want     bool
+	}{
+		{"bb", "bb", true},
+		{"bb work", "work", true},
+		{"bitbucket-*", "bitbucket-work", true},
+		{"* !bb", "bb", false},
+		{"* !bb", "work", true},
+		{"b?", "bb", true},
+		{"other", "bb", false},
+	}
+	for _, tt := range tests {
+		if got := hostPatternMatches(strings.Fields(tt.patterns), tt.alias); got != tt.want {
+			t.Errorf("hostPatternMatches(%q, %q) = %v, want %v", tt.patterns, tt.alias, got, tt.want)
+		}
+	}
+}
+
+func TestDistinctRemotes(t *testing.T) {
+	upstream := Remote{Name: "upstream", Host: "bitbucket.org", Workspace: "ws", Repo: "repo"}
+	origin := Remote{Name: "origin", Host: "bitbucket.org", Workspace: "WS", Repo: "Repo"}
+	fork := Remote{Name: "fork", Host: "bitbucket.org", Workspace: "me", Repo: "repo"}
+
+	got := distinctRemotes([]Remote{upstream, fork, origin})
+	if len(got) != 2 || got[0] != origin || got[1] != fork {
+		t.Errorf("distinctRemotes = %+v, want origin and fork", got)
+	}
+}
+
+func TestChooseRemoteWithoutTerminal(t *testing.T) {
+	// A regular file is no terminal, like stdin in a script
+	in, err := os.CreateTemp(t.TempDir(), "stdin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer in.Close()
+
+	remotes := []Remote{
+		{Name: "fork", Workspace: "me", Repo: "repo"},
+		{Name: "origin", Workspace: "ws", Repo: "repo"},
+	}
+	chosen, asked := chooseRemote(remotes, in, io.Discard)
+	if asked || chosen.Name != "origin" {
+		t.Errorf("chooseRemote = %s, asked %v; want origin without asking", chosen.Name, asked)
+	}
+}
@@ -0,0 +1,32 @@
+package util
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// WriteToTerminal writes an escape sequence straight to the controlling terminal, past the screen drawn by tview
+func WriteToTerminal(sequence string) error {
+	tty, err := os.OpenFile("/dev/tty", os.O_WRONLY, 0)
+	if err != nil {
+		return fmt.Errorf("could not open terminal: %w", err)
+	}
+	defer tty.Close()
+
+	if _, err := tty.WriteString(sequence); err != nil {
+		return fmt.Errorf("could not write to terminal: %w", err)
+	}
+	return nil
+}
+
+// CopyToClipboard sets the system clipboard with OSC 52, which the terminal also honours over SSH.
+// Inside tmux the sequence is passed through to the outer terminal.
+func CopyToClipboard(text string) error {
+	sequence := fmt.Sprintf("\x1b]52;c;%s\x07", base64.StdEncoding.EncodeToString([]byte(text)))
+	if os.Getenv("TMUX") != "" {
+		sequence = "\x1bPtmux;" + strings.ReplaceAll(sequence, "\x1b", "\x1b\x1b") + "\x1b\\"
+	}
+	return WriteToTerminal(sequence)
+}
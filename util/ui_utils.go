@@ -3,6 +3,7 @@ package util
 
 import (
 	"simple-git-terminal/constants"
+	"simple-git-terminal/theme"
 	"simple-git-terminal/types"
 
 	"github.com/gdamore/tcell/v2"
@@ -12,24 +13,24 @@ import (
 func GetPRStateColor(state string) tcell.Color {
 	switch state {
 	case "OPEN":
-		return tcell.ColorLawnGreen
+		return theme.Current.Open
 	case "MERGED":
-		return tcell.ColorBlue
+		return theme.Current.Link
 	case "DECLINED":
-		return tcell.ColorRed
+		return theme.Current.Danger
 	default:
-		return tcell.ColorYellow
+		return theme.Current.Warning
 	}
 }
 
 func GetPRReviewStateIcon(state types.ApprovedState) string {
 	switch state {
 	case types.StateApproved:
-		return "[green]" + constants.ICON_ACTIVE + "[-]"
+		return "[success]" + constants.ICON_ACTIVE + "[-]"
 	case types.StateDeclined:
-		return "[red]" + constants.ICON_DECLINED + "[-]"
+		return "[danger]" + constants.ICON_DECLINED + "[-]"
 	case types.StateRequestedChanges:
-		return "[yellow]" + constants.ICON_WARNING + "[-]"
+		return "[warning]" + constants.ICON_WARNING + "[-]"
 	default:
 		return ""
 	}
@@ -38,11 +39,11 @@ func GetPRReviewStateIcon(state types.ApprovedState) string {
 func GetFieldBasedColor(field string) tcell.Color {
 	switch field {
 	case "title":
-		return tcell.ColorDarkCyan
+		return theme.Current.Field
 	case "description":
-		return tcell.ColorOrange
+		return theme.Current.Accent
 	default:
-		return tcell.ColorWhite
+		return theme.Current.Text
 	}
 }
 
@@ -3,6 +3,7 @@ package util
 import (
 	"log"
 	"net/url"
+	"strings"
 )
 
 func ExtractQueryFromNextURL(nextURL string) string {
@@ -13,3 +14,12 @@ func ExtractQueryFromNextURL(nextURL string) string {
 	}
 	return parsed.RawQuery // returns "page=2&pagelen=10" etc.
 }
+
+// EscapePath escapes each segment of a slash separated path for use in a URL, keeping the slashes
+func EscapePath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
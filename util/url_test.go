@@ -0,0 +1,21 @@
+package util
+
+import "testing"
+
+func TestEscapePath(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "main.go", want: "main.go"},
+		{path: "src/pkg/main.go", want: "src/pkg/main.go"},
+		{path: "docs/release notes.md", want: "docs/release%20notes.md"},
+		{path: "a/b#c?.go", want: "a/b%23c%3F.go"},
+		{path: "web/100%.css", want: "web/100%25.css"},
+	}
+	for _, tt := range tests {
+		if got := EscapePath(tt.path); got != tt.want {
+			t.Errorf("EscapePath(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
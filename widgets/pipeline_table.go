@@ -3,6 +3,7 @@ package widgets
 import (
 	"fmt"
 	"simple-git-terminal/constants"
+	"simple-git-terminal/theme"
 	"simple-git-terminal/types"
 	"simple-git-terminal/util"
 	widgets "simple-git-terminal/widgets/table"
@@ -77,22 +78,22 @@ func (pt *PipelineTable) SetPipelines(pps []types.PipelineResponse, frame int) {
 			startStr = " Unknown"
 		}
 
-		selectedCell := util.CellFormat(constants.ICON_SELECTED, tcell.ColorOrange)
+		selectedCell := util.CellFormat(constants.ICON_SELECTED, theme.Current.Accent)
 
 		// no need to check what is selcted at this point, as this is very first time, select first row already
 		if i == 0 {
 			pt.SetCell(i, 0, selectedCell)
 		}
 
-		pt.SetCell(i, 1, util.CellFormat(util.FormatInitials(pp.Creator.DisplayName), constants.HIGH_CONTRAST_COLOR))      // Initial
-		pt.SetCell(i, 2, util.CellFormat(fmt.Sprintf("%s %d", constants.ICON_BUILD, pp.BuildNumber), tcell.ColorDarkGray)) // Build #
-		pt.SetCell(i, 4, util.CellFormat(fmt.Sprintf(" %s", shortHash), tcell.ColorDarkGray))                             // Commit
-		pt.SetCell(i, 5, util.CellFormat(fmt.Sprintf(" %s", pp.Target.RefName), tcell.ColorDarkGray))                     // Branch
+		pt.SetCell(i, 1, util.CellFormat(util.FormatInitials(pp.Creator.DisplayName), theme.Current.Field))                // Initial
+		pt.SetCell(i, 2, util.CellFormat(fmt.Sprintf("%s %d", constants.ICON_BUILD, pp.BuildNumber), theme.Current.Faint)) // Build #
+		pt.SetCell(i, 4, util.CellFormat(fmt.Sprintf(" %s", shortHash), theme.Current.Faint))                             // Commit
+		pt.SetCell(i, 5, util.CellFormat(fmt.Sprintf(" %s", pp.Target.RefName), theme.Current.Faint))                     // Branch
 		pt.SetCell(i, 6, util.CellFormat(fmt.Sprintf("%s %s", statusIcon, status), statusColor))                           // Status
-		pt.SetCell(i, 9, util.CellFormat(durationStr, tcell.ColorDarkGray))                                                // Duration
-		pt.SetCell(i, 11, util.CellFormat(startStr, tcell.ColorDarkGray))                                                  // Started
+		pt.SetCell(i, 9, util.CellFormat(durationStr, theme.Current.Faint))                                                // Duration
+		pt.SetCell(i, 11, util.CellFormat(startStr, theme.Current.Faint))                                                  // Started
 	}
-	pt.SetSelectedStyle(tcell.StyleDefault.Foreground(tcell.ColorDarkOrange))
+	pt.SetSelectedStyle(tcell.StyleDefault.Foreground(theme.Current.Selected))
 	pt.Table.SetSelectable(true, false)
 }
 
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log"
 	"simple-git-terminal/constants"
+	"simple-git-terminal/theme"
 	"simple-git-terminal/types"
 	"simple-git-terminal/util"
 	widgets "simple-git-terminal/widgets/table"
@@ -43,7 +44,7 @@ func (stepTable *StepsTable) SetSteps(steps []types.StepDetail, frame int) {
 	stepTable.Clear()
 
 	if len(steps) == 0 {
-		stepTable.SetCell(0, 0, util.CellFormat(" No steps available", tcell.ColorGray))
+		stepTable.SetCell(0, 0, util.CellFormat(" No steps available", theme.Current.Muted))
 		return
 	}
 
@@ -65,7 +66,7 @@ func (stepTable *StepsTable) SetSteps(steps []types.StepDetail, frame int) {
 		}
 
 		if i == stepTable.SelectedRow {
-			selectedCell := util.CellFormat(constants.ICON_SELECTED, tcell.ColorOrange)
+			selectedCell := util.CellFormat(constants.ICON_SELECTED, theme.Current.Accent)
 			stepTable.SetCell(i, 0, selectedCell)
 		} else {
 			// Clear selection icon for other rows
@@ -76,7 +77,7 @@ func (stepTable *StepsTable) SetSteps(steps []types.StepDetail, frame int) {
 		stepTable.SetCell(i, 1, iconCell)
 
 		// Name cell (col 1)
-		nameCell := util.CellFormat(step.Name, tcell.ColorWhite)
+		nameCell := util.CellFormat(step.Name, theme.Current.Text)
 		stepTable.SetCell(i, 2, nameCell)
 
 	}
@@ -165,7 +166,7 @@ func (stepTable *StepsTable) PatchSteps(newSteps []types.StepDetail, frame int)
 		// Update name cell if the step name changed
 		if oldStep.Name != newStep.Name {
 			log.Printf("[PatchSteps] Step name changed for UUID: %s, updating name cell at row %d", newStep.UUID, row)
-			nameCell := util.CellFormat(newStep.Name, tcell.ColorWhite)
+			nameCell := util.CellFormat(newStep.Name, theme.Current.Text)
 			stepTable.SetCell(row, 2, nameCell)
 		} else {
 			log.Printf("[PatchSteps] Name for step UUID: %s unchanged", newStep.UUID)
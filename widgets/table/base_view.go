@@ -3,6 +3,7 @@ package widgets
 import (
 	"log"
 	"simple-git-terminal/constants"
+	"simple-git-terminal/theme"
 	"simple-git-terminal/util"
 	"strings"
 
@@ -67,7 +68,7 @@ func (b *BaseTableView) SetLoadingCell(cell *tview.TableCell) {
 		text = text[:idx] // keep everything before "Loading..." // FIXME: Super hacky for now
 	}
 	cell.SetText(text).
-		SetTextColor(tcell.ColorOrange)
+		SetTextColor(theme.Current.Accent)
 
 	b.Table.SetCell(0, 0, cell)
 }
@@ -102,7 +103,7 @@ func (b *BaseTableView) UpdateSelectedRow(row int) {
 
 	log.Println("[UpdateSelectedRow] Selecting row:", row)
 	b.SelectedRow = row
-	b.SetCell(row, 0, util.CellFormat(constants.ICON_SELECTED, tcell.ColorOrange))
+	b.SetCell(row, 0, util.CellFormat(constants.ICON_SELECTED, theme.Current.Accent))
 }
 
 func (b *BaseTableView) UpdateUnSelectedRow(row int) {